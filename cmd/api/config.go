@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/config"
+)
+
+const configUsage = `usage: api config <command>
+
+commands:
+  print   show the effective configuration with secrets redacted
+  check   validate the configuration and exit non-zero on problems
+`
+
+// runConfig implements the `config` subcommand and returns the exit code.
+func runConfig(cfg *config.Config, args []string) int {
+	if len(args) == 0 || (args[0] != "print" && args[0] != "check") {
+		fmt.Fprint(os.Stderr, configUsage)
+		return 2
+	}
+
+	if args[0] == "print" {
+		cfg.Print(os.Stdout)
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return 1
+	}
+	if args[0] == "check" {
+		fmt.Println("configuration is valid")
+	}
+	return 0
+}
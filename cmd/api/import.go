@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/internal/repository"
+	"github.com/HIUNCY/sagara-booking-api/internal/service"
+	"github.com/HIUNCY/sagara-booking-api/pkg/config"
+	"github.com/HIUNCY/sagara-booking-api/pkg/database"
+)
+
+const importUsage = `usage: api import [-dry-run] [-fields <file.csv>] [-bookings <file.csv>]
+
+Imports fields and bookings from CSV, all rows or none. With -dry-run the
+files are only checked. See the README for the columns.
+`
+
+// runImport implements the `import` subcommand and returns the exit code:
+// 1 when the files have errors or the import failed.
+func runImport(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, importUsage) }
+	dryRun := flags.Bool("dry-run", false, "only check the files")
+	fieldsPath := flags.String("fields", "", "fields CSV file")
+	bookingsPath := flags.String("bookings", "", "bookings CSV file")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || (*fieldsPath == "" && *bookingsPath == "") {
+		fmt.Fprint(os.Stderr, importUsage)
+		return 2
+	}
+
+	req := &port.ImportRequest{DryRun: *dryRun}
+	for _, upload := range []struct {
+		path string
+		into *io.Reader
+	}{{*fieldsPath, &req.Fields}, {*bookingsPath, &req.Bookings}} {
+		if upload.path == "" {
+			continue
+		}
+		file, err := os.Open(upload.path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "import error: %v\n", err)
+			return 1
+		}
+		defer file.Close()
+		*upload.into = file
+	}
+
+	db, err := database.ConnectDB(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "database error: %v\n", err)
+		return 1
+	}
+	defer database.Close(db)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migration error: %v\n", err)
+		return 1
+	}
+	if err := migrator.CheckCurrent(); err != nil {
+		fmt.Fprintf(os.Stderr, "refusing to import: %v\n", err)
+		return 1
+	}
+
+	importer := service.NewImportService(repository.NewImportRepository(db), repository.NewBookingRepository(db),
+		repository.NewFieldRepository(db), repository.NewUserRepository(db), port.ClockFunc(time.Now), cfg.DefaultTimeZone)
+	result, err := importer.Import(context.Background(), req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+	return printImportResult(result, *fieldsPath, *bookingsPath)
+}
+
+func printImportResult(result *domain.ImportResult, fieldsPath, bookingsPath string) int {
+	paths := map[string]string{domain.ImportFileFields: fieldsPath, domain.ImportFileBookings: bookingsPath}
+	for _, e := range result.Errors {
+		where := paths[e.File]
+		if e.Row > 0 {
+			where += fmt.Sprintf(" line %d", e.Row)
+		}
+		if e.Column != "" {
+			where += " (" + e.Column + ")"
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", where, e.Message)
+	}
+	switch {
+	case len(result.Errors) > 0:
+		fmt.Fprintf(os.Stderr, "%d error(s); nothing was imported\n", len(result.Errors))
+		return 1
+	case result.DryRun:
+		fmt.Printf("dry run: would import %d field(s) and %d booking(s)\n", result.Fields, result.Bookings)
+	default:
+		fmt.Printf("imported %d field(s) and %d booking(s)\n", result.Fields, result.Bookings)
+	}
+	return 0
+}
@@ -1,20 +1,43 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// Field time zones must resolve in the alpine image, which has no
+	// zoneinfo of its own.
+	_ "time/tzdata"
 
 	_ "github.com/HIUNCY/sagara-booking-api/docs"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
 	"github.com/HIUNCY/sagara-booking-api/internal/handler"
+	"github.com/HIUNCY/sagara-booking-api/internal/notification"
+	"github.com/HIUNCY/sagara-booking-api/internal/outbox"
 	"github.com/HIUNCY/sagara-booking-api/internal/repository"
 	"github.com/HIUNCY/sagara-booking-api/internal/service"
+	"github.com/HIUNCY/sagara-booking-api/internal/webhook"
+	"github.com/HIUNCY/sagara-booking-api/pkg/config"
 	"github.com/HIUNCY/sagara-booking-api/pkg/database"
+	"github.com/HIUNCY/sagara-booking-api/pkg/health"
+	"github.com/HIUNCY/sagara-booking-api/pkg/idempotency"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/HIUNCY/sagara-booking-api/pkg/metrics"
 	"github.com/HIUNCY/sagara-booking-api/pkg/middleware"
+	"github.com/HIUNCY/sagara-booking-api/pkg/ratelimit"
+	"github.com/HIUNCY/sagara-booking-api/pkg/server"
+	"github.com/HIUNCY/sagara-booking-api/pkg/tracing"
+	"github.com/HIUNCY/sagara-booking-api/pkg/util"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	swagger "github.com/gofiber/swagger"
-	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // @title           Sagara Booking API
@@ -31,38 +54,242 @@ import (
 // @in header
 // @name Authorization
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found, relying on Heroku Config Vars.")
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Config error:\n%v", err)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+		case "migrate":
+			os.Exit(runMigrate(cfg, os.Args[2:]))
+		case "config":
+			os.Exit(runConfig(cfg, os.Args[2:]))
+		case "import":
+			os.Exit(runImport(cfg, os.Args[2:]))
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\nusage: api [serve | migrate <up|down|status|to> | config <print|check> | import]\n", os.Args[1])
+			os.Exit(2)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
+	os.Exit(serve(cfg))
+}
 
-	db, err := database.ConnectDB()
+// serve runs the API until SIGINT/SIGTERM and returns the process exit code.
+func serve(cfg *config.Config) int {
+	level, _ := logging.ParseLevel(cfg.LogLevel)
+	slog.SetDefault(logging.New(os.Stdout, level))
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
-		log.Fatalf("Database error: %v", err)
+		slog.Error("tracing error", "error", err)
+		return 1
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("flushing traces", "error", err)
+		}
+	}()
+
+	db, err := database.ConnectDB(cfg.Database)
+	if err != nil {
+		slog.Error("database error", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := database.Close(db); err != nil {
+			slog.Error("closing database", "error", err)
+		}
+	}()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		slog.Error("migration error", "error", err)
+		return 1
+	}
+	if err := migrator.CheckCurrent(); err != nil {
+		slog.Error("refusing to start", "error", err)
+		return 1
+	}
+
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		slog.Error("tracing error", "error", err)
+		return 1
+	}
+
+	// METRICS
+	appMetrics := metrics.New(prometheus.NewRegistry())
+	if err := appMetrics.RegisterDB(db); err != nil {
+		slog.Error("metrics error", "error", err)
+		return 1
 	}
 
+	// HEALTH CHECKS
+	checker := health.NewChecker(2 * time.Second)
+	checker.Add("database", health.DatabasePing(db))
+	checker.Add("migrations", migrator.CheckApplied)
+	healthHandler := handler.NewHealthHandler(checker)
+
+	// BACKGROUND WORKERS
+	// Each worker registers a heartbeat check so /readyz notices a stalled loop.
+	workers := server.NewWorkers()
+
+	tokens := util.NewTokenManager(cfg.JWT.Secret, cfg.JWT.TTL)
+	protected := middleware.Protected(tokens)
+
 	// USER FEATURE
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, tokens,
+		util.NewLoginGuard(util.DefaultIPPolicy),
+		util.NewLoginGuard(util.DefaultAccountPolicy),
+	)
 	userHandler := handler.NewUserHandler(userService)
 
 	// FIELD FEATURE
 	fieldRepo := repository.NewFieldRepository(db)
-	fieldService := service.NewFieldService(fieldRepo)
+	fieldService := service.NewFieldService(fieldRepo, port.ClockFunc(time.Now), cfg.DefaultTimeZone)
 	fieldHandler := handler.NewFieldHandler(fieldService)
 
 	// BOOKING FEATURE
 	bookingRepo := repository.NewBookingRepository(db)
-	bookingService := service.NewBookingService(bookingRepo)
-	bookingHandler := handler.NewBookingHandler(bookingService)
+	bookingService := service.NewBookingService(bookingRepo, fieldRepo, port.ClockFunc(time.Now))
+	bookingHandler := handler.NewBookingHandler(bookingService, appMetrics)
+	frontDeskService := service.NewFrontDeskService(bookingRepo, fieldRepo, userRepo, port.ClockFunc(time.Now))
+	frontDeskHandler := handler.NewFrontDeskHandler(frontDeskService, appMetrics)
+
+	// WAITLIST FEATURE
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	waitlistService := service.NewWaitlistService(waitlistRepo, bookingRepo, fieldRepo, port.ClockFunc(time.Now), cfg.WaitlistHold)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService)
+
+	// CHECKOUT HOLDS
+	holdRepo := repository.NewHoldRepository(db)
+	holdService := service.NewHoldService(holdRepo, bookingService, fieldRepo, port.ClockFunc(time.Now), cfg.SlotHoldMax)
+	holdHandler := handler.NewHoldHandler(holdService, appMetrics)
+
+	// NOTIFICATIONS
+	sink, err := newNotificationSink(cfg.Notification)
+	if err != nil {
+		slog.Error("notification error", "error", err)
+		return 1
+	}
+	defaultTZ, _ := time.LoadLocation(cfg.DefaultTimeZone) // validated with the config
+	preferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	bookingNotifier := notification.NewBookingNotifier(userRepo, fieldRepo, preferenceRepo, sink, notification.Options{
+		DefaultLanguage: cfg.Notification.Language,
+		Location:        defaultTZ,
+		PaymentWindow:   cfg.BookingPaymentWindow,
+	})
+	notificationHandler := handler.NewNotificationHandler(
+		service.NewNotificationPreferenceService(preferenceRepo, cfg.Notification.Language))
+
+	// CALENDAR FEEDS
+	calendarService := service.NewCalendarService(repository.NewCalendarRepository(db), bookingRepo, fieldRepo, userRepo, port.ClockFunc(time.Now))
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+
+	// REPORTS
+	reportService := service.NewReportService(repository.NewReportRepository(db), fieldRepo, port.ClockFunc(time.Now), defaultTZ)
+	reportHandler := handler.NewReportHandler(reportService)
+
+	// IMPORT
+	importService := service.NewImportService(repository.NewImportRepository(db), bookingRepo, fieldRepo, userRepo,
+		port.ClockFunc(time.Now), cfg.DefaultTimeZone)
+	importHandler := handler.NewImportHandler(importService)
 
-	app := fiber.New()
-	app.Use(logger.New())
+	// WEBHOOK FEATURE
+	webhookService := service.NewWebhookService(repository.NewWebhookRepository(db))
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	webhookDeliveries := repository.NewWebhookDeliveryRepository(db)
+	sender := webhook.NewSender(webhookDeliveries, webhook.Options{})
+	senderBeat := health.NewHeartbeat(5 * time.Minute)
+	checker.Add("webhooks", senderBeat.Check)
+	workers.Go("webhooks", func(ctx context.Context) { sender.Run(ctx, senderBeat.Beat) })
+
+	// DOMAIN EVENTS
+	dispatcher := outbox.NewDispatcher(repository.NewOutboxRepository(db), outbox.Options{})
+	for _, eventType := range notification.EventTypes() {
+		dispatcher.Subscribe(eventType, "notifications", notification.EventHandler(bookingNotifier))
+	}
+	dispatcher.Subscribe(domain.EventBookingReminderDue, "notifications", notification.ReminderHandler(bookingRepo, bookingNotifier))
+	dispatcher.Subscribe(domain.EventWaitlistOffered, "notifications", notification.WaitlistHandler(bookingNotifier))
+	for _, eventType := range service.WaitlistEventTypes {
+		dispatcher.Subscribe(eventType, "waitlist", service.WaitlistEventHandler(waitlistService))
+	}
+	for _, eventType := range domain.WebhookEventTypes {
+		dispatcher.Subscribe(eventType, "webhooks", webhook.Fanout(webhookDeliveries))
+	}
+	outboxBeat := health.NewHeartbeat(5 * time.Minute)
+	checker.Add("outbox", outboxBeat.Check)
+	workers.Go("outbox", func(ctx context.Context) { dispatcher.Run(ctx, outboxBeat.Beat) })
+
+	holdExpirer := service.NewSlotHoldExpirer(holdRepo, port.ClockFunc(time.Now))
+	holdBeat := health.NewHeartbeat(5 * time.Minute)
+	checker.Add("slot_holds", holdBeat.Check)
+	workers.Go("slot_holds", func(ctx context.Context) { holdExpirer.Run(ctx, time.Minute, holdBeat.Beat) })
+
+	offerExpirer := service.NewWaitlistOfferExpirer(waitlistRepo, port.ClockFunc(time.Now))
+	offerBeat := health.NewHeartbeat(5 * time.Minute)
+	checker.Add("waitlist_offers", offerBeat.Check)
+	workers.Go("waitlist_offers", func(ctx context.Context) { offerExpirer.Run(ctx, time.Minute, offerBeat.Beat) })
+
+	if cfg.BookingPaymentWindow > 0 {
+		expirer := service.NewBookingExpirer(bookingRepo, appMetrics, cfg.BookingPaymentWindow, port.ClockFunc(time.Now))
+		expiryBeat := health.NewHeartbeat(5 * time.Minute)
+		checker.Add("booking_expiry", expiryBeat.Check)
+		workers.Go("booking_expiry", func(ctx context.Context) { expirer.Run(ctx, time.Minute, expiryBeat.Beat) })
+	}
+
+	if len(cfg.BookingReminderOffsets) > 0 {
+		reminders := service.NewBookingReminderScheduler(repository.NewBookingReminderRepository(db), cfg.BookingReminderOffsets)
+		reminderBeat := health.NewHeartbeat(5 * time.Minute)
+		checker.Add("booking_reminders", reminderBeat.Check)
+		workers.Go("booking_reminders", func(ctx context.Context) { reminders.Run(ctx, time.Minute, reminderBeat.Beat) })
+	}
+
+	// RATE LIMITING
+	var rateStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Store == "postgres" {
+		rateStore = ratelimit.NewPostgresStore(db)
+	}
+	authLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "auth", Limit: ratelimit.Every(20, time.Minute, 10), Store: rateStore,
+	})
+	bookingLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "bookings", Limit: ratelimit.Every(10, time.Minute, 5), Store: rateStore,
+	})
+	paymentLimit := middleware.RateLimit(middleware.RateLimitPolicy{
+		Name: "payments", Limit: ratelimit.Every(5, time.Minute, 3), Store: rateStore,
+	})
+
+	// IDEMPOTENCY
+	idempotent := middleware.Idempotency(idempotency.NewPostgresStore(db))
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	app.Use(middleware.RequestID())
+	app.Use(tracing.Middleware())
+	app.Use(appMetrics.Middleware())
+	app.Use(middleware.RealIP(cfg.TrustProxy))
+	app.Use(middleware.AccessLog())
+	app.Use(middleware.Timeout(cfg.RequestTimeout))
 	app.Use(cors.New())
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Sagara Backend Test API is Running!")
 	})
 
+	// PROBES
+	app.Get("/healthz", healthHandler.Liveness)
+	app.Get("/readyz", healthHandler.Readiness)
+	app.Get("/version", healthHandler.Version)
+	app.Get("/metrics", appMetrics.Handler(cfg.MetricsToken))
+
 	// SWAGGER ROUTES
 	app.Get("/swagger/*", swagger.New(swagger.Config{
 		URL: "/swagger/doc.json",
@@ -71,28 +298,110 @@ func main() {
 	api := app.Group("/api")
 
 	// AUTH ROUTES
-	api.Post("/register", userHandler.Register)
-	api.Post("/login", userHandler.Login)
+	api.Post("/register", authLimit, userHandler.Register)
+	api.Post("/login", authLimit, userHandler.Login)
 
 	// FIELD ROUTES
-	fields := api.Group("/fields", middleware.Protected)
+	fields := api.Group("/fields", protected)
 	fields.Get("/", fieldHandler.GetAll)
 	fields.Get("/:id", fieldHandler.GetByID)
+	fields.Get("/:id/availability", bookingHandler.GetAvailability)
 	fields.Post("/", middleware.AdminOnly, fieldHandler.Create)
 	fields.Put("/:id", middleware.AdminOnly, fieldHandler.Update)
 	fields.Delete("/:id", middleware.AdminOnly, fieldHandler.Delete)
 
 	// BOOKING AND PAYMENT ROUTES
-	bookings := api.Group("/bookings", middleware.Protected)
+	bookings := api.Group("/bookings", protected)
 	bookings.Get("/", bookingHandler.GetAll)
 	bookings.Get("/:id", bookingHandler.GetByID)
-	bookings.Post("/", bookingHandler.Create)
-	api.Post("/payments", middleware.Protected, bookingHandler.Pay)
+	bookings.Get("/:id/ics", calendarHandler.Booking)
+	bookings.Post("/", bookingLimit, idempotent, bookingHandler.Create)
+	api.Post("/payments", protected, paymentLimit, idempotent, bookingHandler.Pay)
+
+	// HOLD ROUTES
+	holds := api.Group("/holds", protected)
+	holds.Get("/", holdHandler.GetMine)
+	holds.Post("/", bookingLimit, holdHandler.Create)
+	holds.Delete("/:id", holdHandler.Delete)
+	holds.Post("/:id/checkout", bookingLimit, idempotent, holdHandler.Checkout)
+
+	// WAITLIST ROUTES
+	waitlist := api.Group("/waitlist", protected)
+	waitlist.Get("/", waitlistHandler.GetMine)
+	waitlist.Post("/", bookingLimit, waitlistHandler.Join)
+	waitlist.Delete("/:id", waitlistHandler.Leave)
+
+	// CALENDAR ROUTES
+	// Feeds are read by calendar apps, which cannot log in; the token in
+	// the URL stands in for it.
+	api.Get("/calendar/:token.ics", calendarHandler.Feed)
+	api.Post("/calendar-feed", protected, calendarHandler.CreateMyFeed)
+	api.Delete("/calendar-feed", protected, calendarHandler.DeleteMyFeed)
+
+	// NOTIFICATION PREFERENCE ROUTES
+	api.Get("/notification-preferences", protected, notificationHandler.Get)
+	api.Put("/notification-preferences", protected, notificationHandler.Update)
+
+	// ADMIN ROUTES
+	admin := api.Group("/admin", protected, middleware.AdminOnly)
+	admin.Post("/bookings", idempotent, frontDeskHandler.CreateBooking)
+	admin.Get("/bookings/export", bookingHandler.Export)
+	admin.Post("/bookings/:id/no-show", bookingHandler.MarkNoShow)
+	admin.Post("/import", importHandler.Import)
+	admin.Post("/fields/:id/calendar-feed", calendarHandler.CreateFieldFeed)
+	admin.Delete("/fields/:id/calendar-feed", calendarHandler.DeleteFieldFeed)
+	reports := admin.Group("/reports")
+	reports.Get("/revenue", reportHandler.Revenue)
+	reports.Get("/utilization", reportHandler.Utilization)
+	reports.Get("/heatmap", reportHandler.Heatmap)
+	reports.Get("/cancellations", reportHandler.Cancellations)
+	reports.Get("/top-customers", reportHandler.TopCustomers)
+	webhooks := admin.Group("/webhooks")
+	webhooks.Get("/", webhookHandler.GetAll)
+	webhooks.Post("/", webhookHandler.Create)
+	webhooks.Get("/:id", webhookHandler.GetByID)
+	webhooks.Put("/:id", webhookHandler.Update)
+	webhooks.Delete("/:id", webhookHandler.Delete)
+	webhooks.Get("/:id/deliveries", webhookHandler.ListDeliveries)
+	webhooks.Get("/:id/deliveries/:deliveryId", webhookHandler.GetDelivery)
+	webhooks.Post("/:id/deliveries/:deliveryId/redeliver", webhookHandler.Redeliver)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	ln, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		slog.Error("listen error", "error", err)
+		return 1
 	}
 
-	log.Fatal(app.Listen(":" + port))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exitCode := 0
+	if err := server.Run(ctx, app, ln, cfg.ShutdownTimeout); err != nil {
+		slog.Error("server error", "error", err)
+		exitCode = 1
+	}
+	if err := workers.Stop(5 * time.Second); err != nil {
+		slog.Error("shutdown error", "error", err)
+		exitCode = 1
+	}
+	slog.Info("shutdown complete")
+	return exitCode
+}
+
+// newNotificationSink picks where customer email goes.
+func newNotificationSink(cfg config.NotificationConfig) (port.Notifier, error) {
+	switch cfg.Sink {
+	case "smtp":
+		return notification.NewSMTPNotifier(notification.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.From,
+		})
+	case "file":
+		return notification.NewFileNotifier(cfg.FileDir, cfg.From)
+	default:
+		return notification.NewLogNotifier(), nil
+	}
 }
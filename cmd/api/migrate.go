@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/config"
+	"github.com/HIUNCY/sagara-booking-api/pkg/database"
+)
+
+const migrateUsage = `usage: api migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [n]       roll back the last n migrations (default 1)
+  status         list migrations and whether they are applied
+  to <version>   migrate up or down to exactly <version> (0 rolls back everything)
+`
+
+// runMigrate implements the `migrate` subcommand and returns the exit code.
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := database.ConnectDB(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "database error: %v\n", err)
+		return 1
+	}
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migration error: %v\n", err)
+		return 1
+	}
+
+	var done []database.Migration
+	switch args[0] {
+	case "up":
+		done, err = migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintf(os.Stderr, "invalid step count %q\n", args[1])
+				return 2
+			}
+		}
+		done, err = migrator.Down(steps)
+	case "to":
+		if len(args) < 2 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			return 2
+		}
+		version, perr := strconv.ParseInt(args[1], 10, 64)
+		if perr != nil {
+			fmt.Fprintf(os.Stderr, "invalid version %q\n", args[1])
+			return 2
+		}
+		done, err = migrator.To(version)
+	case "status":
+		return printMigrationStatus(migrator)
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	for _, m := range done {
+		fmt.Printf("migrated %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migration failed: %v\n", err)
+		return 1
+	}
+	if len(done) == 0 {
+		fmt.Println("nothing to migrate")
+	}
+	return 0
+}
+
+func printMigrationStatus(migrator *database.Migrator) int {
+	statuses, err := migrator.Status()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migration error: %v\n", err)
+		return 1
+	}
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, applied)
+	}
+	return 0
+}
@@ -23,43 +23,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/bookings": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieve a list of all bookings (Admin/User).",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Bookings"
-                ],
-                "summary": "Get all bookings history",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/port.DataResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/port.ErrorResponse"
-                        }
-                    }
-                }
-            },
+        "/admin/bookings": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Book a field. Checks for schedule overlap.",
+                "description": "Takes a phone or walk-in booking for an existing user (user_id) or a guest (name and phone; guests\nwith the same phone number are one customer, and get no emails). With payment_method \"cash\" the\nbooking is paid at the counter straight away. The start-in-the-future and opening-hours rules can be\nwaived with overrides \"past_start\" and \"opening_hours\" and an override_reason; the admin, overrides\nand reason are recorded on the booking. Even so, a started slot can be booked at most 24h after it\nstarted, and a booking must end by midnight of the day it starts. Overlapping bookings and other\ncustomers' holds are never allowed. There is no staff role; front desk staff use admin accounts.",
                 "consumes": [
                     "application/json"
                 ],
@@ -69,7 +35,7 @@ const docTemplate = `{
                 "tags": [
                     "Bookings"
                 ],
-                "summary": "Create a new booking",
+                "summary": "Book for a customer (Admin Only)",
                 "parameters": [
                     {
                         "description": "Booking Data",
@@ -77,8 +43,14 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/port.BookingRequest"
+                            "$ref": "#/definitions/port.FrontDeskBookingRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key; retries with the same key replay the first response for 24h",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -89,7 +61,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid Input / Overlap",
+                        "description": "Invalid Input",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
@@ -99,73 +71,29 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/bookings/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get detailed information about a specific booking by ID.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Bookings"
-                ],
-                "summary": "Get booking details",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Booking ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/port.DataResponse"
+                            "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Field Or User Not Found",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/fields": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieve a list of all available sports fields.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Fields"
-                ],
-                "summary": "Get All Fields",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Schedule Overlap / Idempotency-Key In Progress",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/port.FieldResponse"
-                            }
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key Reused With Different Body",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
                     "500": {
@@ -174,51 +102,84 @@ const docTemplate = `{
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Add a new sports field to the system. Requires Admin role.",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/bookings/export": {
+            "get": {
+                "description": "Download bookings with their field, customer, amount paid, payment reference and status times as\nCSV or Excel, by start time, with the same filters as the booking list. Rows are streamed as they are\nread. Times are UTC except local_start, in the field's time zone.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Fields"
+                    "Bookings"
                 ],
-                "summary": "Create New Field (Admin Only)",
+                "summary": "Export bookings (Admin Only)",
                 "parameters": [
                     {
-                        "description": "Field Data",
-                        "name": "field",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/port.CreateFieldRequest"
-                        }
+                        "type": "string",
+                        "description": "csv (default) or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, paid, cancelled, expired or no_show",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this customer",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Starting on or after, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Starting on or before, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/port.MessageResponse"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid Input",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
                     "403": {
-                        "description": "Forbidden: Admin only",
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
@@ -229,28 +190,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     }
-                }
-            }
-        },
-        "/fields/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get detailed information of a specific field by ID.",
+                ]
+            }
+        },
+        "/admin/bookings/{id}/no-show": {
+            "post": {
+                "description": "Record that the customer of a paid booking did not turn up, once it has started. The slot stays\npaid for; the booking counts as a no-show in reports. Marking it again is harmless.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fields"
+                    "Bookings"
                 ],
-                "summary": "Get Field Detail",
+                "summary": "Mark a booking as a no-show (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Field ID",
+                        "description": "Booking ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -260,7 +221,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/port.FieldResponse"
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
                     "404": {
@@ -268,26 +247,37 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Booking Not Paid Or Not Started",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update existing field data. Requires Admin role.",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/fields/{id}/calendar-feed": {
+            "post": {
+                "description": "Returns a secret iCalendar URL of every booking on the field, named after the customers, for the\nfront desk. Each call issues a new URL and the old one stops working.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fields"
+                    "Calendar"
                 ],
-                "summary": "Update Field (Admin Only)",
+                "summary": "Get a calendar feed URL for a field (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -295,22 +285,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated Data",
-                        "name": "field",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/port.CreateFieldRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/port.MessageResponse"
+                            "$ref": "#/definitions/port.DataResponse"
                         }
                     },
                     "400": {
@@ -319,34 +300,45 @@ const docTemplate = `{
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Field Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Permanently remove a field. Requires Admin role.",
+                ]
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Fields"
+                    "Calendar"
                 ],
-                "summary": "Delete Field (Admin Only)",
+                "summary": "Revoke a field's calendar feed URL (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -363,8 +355,1903 @@ const docTemplate = `{
                             "$ref": "#/definitions/port.MessageResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/import": {
+            "post": {
+                "description": "Upload a fields file, a bookings file or both. Every row is checked first, bookings with the rules of\nbooking creation except that they may be in the past, and either all rows are imported or none.\nWith dry_run=true nothing is imported and the row errors are reported. Fields have the columns name,\nprice_per_hour, location, time_zone, open_time and close_time. Bookings have field_id or field_name,\nuser_email, start_time and end_time (RFC 3339, or YYYY-MM-DD HH:MM in the field's time zone), status\n(paid by default, cancelled or no_show), amount_paid and payment_reference. Imported bookings send no\nnotifications or webhooks.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Import"
+                ],
+                "summary": "Import fields and bookings from CSV (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Fields CSV",
+                        "name": "fields",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Bookings CSV",
+                        "name": "bookings",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only check the files",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry Run",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Imported",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Overlapping Booking Made Meanwhile",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Row Errors, Nothing Imported",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/reports/cancellations": {
+            "get": {
+                "description": "Each field's bookings by outcome, with a final total row (field_id 0). The cancellation rate is\nover all bookings, the no-show rate over confirmed ones.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Cancellation and no-show report (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default 29 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/reports/heatmap": {
+            "get": {
+                "description": "Confirmed bookings by the weekday and local hour they start, all 7x24 cells from Monday 00:00.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Peak-hour heatmap (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default 29 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/reports/revenue": {
+            "get": {
+                "description": "Confirmed (paid and no-show) bookings and their revenue per local day, week or month, by field,\nvenue (field location) or overall. Revenue uses each field's current hourly price. Bookings count\non the day they start in their field's time zone.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Revenue report (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default 29 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day (default), week or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "field (default), venue or all",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/reports/top-customers": {
+            "get": {
+                "description": "The customers with the most confirmed revenue over the range.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Top customers (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default 29 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "How many, 1-100 (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/reports/utilization": {
+            "get": {
+                "description": "Each current field's confirmed hours as a percentage of its opening hours over the range.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Utilization report (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default 29 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Field Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List Webhook Subscriptions (Admin Only)",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Subscribe a partner URL to booking events. Deliveries are signed with the secret, which is\ngenerated when omitted and only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create Webhook Subscription (Admin Only)",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/port.DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/port.WebhookSecretResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Webhook Subscription (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replace the URL, event types and description. A non-empty secret rotates it; active=true\nre-enables a subscription that was disabled after repeated failures.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update Webhook Subscription (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/port.DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/port.WebhookSecretResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove a subscription together with its delivery log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete Webhook Subscription (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "The subscription's most recent deliveries, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List Webhook Deliveries (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum deliveries (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks/{id}/deliveries/{deliveryId}": {
+            "get": {
+                "description": "A delivery with the log of every attempt: response status, truncated body, error and duration.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Webhook Delivery (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/admin/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "description": "Queue a delivery to be sent again with a fresh set of retries, whatever its current state.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Redeliver Webhook (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/port.DataResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/domain.WebhookDelivery"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Webhook is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/bookings": {
+            "get": {
+                "description": "Retrieve a list of all bookings (Admin/User), newest first. Dates are the local days bookings start\non in their field's time zone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Get all bookings history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, paid, cancelled, expired or no_show",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this field",
+                        "name": "field_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only this customer",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Starting on or after, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Starting on or before, YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Book a field. Checks for schedule overlap.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Create a new booking",
+                "parameters": [
+                    {
+                        "description": "Booking Data",
+                        "name": "booking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.BookingRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key; retries with the same key replay the first response for 24h",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Schedule Overlap / Idempotency-Key In Progress",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key Reused With Different Body",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate Limited",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/bookings/{id}": {
+            "get": {
+                "description": "Get detailed information about a specific booking by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Get booking details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/bookings/{id}/ics": {
+            "get": {
+                "description": "One booking as an iCalendar event to add to a calendar. Customers can download their own bookings,\nadmins any.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Download a booking as an .ics file",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/calendar-feed": {
+            "post": {
+                "description": "Returns a secret iCalendar URL of your bookings to subscribe to in Google Calendar, Outlook or Apple\nCalendar. Anyone with the URL can read it, so each call issues a new one and the old URL stops working.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Get a calendar feed URL for my bookings",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Revoke my calendar feed URL",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/calendar/{token}.ics": {
+            "get": {
+                "description": "The iCalendar feed a secret URL opens: a customer's or a field's bookings from 90 days ago on, with\ncancelled and expired ones marked cancelled. Needs no login; the token in the URL is the credential.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Calendar"
+                ],
+                "summary": "Calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown Or Revoked Feed",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fields": {
+            "get": {
+                "description": "Retrieve a list of all available sports fields.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fields"
+                ],
+                "summary": "Get All Fields",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/port.FieldResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Add a new sports field to the system. Requires Admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fields"
+                ],
+                "summary": "Create New Field (Admin Only)",
+                "parameters": [
+                    {
+                        "description": "Field Data",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.CreateFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden: Admin only",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/fields/{id}": {
+            "get": {
+                "description": "Get detailed information of a specific field by ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fields"
+                ],
+                "summary": "Get Field Detail",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Field ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.FieldResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Update existing field data. Requires Admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fields"
+                ],
+                "summary": "Update Field (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Field ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Data",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.CreateFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove a field. Requires Admin role. With policy=block (default) the request fails while the field\nhas upcoming bookings; with policy=cascade those bookings are cancelled and their owners notified.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fields"
+                ],
+                "summary": "Delete Field (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Field ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "block",
+                            "cascade"
+                        ],
+                        "type": "string",
+                        "description": "Deletion policy",
+                        "name": "policy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Field has upcoming bookings",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/fields/{id}/availability": {
+            "get": {
+                "description": "The field's opening hours on a local day with the intervals taken by bookings and unexpired\nholds, and the free ones in between. Times are UTC; date is in the field's time zone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Get a field's availability for a day",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Field ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Local date (YYYY-MM-DD), today by default",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/holds": {
+            "get": {
+                "description": "Your unexpired holds, soonest to expire first, including slots offered from the waitlist.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "List my holds",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Reserve a field interval for up to SLOT_HOLD_MAX while paying, so nobody else can book or hold\nit. Holding an overlapping slot again replaces your earlier hold; at most 3 holds at a time.\nUnused holds are released when they expire.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Hold a slot during checkout",
+                "parameters": [
+                    {
+                        "description": "Slot to hold",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.HoldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Field Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Slot Booked Or Held / Too Many Holds",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate Limited",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/holds/{id}": {
+            "delete": {
+                "description": "Free the slot straight away. Releasing a slot offered from the waitlist declines the offer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Release a hold",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/holds/{id}/checkout": {
+            "post": {
+                "description": "Turn an unexpired hold into a pending booking, consuming the hold. Pay for it with /payments.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Holds"
+                ],
+                "summary": "Book a held slot",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Hold ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key; retries with the same key replay the first response for 24h",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Hold Expired / Schedule Overlap",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate Limited",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
@@ -375,7 +2262,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
         "/login": {
@@ -420,18 +2312,111 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
+                    },
+                    "429": {
+                        "description": "Too Many Failed Attempts",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/payments": {
-            "post": {
+        "/notification-preferences": {
+            "get": {
+                "description": "The language of booking emails and whether optional messages such as reminders are sent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Get my notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.NotificationPreference"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            },
+            "put": {
+                "description": "Choose the email language (id or en) and opt out of non-transactional messages. Booking\nconfirmations, payment receipts, cancellations and expiries are always sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Update my notification preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "preference",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.NotificationPreferenceRequest"
+                        }
+                    }
                 ],
-                "description": "Change booking status from pending to paid.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.NotificationPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/payments": {
+            "post": {
+                "description": "Change booking status from pending to paid, recording the amount at the field's current price and\nthe payment reference (generated when omitted) for reconciliation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -444,84 +2429,423 @@ const docTemplate = `{
                 "summary": "Pay for a booking (Mock Payment)",
                 "parameters": [
                     {
-                        "description": "JSON: {booking_id: 1}",
+                        "description": "JSON: {booking_id: 1, payment_reference: TRF-123 (optional)}",
                         "name": "payment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "booking_id": {
-                                    "type": "integer"
-                                }
-                            }
+                            "type": "object",
+                            "properties": {
+                                "booking_id": {
+                                    "type": "integer"
+                                },
+                                "payment_reference": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key; retries with the same key replay the first response for 24h",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Booking Expired or Cancelled / Idempotency-Key In Progress",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key Reused With Different Body",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate Limited",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Create a new user account. Role can be 'user' or 'admin'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register New User",
+                "parameters": [
+                    {
+                        "description": "User Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "message: User created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/port.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email Already Registered",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/waitlist": {
+            "get": {
+                "description": "Newest first, including finished ones. Offered entries carry the time their hold runs out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "List my waitlist entries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Wait for a field interval that is currently taken. When it frees up it is held for the first\ncustomer in line for WAITLIST_HOLD, who is emailed and confirms by booking the slot before the\nhold runs out; otherwise it goes to the next in line.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Join the waitlist for a booked slot",
+                "parameters": [
+                    {
+                        "description": "Slot to wait for",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/port.WaitlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/port.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Input / Slot Is Free",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Field Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Already Waiting For This Slot",
                         "schema": {
-                            "$ref": "#/definitions/port.MessageResponse"
+                            "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/register": {
-            "post": {
-                "description": "Create a new user account. Role can be 'user' or 'admin'.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/waitlist/{id}": {
+            "delete": {
+                "description": "Withdraw an entry. Leaving while the slot is offered declines it, passing it to the next in line.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Waitlist"
                 ],
-                "summary": "Register New User",
+                "summary": "Leave the waitlist",
                 "parameters": [
                     {
-                        "description": "User Data",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/port.RegisterRequest"
-                        }
+                        "type": "integer",
+                        "description": "Waitlist entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "message: User created successfully",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/port.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid Input",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/port.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/port.ErrorResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         }
     },
     "definitions": {
+        "domain.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string"
+                },
+                "opt_out_non_transactional": {
+                    "description": "OptOutNonTransactional stops reminders and other optional messages.",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WebhookAttempt": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "response_body": {
+                    "description": "ResponseBody is truncated to the first kilobyte.",
+                    "type": "string"
+                },
+                "response_status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempt_log": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.WebhookAttempt"
+                    }
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Payload is the exact JSON body POSTed to the receiver.",
+                    "type": "object"
+                },
+                "response_status": {
+                    "description": "ResponseStatus and LastError describe the latest attempt.",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "consecutive_failures": {
+                    "description": "ConsecutiveFailures counts failed attempts since the last success; the\nsubscription is disabled when it reaches the sender's limit.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "port.BookingRequest": {
             "type": "object",
             "properties": {
@@ -539,14 +2863,27 @@ const docTemplate = `{
         "port.CreateFieldRequest": {
             "type": "object",
             "properties": {
+                "close_time": {
+                    "type": "string",
+                    "example": "23:00"
+                },
                 "location": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
+                "open_time": {
+                    "type": "string",
+                    "example": "06:00"
+                },
                 "price_per_hour": {
                     "type": "integer"
+                },
+                "time_zone": {
+                    "description": "TimeZone, OpenTime and CloseTime are optional. New fields default to\nthe server's DEFAULT_TIMEZONE and being open all day; on update, empty\nvalues keep the current ones.",
+                    "type": "string",
+                    "example": "Asia/Jakarta"
                 }
             }
         },
@@ -584,6 +2921,80 @@ const docTemplate = `{
                 }
             }
         },
+        "port.FrontDeskBookingRequest": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "field_id": {
+                    "type": "integer"
+                },
+                "guest": {
+                    "$ref": "#/definitions/port.GuestContact"
+                },
+                "override_reason": {
+                    "type": "string",
+                    "example": "Tournament final, venue stays open late"
+                },
+                "overrides": {
+                    "description": "Overrides names the booking rules to waive, past_start or\nopening_hours; OverrideReason, required with them, says why.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "opening_hours"
+                    ]
+                },
+                "payment_method": {
+                    "description": "PaymentMethod \"cash\" records the booking as paid at the counter, with\nPaymentReference as the receipt number if given. Without it the\nbooking is pending like any other.",
+                    "type": "string",
+                    "example": "cash"
+                },
+                "payment_reference": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "port.GuestContact": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Andi"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+62 812-3456-7890"
+                }
+            }
+        },
+        "port.HoldRequest": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "field_id": {
+                    "type": "integer"
+                },
+                "minutes": {
+                    "description": "Minutes is how long to hold the slot, up to SLOT_HOLD_MAX; omitted\nholds it for the maximum.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
         "port.LoginRequest": {
             "type": "object",
             "properties": {
@@ -611,6 +3022,17 @@ const docTemplate = `{
                 }
             }
         },
+        "port.NotificationPreferenceRequest": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string"
+                },
+                "opt_out_non_transactional": {
+                    "type": "boolean"
+                }
+            }
+        },
         "port.RegisterRequest": {
             "type": "object",
             "properties": {
@@ -627,6 +3049,87 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "port.WaitlistRequest": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "field_id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "port.WebhookRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active re-enables (true) or pauses (false) the subscription on update.",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Secret signs deliveries. It is generated when left empty on create;\non update a non-empty value rotates it.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "port.WebhookSecretResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "consecutive_failures": {
+                    "description": "ConsecutiveFailures counts failed attempts since the last success; the\nsubscription is disabled when it reaches the sender's limit.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
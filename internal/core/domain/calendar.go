@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// CalendarFeed is the secret, read-only calendar URL of one customer's or
+// one field's bookings. Only a hash of the URL's token is stored, so a lost
+// URL cannot be shown again, only replaced.
+type CalendarFeed struct {
+	ID uint `gorm:"primaryKey"`
+	// Exactly one of UserID and FieldID is set.
+	UserID    *uint
+	FieldID   *uint
+	TokenHash string `gorm:"size:64;not null"`
+	CreatedAt time.Time
+}
+
+// Calendar is what a calendar feed shows: the bookings of one customer or
+// one field, each with its Field and, for a field, its User.
+type Calendar struct {
+	Name string
+	// ForField tells a field's calendar, whose events are named after
+	// customers, from a customer's, whose events are named after fields.
+	ForField bool
+	Bookings []Booking
+}
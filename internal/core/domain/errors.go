@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Error kinds. Services and repositories wrap these so handlers can map
+// failures to HTTP statuses with errors.Is instead of matching strings.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation failed")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("too many requests")
+)
+
+// Error carries a client-safe message together with one of the kinds above.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NewNotFoundError(message string) error {
+	return &Error{Kind: ErrNotFound, Message: message}
+}
+
+func NewConflictError(message string) error {
+	return &Error{Kind: ErrConflict, Message: message}
+}
+
+func NewValidationError(message string) error {
+	return &Error{Kind: ErrValidation, Message: message}
+}
+
+func NewForbiddenError(message string) error {
+	return &Error{Kind: ErrForbidden, Message: message}
+}
+
+func NewUnauthorizedError(message string) error {
+	return &Error{Kind: ErrUnauthorized, Message: message}
+}
+
+// RateLimitError is returned when a caller is throttled. RetryAfter tells the
+// client how long to wait before trying again.
+type RateLimitError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Message
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+func NewRateLimitError(message string, retryAfter time.Duration) error {
+	return &RateLimitError{Message: message, RetryAfter: retryAfter}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// BookingExportRow is a booking with its field and customer, flattened for
+// finance to reconcile against bank statements. It never carries the
+// customer's password.
+type BookingExportRow struct {
+	BookingID uint
+	Status    string
+	StartTime time.Time
+	EndTime   time.Time
+	// LocalStart is StartTime on the field's wall clock, "YYYY-MM-DD HH:MM".
+	LocalStart   string
+	Minutes      int64
+	FieldID      uint
+	FieldName    string
+	Venue        string
+	TimeZone     string
+	UserID       uint
+	UserName     string
+	UserEmail    string
+	PricePerHour int64
+	// AmountPaid and PaymentReference are set once the booking is paid.
+	AmountPaid       *int64
+	PaymentReference string
+	CreatedAt        time.Time
+	PaidAt           *time.Time
+	CancelledAt      *time.Time
+	ExpiredAt        *time.Time
+	NoShowAt         *time.Time
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// SlotHold reserves a field interval for one customer until ExpiresAt:
+// nobody else can book or hold it meanwhile, and a booking the customer
+// makes for it consumes the hold. Holds are placed at checkout or given to
+// customers offered a slot from the waitlist.
+type SlotHold struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	FieldID   uint      `json:"field_id" gorm:"not null"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	StartTime time.Time `json:"start_time" gorm:"not null"`
+	EndTime   time.Time `json:"end_time" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HoldEventPayload is the body of hold.* events.
+type HoldEventPayload struct {
+	HoldID    uint      `json:"hold_id"`
+	UserID    uint      `json:"user_id"`
+	FieldID   uint      `json:"field_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// NewHoldEvent snapshots hold into a pending outbox event.
+func NewHoldEvent(eventType string, hold *SlotHold, now time.Time) (OutboxEvent, error) {
+	payload, err := json.Marshal(HoldEventPayload{
+		HoldID:    hold.ID,
+		UserID:    hold.UserID,
+		FieldID:   hold.FieldID,
+		StartTime: hold.StartTime,
+		EndTime:   hold.EndTime,
+	})
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{
+		EventType:     eventType,
+		AggregateID:   hold.ID,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// HoldPayload decodes the payload of a hold.* event.
+func (e *OutboxEvent) HoldPayload() (HoldEventPayload, error) {
+	var p HoldEventPayload
+	err := json.Unmarshal(e.Payload, &p)
+	return p, err
+}
+
+// What occupies a busy interval in a field's availability.
+const (
+	BusyBooking = "booking"
+	BusyHold    = "hold"
+)
+
+// TimeRange is the half-open interval [StartTime, EndTime).
+type TimeRange struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// BusySlot is an interval a field cannot be booked in. Kind tells a pending
+// or paid booking from a hold, without saying whose.
+type BusySlot struct {
+	TimeRange
+	Kind string `json:"kind" example:"booking"`
+}
+
+// Availability is a field's calendar for one local day.
+type Availability struct {
+	FieldID  uint      `json:"field_id"`
+	Date     string    `json:"date" example:"2025-06-02"`
+	TimeZone string    `json:"time_zone" example:"Asia/Jakarta"`
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+	// Busy lists bookings and unexpired holds overlapping the opening
+	// hours; Free is the rest of them.
+	Busy []BusySlot  `json:"busy"`
+	Free []TimeRange `json:"free"`
+}
+
+// FreeRanges returns the parts of [open, close) not covered by busy, which
+// may overlap each other and extend past either end.
+func FreeRanges(open, close time.Time, busy []BusySlot) []TimeRange {
+	sorted := append([]BusySlot(nil), busy...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	free := []TimeRange{}
+	cursor := open
+	for _, b := range sorted {
+		if b.StartTime.After(cursor) {
+			end := b.StartTime
+			if end.After(close) {
+				end = close
+			}
+			if end.After(cursor) {
+				free = append(free, TimeRange{StartTime: cursor, EndTime: end})
+			}
+		}
+		if b.EndTime.After(cursor) {
+			cursor = b.EndTime
+		}
+	}
+	if close.After(cursor) {
+		free = append(free, TimeRange{StartTime: cursor, EndTime: close})
+	}
+	return free
+}
@@ -0,0 +1,26 @@
+package domain
+
+// Import files, as named in ImportError.
+const (
+	ImportFileFields   = "fields"
+	ImportFileBookings = "bookings"
+)
+
+// ImportError is a problem with one row of an import file. Row is the line
+// in the file, counting the header as line 1; 0 is the file as a whole.
+type ImportError struct {
+	File    string `json:"file" example:"bookings"`
+	Row     int    `json:"row" example:"4"`
+	Column  string `json:"column,omitempty" example:"start_time"`
+	Message string `json:"message"`
+}
+
+// ImportResult reports what an import did, or would do on a dry run. When
+// there are errors nothing is imported.
+type ImportResult struct {
+	DryRun   bool          `json:"dry_run"`
+	Imported bool          `json:"imported"`
+	Fields   int           `json:"fields"`
+	Bookings int           `json:"bookings"`
+	Errors   []ImportError `json:"errors"`
+}
@@ -12,22 +12,98 @@ type User struct {
 	Email    string `json:"email" gorm:"unique"`
 	Password string `json:"password"`
 	Role     string `json:"role" gorm:"default:'user'"`
+	// Phone is how the front desk reaches guests, who have no email.
+	Phone string `json:"phone,omitempty" gorm:"size:32"`
 }
 
+// RoleGuest marks a walk-in or phone customer the front desk booked for.
+// Guests have no email or password, so they cannot log in and get no
+// emails; they are told apart by phone number.
+const RoleGuest = "guest"
+
 type Field struct {
 	gorm.Model
 	Name         string `json:"name"`
 	PricePerHour int    `json:"price_per_hour"`
 	Location     string `json:"location"`
+	// TimeZone is the IANA zone the field's opening hours and day
+	// boundaries are in.
+	TimeZone string `json:"time_zone" gorm:"size:64;not null" example:"Asia/Jakarta"`
+	// OpenTime and CloseTime are local wall-clock times ("HH:MM"); a
+	// CloseTime of "24:00" means midnight at the end of the day.
+	OpenTime  string `json:"open_time" gorm:"size:5;not null" example:"06:00"`
+	CloseTime string `json:"close_time" gorm:"size:5;not null" example:"23:00"`
+}
+
+// Price is what booking the field from start to end costs, charged per
+// whole minute as booking emails show it.
+func (f *Field) Price(start, end time.Time) int64 {
+	minutes := int64(end.Sub(start) / time.Minute)
+	return int64(f.PricePerHour) * minutes / 60
 }
 
+const (
+	BookingStatusPending   = "pending"
+	BookingStatusPaid      = "paid"
+	BookingStatusCancelled = "cancelled"
+	// BookingStatusExpired marks a pending booking that was not paid within
+	// the payment window; it no longer holds the slot.
+	BookingStatusExpired = "expired"
+	// BookingStatusNoShow marks a paid booking whose customer did not turn
+	// up, as recorded by an admin once it has started.
+	BookingStatusNoShow = "no_show"
+)
+
+// CancelReasonFieldRemoved is given to customers whose bookings were
+// cancelled because the venue deleted the field.
+const CancelReasonFieldRemoved = "the field has been removed by the venue"
+
 type Booking struct {
 	gorm.Model
-	FieldID   uint      `json:"field_id"`
-	Field     *Field    `json:"field,omitempty" gorm:"foreignKey:FieldID"`
-	UserID    uint      `json:"user_id"`
-	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	FieldID   uint      `json:"field_id" gorm:"not null;index"`
+	Field     *Field    `json:"field,omitempty" gorm:"foreignKey:FieldID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 	StartTime time.Time `json:"start_time"`
 	EndTime   time.Time `json:"end_time"`
 	Status    string    `json:"status" gorm:"default:'pending'"`
+	// PaymentReference and AmountPaid are recorded when the booking is
+	// paid, the amount at the field's price at the time.
+	PaymentReference string `json:"payment_reference,omitempty" gorm:"size:100"`
+	AmountPaid       *int64 `json:"amount_paid,omitempty"`
+	// When the booking reached each status, if it did.
+	PaidAt      *time.Time `json:"paid_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	ExpiredAt   *time.Time `json:"expired_at,omitempty"`
+	NoShowAt    *time.Time `json:"no_show_at,omitempty"`
+	// PaymentMethod is PaymentMethodCash for bookings paid at the counter.
+	PaymentMethod string `json:"payment_method,omitempty" gorm:"size:16"`
+	// CreatedBy is the admin who took the booking for the customer, if one
+	// did, and Overrides the booking rules they waived, comma separated,
+	// for the reason given.
+	CreatedBy      *uint  `json:"created_by,omitempty"`
+	Overrides      string `json:"overrides,omitempty" gorm:"size:100"`
+	OverrideReason string `json:"override_reason,omitempty" gorm:"size:500"`
+}
+
+// PaymentMethodCash marks a booking paid in cash at the counter.
+const PaymentMethodCash = "cash"
+
+// Booking rules the front desk may waive, with a reason, when booking for a
+// customer. Overlaps and other customers' holds are never waived.
+const (
+	// OverridePastStart allows a booking that has already started, for a
+	// walk-in who is already playing.
+	OverridePastStart = "past_start"
+	// OverrideOpeningHours allows a booking outside the field's opening
+	// hours.
+	OverrideOpeningHours = "opening_hours"
+)
+
+// BookingReminder records that the reminder due OffsetSeconds before a
+// booking starts has been scheduled, so it is never scheduled twice.
+type BookingReminder struct {
+	BookingID     uint  `gorm:"primaryKey;autoIncrement:false"`
+	OffsetSeconds int64 `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt     time.Time
 }
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// Notification kinds, one template per kind and language.
+const (
+	NotificationBookingCreated   = "booking_created"
+	NotificationPaymentReceived  = "payment_received"
+	NotificationBookingReminder  = "booking_reminder"
+	NotificationBookingCancelled = "booking_cancelled"
+	NotificationBookingExpired   = "booking_expired"
+	NotificationWaitlistOffer    = "waitlist_offer"
+)
+
+// Supported notification languages.
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// IsTransactional reports whether kind confirms or changes something the
+// customer depends on. Transactional messages are sent regardless of the
+// customer's preferences; the rest can be opted out of.
+func IsTransactional(kind string) bool {
+	return kind != NotificationBookingReminder
+}
+
+// NotificationPreference is a customer's choice of language and whether they
+// receive non-transactional messages. Customers without a row get the
+// defaults.
+type NotificationPreference struct {
+	UserID   uint   `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Language string `json:"language" gorm:"size:8;not null"`
+	// OptOutNonTransactional stops reminders and other optional messages.
+	OptOutNonTransactional bool      `json:"opt_out_non_transactional" gorm:"not null"`
+	UpdatedAt              time.Time `json:"updated_at"`
+}
+
+// Notification is a rendered message ready to hand to a sink.
+type Notification struct {
+	Kind   string
+	UserID uint
+	// Name and To are the recipient's name and email address.
+	Name     string
+	To       string
+	Language string
+	Subject  string
+	Body     string
+}
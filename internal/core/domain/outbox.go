@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types written to the outbox.
+const (
+	EventBookingCreated   = "booking.created"
+	EventBookingPaid      = "booking.paid"
+	EventBookingCancelled = "booking.cancelled"
+	EventBookingExpired   = "booking.expired"
+	// EventBookingReminderDue is written when a paid booking reaches one of
+	// its reminder offsets. It is internal and not offered to webhooks.
+	EventBookingReminderDue = "booking.reminder_due"
+	// EventWaitlistOffered is written when a freed slot is held for the
+	// next customer on its waitlist.
+	EventWaitlistOffered = "waitlist.offered"
+	// EventWaitlistReleased is written when an offered hold ends without a
+	// booking, lapsed or declined, so the slot goes to the next in line.
+	EventWaitlistReleased = "waitlist.released"
+	// EventHoldReleased is written when a checkout hold ends without being
+	// booked: released, replaced or run out. It is internal and not offered
+	// to webhooks.
+	EventHoldReleased = "hold.released"
+)
+
+// Outbox event delivery states.
+const (
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	// OutboxStatusDead is terminal: the event exhausted its attempts and
+	// needs an operator to look at LastError.
+	OutboxStatusDead = "dead"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// that caused it, then delivered to in-process handlers by the dispatcher.
+type OutboxEvent struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	EventType   string          `json:"event_type" gorm:"size:64;not null"`
+	AggregateID uint            `json:"aggregate_id" gorm:"not null"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+	Status      string          `json:"status" gorm:"size:16;not null;default:pending"`
+	Attempts    int             `json:"attempts" gorm:"not null;default:0"`
+	// NextAttemptAt is when the event becomes due (again).
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"not null"`
+	// LockedUntil is the lease of the instance currently delivering it.
+	LockedUntil *time.Time `json:"-"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	// DeliveredTo names the subscribers that have already handled the
+	// event; a retry skips them.
+	DeliveredTo []string `json:"delivered_to,omitempty" gorm:"-"`
+}
+
+// OutboxDelivery records that one subscriber handled an outbox event.
+type OutboxDelivery struct {
+	EventID     uint      `gorm:"primaryKey"`
+	Subscriber  string    `gorm:"primaryKey;size:64"`
+	DeliveredAt time.Time `gorm:"not null"`
+}
+
+// BookingEventPayload is the body of every booking.* event: a snapshot of
+// the booking when the event happened.
+type BookingEventPayload struct {
+	BookingID uint      `json:"booking_id"`
+	UserID    uint      `json:"user_id"`
+	FieldID   uint      `json:"field_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewBookingEvent snapshots booking into a pending outbox event.
+func NewBookingEvent(eventType string, booking *Booking, reason string, now time.Time) (OutboxEvent, error) {
+	payload, err := json.Marshal(BookingEventPayload{
+		BookingID: booking.ID,
+		UserID:    booking.UserID,
+		FieldID:   booking.FieldID,
+		StartTime: booking.StartTime,
+		EndTime:   booking.EndTime,
+		Status:    booking.Status,
+		Reason:    reason,
+		CreatedAt: booking.CreatedAt,
+	})
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{
+		EventType:     eventType,
+		AggregateID:   booking.ID,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// BookingPayload decodes the payload of a booking.* event.
+func (e *OutboxEvent) BookingPayload() (BookingEventPayload, error) {
+	var p BookingEventPayload
+	err := json.Unmarshal(e.Payload, &p)
+	return p, err
+}
@@ -0,0 +1,70 @@
+package domain
+
+// Report periods and revenue groupings.
+const (
+	ReportPeriodDay   = "day"
+	ReportPeriodWeek  = "week"
+	ReportPeriodMonth = "month"
+
+	ReportGroupField = "field"
+	ReportGroupVenue = "venue"
+	ReportGroupAll   = "all"
+)
+
+// Reports count paid and no-show bookings as confirmed: both were paid for
+// and took the slot. Revenue is the amount each booking was paid.
+
+// RevenueRow is the confirmed revenue of one period, for one field, one
+// venue (field location) or all fields depending on the grouping.
+type RevenueRow struct {
+	PeriodStart string  `json:"period_start" example:"2025-06-02"`
+	FieldID     uint    `json:"field_id,omitempty"`
+	FieldName   string  `json:"field_name,omitempty"`
+	Venue       string  `json:"venue,omitempty"`
+	Bookings    int64   `json:"bookings"`
+	Hours       float64 `json:"hours"`
+	Revenue     int64   `json:"revenue"`
+}
+
+// UtilizationRow compares a field's confirmed hours with its opening hours.
+type UtilizationRow struct {
+	FieldID        uint    `json:"field_id"`
+	FieldName      string  `json:"field_name"`
+	BookedHours    float64 `json:"booked_hours"`
+	OpenHours      float64 `json:"open_hours"`
+	UtilizationPct float64 `json:"utilization_pct"`
+}
+
+// HeatmapCell counts confirmed bookings starting in one local hour of one
+// weekday, 1 being Monday.
+type HeatmapCell struct {
+	Weekday  int    `json:"weekday" example:"1"`
+	Day      string `json:"day" example:"Monday"`
+	Hour     int    `json:"hour" example:"18"`
+	Bookings int64  `json:"bookings"`
+}
+
+// CancellationRow breaks a field's bookings down by outcome. The
+// cancellation rate is over all bookings, the no-show rate over confirmed
+// ones. A FieldID of 0 is the total over all fields.
+type CancellationRow struct {
+	FieldID          uint    `json:"field_id"`
+	FieldName        string  `json:"field_name"`
+	Bookings         int64   `json:"bookings"`
+	Paid             int64   `json:"paid"`
+	Cancelled        int64   `json:"cancelled"`
+	Expired          int64   `json:"expired"`
+	NoShows          int64   `json:"no_shows"`
+	CancellationRate float64 `json:"cancellation_rate_pct"`
+	NoShowRate       float64 `json:"no_show_rate_pct"`
+}
+
+// CustomerRow is a customer's confirmed bookings and what they paid.
+type CustomerRow struct {
+	UserID   uint    `json:"user_id"`
+	Name     string  `json:"name"`
+	Email    string  `json:"email"`
+	Bookings int64   `json:"bookings"`
+	Hours    float64 `json:"hours"`
+	Revenue  int64   `json:"revenue"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults for fields created without opening hours: open all day.
+const (
+	DefaultOpenTime  = "00:00"
+	DefaultCloseTime = "24:00"
+)
+
+// ParseClockTime parses a wall-clock time "HH:MM" into hours and minutes.
+// "24:00" is accepted as the end of the day.
+func ParseClockTime(s string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", s)
+	switch {
+	case s == "24:00":
+		return 24, 0, nil
+	case err != nil || len(s) != 5:
+		return 0, 0, fmt.Errorf("%q is not a time of day in HH:MM form", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// LocalDay returns the start of the calendar day containing t in loc and
+// the start of the next one. They are 24 hours apart except on days when
+// the clocks change.
+func LocalDay(t time.Time, loc *time.Location) (start, end time.Time) {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc), time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+}
+
+// Zone returns the field's time zone. Fields stored before time zones were
+// recorded have none and are treated as UTC.
+func (f *Field) Zone() (*time.Location, error) {
+	return time.LoadLocation(f.TimeZone)
+}
+
+// Hours returns the field's opening and closing times, open all day when
+// none are set.
+func (f *Field) Hours() (open, close string) {
+	open, close = f.OpenTime, f.CloseTime
+	if open == "" {
+		open = DefaultOpenTime
+	}
+	if close == "" {
+		close = DefaultCloseTime
+	}
+	return open, close
+}
+
+// OpeningHours returns when the field opens and closes on the local day
+// containing t. Times are wall-clock times, so on a day the clocks change
+// the field is open for an hour more or less.
+func (f *Field) OpeningHours(t time.Time) (open, close time.Time, err error) {
+	loc, err := f.Zone()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	openTime, closeTime := f.Hours()
+	oh, om, err := ParseClockTime(openTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	ch, cm, err := ParseClockTime(closeTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, oh, om, 0, 0, loc), time.Date(y, m, d, ch, cm, 0, 0, loc), nil
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Waitlist entry states. An entry is waiting until the slot frees up, then
+// offered with a hold; it ends booked, lapsed (the hold ran out) or
+// cancelled (withdrawn by the customer, or the field was removed).
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusOffered   = "offered"
+	WaitlistStatusBooked    = "booked"
+	WaitlistStatusLapsed    = "lapsed"
+	WaitlistStatusCancelled = "cancelled"
+)
+
+// WaitlistEntry is a customer waiting for a field interval that was booked
+// when they asked for it.
+type WaitlistEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	FieldID   uint      `json:"field_id" gorm:"not null"`
+	UserID    uint      `json:"user_id" gorm:"not null"`
+	StartTime time.Time `json:"start_time" gorm:"not null"`
+	EndTime   time.Time `json:"end_time" gorm:"not null"`
+	Status    string    `json:"status" gorm:"size:16;not null;default:waiting"`
+	// HoldID and HoldExpiresAt are set while the entry is offered.
+	HoldID        *uint      `json:"hold_id,omitempty"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// WaitlistEventPayload is the body of waitlist.* events.
+type WaitlistEventPayload struct {
+	EntryID       uint       `json:"entry_id"`
+	UserID        uint       `json:"user_id"`
+	FieldID       uint       `json:"field_id"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       time.Time  `json:"end_time"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+}
+
+// NewWaitlistEvent snapshots entry into a pending outbox event.
+func NewWaitlistEvent(eventType string, entry *WaitlistEntry, now time.Time) (OutboxEvent, error) {
+	payload, err := json.Marshal(WaitlistEventPayload{
+		EntryID:       entry.ID,
+		UserID:        entry.UserID,
+		FieldID:       entry.FieldID,
+		StartTime:     entry.StartTime,
+		EndTime:       entry.EndTime,
+		HoldExpiresAt: entry.HoldExpiresAt,
+	})
+	if err != nil {
+		return OutboxEvent{}, err
+	}
+	return OutboxEvent{
+		EventType:     eventType,
+		AggregateID:   entry.ID,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// WaitlistPayload decodes the payload of a waitlist.* event.
+func (e *OutboxEvent) WaitlistPayload() (WaitlistEventPayload, error) {
+	var p WaitlistEventPayload
+	err := json.Unmarshal(e.Payload, &p)
+	return p, err
+}
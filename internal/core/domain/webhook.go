@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEventTypes are the events partners may subscribe to.
+var WebhookEventTypes = []string{
+	EventBookingCreated,
+	EventBookingPaid,
+	EventBookingCancelled,
+	EventBookingExpired,
+}
+
+// Webhook delivery states.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed is terminal until an admin redelivers it.
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookSubscription is a partner endpoint that receives signed POSTs for
+// the event types it subscribed to.
+type WebhookSubscription struct {
+	ID          uint     `json:"id" gorm:"primaryKey"`
+	URL         string   `json:"url" gorm:"not null"`
+	Secret      string   `json:"-" gorm:"not null"`
+	EventTypes  []string `json:"event_types" gorm:"type:jsonb;serializer:json;not null"`
+	Description string   `json:"description,omitempty"`
+	Active      bool     `json:"active" gorm:"not null"`
+	// ConsecutiveFailures counts failed attempts since the last success; the
+	// subscription is disabled when it reaches the sender's limit.
+	ConsecutiveFailures int        `json:"consecutive_failures" gorm:"not null;default:0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// Wants reports whether the subscription receives eventType.
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event queued for one subscription. Retries and
+// redeliveries reuse the row, so receivers see the same X-Webhook-ID and
+// body every time.
+type WebhookDelivery struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint   `json:"subscription_id" gorm:"not null"`
+	EventID        uint   `json:"event_id" gorm:"not null"`
+	EventType      string `json:"event_type" gorm:"size:64;not null"`
+	// Payload is the exact JSON body POSTed to the receiver.
+	Payload       json.RawMessage `json:"payload" gorm:"type:jsonb;not null" swaggertype:"object"`
+	Status        string          `json:"status" gorm:"size:16;not null;default:pending"`
+	Attempts      int             `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time       `json:"next_attempt_at" gorm:"not null"`
+	LockedUntil   *time.Time      `json:"-"`
+	// ResponseStatus and LastError describe the latest attempt.
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+
+	Subscription *WebhookSubscription `json:"-" gorm:"foreignKey:SubscriptionID"`
+	AttemptLog   []WebhookAttempt     `json:"attempt_log,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+// WebhookAttempt logs a single POST of a delivery.
+type WebhookAttempt struct {
+	ID             uint `json:"id" gorm:"primaryKey"`
+	DeliveryID     uint `json:"delivery_id" gorm:"not null"`
+	ResponseStatus int  `json:"response_status,omitempty"`
+	// ResponseBody is truncated to the first kilobyte.
+	ResponseBody string    `json:"response_body,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	DurationMS   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Succeeded reports whether the receiver accepted the delivery.
+func (a *WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.ResponseStatus >= 200 && a.ResponseStatus < 300
+}
+
+// WebhookEnvelope is the JSON body of every webhook POST.
+type WebhookEnvelope struct {
+	// ID is the outbox event ID; receivers use it to drop duplicates.
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -1,6 +1,7 @@
 package port
 
 import (
+	"context"
 	"time"
 
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
@@ -12,17 +13,87 @@ type BookingRequest struct {
 	EndTime   time.Time `json:"end_time"`
 }
 
+// BookingQuery filters the booking list and export. Every filter is
+// optional.
+type BookingQuery struct {
+	Status  string `query:"status" example:"paid"`
+	FieldID uint   `query:"field_id"`
+	UserID  uint   `query:"user_id"`
+	// From and To are the first and last local days (YYYY-MM-DD) a booking
+	// may start on, in its field's time zone.
+	From string `query:"from" example:"2025-06-01"`
+	To   string `query:"to" example:"2025-06-30"`
+}
+
+// BookingFilter is a validated BookingQuery; zero values match anything.
+// From and To are calendar dates at midnight UTC.
+type BookingFilter struct {
+	Status  string
+	FieldID uint
+	UserID  uint
+	From    time.Time
+	To      time.Time
+}
+
+// BookingRows iterates over exported bookings like sql.Rows, so they are
+// written out as they are read rather than held in memory.
+type BookingRows interface {
+	Next() bool
+	Scan(row *domain.BookingExportRow) error
+	Err() error
+	Close() error
+}
+
 type BookingRepository interface {
-	Create(booking *domain.Booking) error
-	CheckAvailability(fieldID uint, start, end time.Time) (bool, error)
-	GetByID(id uint) (*domain.Booking, error)
-	UpdateStatus(id uint, status string) error
-	GetAll() ([]domain.Booking, error)
+	// Create inserts booking. A booking whose User is a guest without an
+	// ID is for that guest, who is recorded in the same transaction, or
+	// the guest already recorded with their phone number is used.
+	Create(ctx context.Context, booking *domain.Booking) error
+	// CheckAvailability reports whether the interval is taken by a pending,
+	// paid or no-show booking, or held for someone other than userID.
+	CheckAvailability(ctx context.Context, fieldID, userID uint, start, end time.Time) (bool, error)
+	// ListBusy returns the pending, paid and no-show bookings and unexpired
+	// holds on fieldID overlapping from-to, by start time.
+	ListBusy(ctx context.Context, fieldID uint, from, to time.Time) ([]domain.BusySlot, error)
+	GetByID(ctx context.Context, id uint) (*domain.Booking, error)
+	// UpdateStatus moves a booking from status from to status to. It is a
+	// no-op for a booking already in to, and a conflict for one in any
+	// other status by the time it is locked.
+	UpdateStatus(ctx context.Context, id uint, from, to string) error
+	// MarkPaid records a pending booking as paid with reference, charging
+	// it its field's current price, like UpdateStatus from pending.
+	MarkPaid(ctx context.Context, id uint, reference string) error
+	GetAll(ctx context.Context, filter BookingFilter) ([]domain.Booking, error)
+	// Export runs the export query; the caller must close the rows.
+	Export(ctx context.Context, filter BookingFilter) (BookingRows, error)
+	// ExpirePending marks up to limit pending bookings created before cutoff
+	// as expired, recording a booking.expired event for each, and returns them.
+	ExpirePending(ctx context.Context, cutoff time.Time, limit int) ([]domain.Booking, error)
+}
+
+// BookingReminderRepository schedules reminders for upcoming paid bookings.
+type BookingReminderRepository interface {
+	// ScheduleDue records the reminder due offset before each paid booking
+	// that starts after after and no later than until, writing a
+	// booking.reminder_due event for it, and returns up to limit of them.
+	// Reminders already recorded, by this or another instance, are skipped.
+	ScheduleDue(ctx context.Context, offset time.Duration, after, until time.Time, limit int) ([]domain.Booking, error)
 }
 
 type BookingService interface {
-	CreateBooking(userID uint, req *BookingRequest) (*domain.Booking, error)
-	PayBooking(bookingID uint) error
-	GetAllBookings() ([]domain.Booking, error)
-	GetBookingByID(id uint) (*domain.Booking, error)
+	CreateBooking(ctx context.Context, userID uint, req *BookingRequest) (*domain.Booking, error)
+	// PayBooking pays for a booking; an empty reference is given a
+	// generated one.
+	PayBooking(ctx context.Context, bookingID uint, reference string) error
+	GetAllBookings(ctx context.Context, q *BookingQuery) ([]domain.Booking, error)
+	// ExportBookings returns the bookings matching q by start time, for
+	// finance. The caller must close the rows.
+	ExportBookings(ctx context.Context, q *BookingQuery) (BookingRows, error)
+	GetBookingByID(ctx context.Context, id uint) (*domain.Booking, error)
+	// GetAvailability returns the field's calendar for date (YYYY-MM-DD in
+	// the field's time zone), today when empty.
+	GetAvailability(ctx context.Context, fieldID uint, date string) (*domain.Availability, error)
+	// MarkNoShow records that the customer of a paid booking that has
+	// started did not turn up.
+	MarkNoShow(ctx context.Context, bookingID uint) error
 }
@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// CalendarFeedOwner names whose bookings a feed shows: a customer or a
+// field. Exactly one is set.
+type CalendarFeedOwner struct {
+	UserID  uint
+	FieldID uint
+}
+
+type CalendarRepository interface {
+	// SaveFeed gives owner a feed with tokenHash, replacing any earlier one.
+	SaveFeed(ctx context.Context, owner CalendarFeedOwner, tokenHash string) error
+	// DeleteFeed removes owner's feed, if it has one.
+	DeleteFeed(ctx context.Context, owner CalendarFeedOwner) error
+	GetFeedByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	// ListBookings returns owner's bookings starting after since, in any
+	// status, by start time, with their Field (even if deleted) and User.
+	ListBookings(ctx context.Context, owner CalendarFeedOwner, since time.Time) ([]domain.Booking, error)
+}
+
+type CalendarService interface {
+	// CreateFeed issues a new feed token for owner and returns it. Any URL
+	// issued before stops working.
+	CreateFeed(ctx context.Context, owner CalendarFeedOwner) (string, error)
+	DeleteFeed(ctx context.Context, owner CalendarFeedOwner) error
+	// Feed returns the calendar a token opens.
+	Feed(ctx context.Context, token string) (*domain.Calendar, error)
+	// BookingCalendar returns a calendar of one booking, for its customer or
+	// an admin.
+	BookingCalendar(ctx context.Context, bookingID, userID uint, isAdmin bool) (*domain.Calendar, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import "time"
+
+// Clock is the services' source of "now", so tests can pin it.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function such as time.Now to Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
@@ -1,26 +1,53 @@
 package port
 
-import "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// Field deletion policies, selected by the admin with ?policy= on DELETE.
+const (
+	// FieldDeletePolicyBlock refuses to delete a field that still has
+	// upcoming pending or paid bookings.
+	FieldDeletePolicyBlock = "block"
+	// FieldDeletePolicyCascade cancels those bookings, notifies their owners
+	// and then deletes the field.
+	FieldDeletePolicyCascade = "cascade"
+)
 
 // DTO
 type CreateFieldRequest struct {
 	Name         string `json:"name"`
 	PricePerHour int    `json:"price_per_hour"`
 	Location     string `json:"location"`
+	// TimeZone, OpenTime and CloseTime are optional. New fields default to
+	// the server's DEFAULT_TIMEZONE and being open all day; on update, empty
+	// values keep the current ones.
+	TimeZone  string `json:"time_zone" example:"Asia/Jakarta"`
+	OpenTime  string `json:"open_time" example:"06:00"`
+	CloseTime string `json:"close_time" example:"23:00"`
 }
 
 type FieldRepository interface {
-	Create(field *domain.Field) error
-	GetAll() ([]domain.Field, error)
-	GetByID(id uint) (*domain.Field, error)
-	Update(field *domain.Field) error
-	Delete(id uint) error
+	Create(ctx context.Context, field *domain.Field) error
+	GetAll(ctx context.Context) ([]domain.Field, error)
+	GetByID(ctx context.Context, id uint) (*domain.Field, error)
+	// GetByIDWithDeleted also finds removed fields, for history such as
+	// telling customers which field their cancelled booking was on.
+	GetByIDWithDeleted(ctx context.Context, id uint) (*domain.Field, error)
+	Update(ctx context.Context, field *domain.Field) error
+	// DeleteIfUnbooked soft-deletes the field unless it has bookings that
+	// end after after, and returns how many it has.
+	DeleteIfUnbooked(ctx context.Context, id uint, after time.Time) (int64, error)
+	DeleteAndCancelBookings(ctx context.Context, id uint, after time.Time) ([]domain.Booking, error)
 }
 
 type FieldService interface {
-	CreateField(req *CreateFieldRequest) error
-	GetAllFields() ([]domain.Field, error)
-	GetFieldByID(id uint) (*domain.Field, error)
-	UpdateField(id uint, req *CreateFieldRequest) error
-	DeleteField(id uint) error
+	CreateField(ctx context.Context, req *CreateFieldRequest) error
+	GetAllFields(ctx context.Context) ([]domain.Field, error)
+	GetFieldByID(ctx context.Context, id uint) (*domain.Field, error)
+	UpdateField(ctx context.Context, id uint, req *CreateFieldRequest) error
+	DeleteField(ctx context.Context, id uint, policy string) error
 }
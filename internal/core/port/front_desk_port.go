@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// GuestContact is a walk-in or phone customer without an account.
+type GuestContact struct {
+	Name  string `json:"name" example:"Andi"`
+	Phone string `json:"phone" example:"+62 812-3456-7890"`
+}
+
+// FrontDeskBookingRequest is a booking an admin takes for a customer, an
+// existing user or a guest, exactly one of which is given.
+type FrontDeskBookingRequest struct {
+	BookingRequest
+	UserID uint          `json:"user_id"`
+	Guest  *GuestContact `json:"guest"`
+	// PaymentMethod "cash" records the booking as paid at the counter, with
+	// PaymentReference as the receipt number if given. Without it the
+	// booking is pending like any other.
+	PaymentMethod    string `json:"payment_method" example:"cash"`
+	PaymentReference string `json:"payment_reference"`
+	// Overrides names the booking rules to waive, past_start or
+	// opening_hours; OverrideReason, required with them, says why.
+	Overrides      []string `json:"overrides" example:"opening_hours"`
+	OverrideReason string   `json:"override_reason" example:"Tournament final, venue stays open late"`
+}
+
+type FrontDeskService interface {
+	// CreateBooking books for a customer on behalf of the admin staffID,
+	// who is recorded on the booking.
+	CreateBooking(ctx context.Context, staffID uint, req *FrontDeskBookingRequest) (*domain.Booking, error)
+}
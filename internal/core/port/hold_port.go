@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// DTO
+type HoldRequest struct {
+	FieldID   uint      `json:"field_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// Minutes is how long to hold the slot, up to SLOT_HOLD_MAX; omitted
+	// holds it for the maximum.
+	Minutes int `json:"minutes" example:"10"`
+}
+
+type HoldRepository interface {
+	// Create places hold if, under the field lock, its slot is neither
+	// booked nor held for anyone else. The user's own overlapping checkout
+	// holds on the field are replaced; a user who would then have more than
+	// maxActive checkout holds is refused with a conflict.
+	Create(ctx context.Context, hold *domain.SlotHold, maxActive int) error
+	GetByID(ctx context.Context, id uint) (*domain.SlotHold, error)
+	// ListActiveByUser returns the user's holds expiring after now, waitlist
+	// offers included, soonest first.
+	ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]domain.SlotHold, error)
+	// Release deletes a hold, writing a hold.released event. Releasing the
+	// hold of a waitlist offer declines the offer, writing a
+	// waitlist.released event instead.
+	Release(ctx context.Context, id uint) error
+	// DeleteExpired removes up to limit checkout holds that expired before
+	// now, writing a hold.released event for each, and returns how many.
+	// Waitlist offers are lapsed by WaitlistRepository.ExpireOffers instead.
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error)
+}
+
+type HoldService interface {
+	PlaceHold(ctx context.Context, userID uint, req *HoldRequest) (*domain.SlotHold, error)
+	GetMyHolds(ctx context.Context, userID uint) ([]domain.SlotHold, error)
+	ReleaseHold(ctx context.Context, userID, id uint) error
+	// Checkout books the held slot for the hold's owner, consuming the hold.
+	Checkout(ctx context.Context, userID, id uint) (*domain.Booking, error)
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// ImportRequest carries the CSV files to import; either may be nil.
+// Bookings may refer to fields from the same import by name.
+type ImportRequest struct {
+	Fields   io.Reader
+	Bookings io.Reader
+	// DryRun validates every row without importing anything.
+	DryRun bool
+}
+
+type ImportRepository interface {
+	// Import creates fields and then bookings in one transaction. A booking
+	// whose Field is set belongs to that new field; the others keep their
+	// FieldID. Imported bookings publish no events.
+	Import(ctx context.Context, fields []domain.Field, bookings []domain.Booking) error
+}
+
+type ImportService interface {
+	// Import checks every row and imports them all, or nothing when any is
+	// invalid or on a dry run. Row problems are reported in the result; an
+	// error means the import itself failed.
+	Import(ctx context.Context, req *ImportRequest) (*domain.ImportResult, error)
+}
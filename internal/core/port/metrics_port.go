@@ -0,0 +1,12 @@
+package port
+
+import "context"
+
+// BookingMetrics records booking business events for monitoring.
+type BookingMetrics interface {
+	BookingCreated(ctx context.Context)
+	BookingConflict(ctx context.Context)
+	PaymentSucceeded(ctx context.Context)
+	PaymentFailed(ctx context.Context)
+	BookingExpired(ctx context.Context)
+}
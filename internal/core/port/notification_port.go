@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// Notifier is a delivery channel for rendered notifications: SMTP, a
+// directory of .eml files or the application log.
+type Notifier interface {
+	Send(ctx context.Context, n *domain.Notification) error
+}
+
+// BookingNotifier tells a customer about their booking: it picks the
+// template for kind in the customer's language, honours their preferences
+// and hands the message to a Notifier.
+type BookingNotifier interface {
+	NotifyBooking(ctx context.Context, kind string, booking *domain.Booking, reason string) error
+	// NotifyWaitlistOffer tells a waiting customer their slot is free and
+	// held for them until the entry's HoldExpiresAt.
+	NotifyWaitlistOffer(ctx context.Context, entry *domain.WaitlistEntry) error
+}
+
+// DTO
+type NotificationPreferenceRequest struct {
+	Language               string `json:"language"`
+	OptOutNonTransactional bool   `json:"opt_out_non_transactional"`
+}
+
+type NotificationPreferenceRepository interface {
+	// Get returns nil without error when the user has no saved preference.
+	Get(ctx context.Context, userID uint) (*domain.NotificationPreference, error)
+	Save(ctx context.Context, pref *domain.NotificationPreference) error
+}
+
+type NotificationPreferenceService interface {
+	GetPreference(ctx context.Context, userID uint) (*domain.NotificationPreference, error)
+	UpdatePreference(ctx context.Context, userID uint, req *NotificationPreferenceRequest) (*domain.NotificationPreference, error)
+}
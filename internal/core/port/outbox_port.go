@@ -0,0 +1,39 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// OutboxRepository is the dispatcher's view of outbox_events. Events are
+// written by the other repositories inside their own transactions.
+type OutboxRepository interface {
+	// ClaimDue leases up to limit due events until leaseUntil, with the
+	// subscribers that already handled each in DeliveredTo. Events leased by
+	// another instance are skipped, so several dispatchers can run at once.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error)
+	// MarkSubscriberDelivered records that subscriber handled event, so
+	// later attempts at the event skip it.
+	MarkSubscriberDelivered(ctx context.Context, event *domain.OutboxEvent, subscriber string, at time.Time) error
+	// MarkDelivered and MarkFailed only apply while the caller still holds
+	// the lease it was given by ClaimDue.
+	MarkDelivered(ctx context.Context, event *domain.OutboxEvent, at time.Time) error
+	MarkFailed(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// EventHandler consumes outbox events. A handler is not called again for an
+// event it handled, but delivery is still at-least-once: a handler that
+// succeeds just before its instance dies sees the event again, so handlers
+// must tolerate duplicates.
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// EventHandlerFunc adapts a function to EventHandler.
+type EventHandlerFunc func(ctx context.Context, event *domain.OutboxEvent) error
+
+func (f EventHandlerFunc) HandleEvent(ctx context.Context, event *domain.OutboxEvent) error {
+	return f(ctx, event)
+}
@@ -0,0 +1,62 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// DTO
+type ReportQuery struct {
+	// From and To are the first and last local days (YYYY-MM-DD) of the
+	// report; the last 30 days up to today when omitted.
+	From string `query:"from" example:"2025-06-01"`
+	To   string `query:"to" example:"2025-06-30"`
+	// FieldID limits the report to one field; 0 covers all of them.
+	FieldID uint `query:"field_id"`
+}
+
+// ReportRange is a validated ReportQuery. Bookings fall in it when they
+// start on a day between From and To, inclusive, in their field's time
+// zone; From and To are those calendar dates at midnight UTC.
+type ReportRange struct {
+	From    time.Time
+	To      time.Time
+	FieldID uint
+}
+
+// FieldHours is the confirmed hours booked on a field.
+type FieldHours struct {
+	FieldID uint
+	Hours   float64
+}
+
+// ReportRepository aggregates bookings in a range. Only bookings that are
+// not deleted count; fields deleted since still appear.
+type ReportRepository interface {
+	// Revenue sums confirmed bookings per period (a domain.ReportPeriod*)
+	// and grouping (a domain.ReportGroup*), oldest period first.
+	Revenue(ctx context.Context, rng ReportRange, period, group string) ([]domain.RevenueRow, error)
+	BookedHours(ctx context.Context, rng ReportRange) ([]FieldHours, error)
+	// Heatmap returns the non-empty cells only.
+	Heatmap(ctx context.Context, rng ReportRange) ([]domain.HeatmapCell, error)
+	// Outcomes counts each field's bookings by status; the rates are left
+	// for the caller.
+	Outcomes(ctx context.Context, rng ReportRange) ([]domain.CancellationRow, error)
+	// TopCustomers returns up to limit customers by confirmed revenue.
+	TopCustomers(ctx context.Context, rng ReportRange, limit int) ([]domain.CustomerRow, error)
+}
+
+type ReportService interface {
+	Revenue(ctx context.Context, q *ReportQuery, period, group string) ([]domain.RevenueRow, error)
+	// Utilization covers the fields open today, comparing confirmed hours
+	// with opening hours over the range.
+	Utilization(ctx context.Context, q *ReportQuery) ([]domain.UtilizationRow, error)
+	// Heatmap returns all 7x24 weekday and local hour cells, Monday 00:00
+	// first.
+	Heatmap(ctx context.Context, q *ReportQuery) ([]domain.HeatmapCell, error)
+	// Cancellations returns one row per field and a final total row.
+	Cancellations(ctx context.Context, q *ReportQuery) ([]domain.CancellationRow, error)
+	TopCustomers(ctx context.Context, q *ReportQuery, limit int) ([]domain.CustomerRow, error)
+}
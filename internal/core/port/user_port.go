@@ -1,6 +1,11 @@
 package port
 
-import "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
 
 // DTO
 type RegisterRequest struct {
@@ -13,6 +18,7 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	ClientIP string `json:"-"`
 }
 
 type LoginResponse struct {
@@ -21,12 +27,26 @@ type LoginResponse struct {
 
 // Repository Interface
 type UserRepository interface {
-	CreateUser(user *domain.User) error
-	GetByEmail(email string) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User) error
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetByID(ctx context.Context, id uint) (*domain.User, error)
+}
+
+// LoginGuard throttles repeated failed logins for a key (client IP or
+// account). Check and Fail return how long the key is blocked for.
+type LoginGuard interface {
+	Check(ctx context.Context, key string) time.Duration
+	Fail(ctx context.Context, key string) time.Duration
+	Reset(ctx context.Context, key string)
+}
+
+// TokenIssuer signs access tokens for authenticated users.
+type TokenIssuer interface {
+	GenerateToken(ctx context.Context, userID uint, role string) (string, error)
 }
 
 // Service Interface
 type UserService interface {
-	Register(req *RegisterRequest) error
-	Login(req *LoginRequest) (*LoginResponse, error)
+	Register(ctx context.Context, req *RegisterRequest) error
+	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
 }
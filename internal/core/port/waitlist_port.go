@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// DTO
+type WaitlistRequest struct {
+	FieldID   uint      `json:"field_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+type WaitlistRepository interface {
+	// Create adds a waiting entry; joining twice for the same slot is a
+	// conflict.
+	Create(ctx context.Context, entry *domain.WaitlistEntry) error
+	GetByID(ctx context.Context, id uint) (*domain.WaitlistEntry, error)
+	ListByUser(ctx context.Context, userID uint) ([]domain.WaitlistEntry, error)
+	// Cancel withdraws a waiting or offered entry; finished entries are left
+	// as they are. Withdrawing an offer releases its hold and writes a
+	// waitlist.released event.
+	Cancel(ctx context.Context, id uint) error
+	// OfferFreed goes through the entries waiting for slots on fieldID that
+	// overlap start-end, in the order they joined, and gives each one whose
+	// whole slot is now free a hold until holdUntil, writing a
+	// waitlist.offered event for it. Entries for slots that have already
+	// started are skipped.
+	OfferFreed(ctx context.Context, fieldID uint, start, end, now, holdUntil time.Time) ([]domain.WaitlistEntry, error)
+	// ExpireOffers marks up to limit offered entries whose hold ran out
+	// before now as lapsed, releases their holds with a waitlist.released
+	// event each, and returns them.
+	ExpireOffers(ctx context.Context, now time.Time, limit int) ([]domain.WaitlistEntry, error)
+}
+
+type WaitlistService interface {
+	JoinWaitlist(ctx context.Context, userID uint, req *WaitlistRequest) (*domain.WaitlistEntry, error)
+	GetMyWaitlist(ctx context.Context, userID uint) ([]domain.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, userID, id uint) error
+	// SlotFreed offers a field interval that became free to the customers
+	// waiting for it.
+	SlotFreed(ctx context.Context, fieldID uint, start, end time.Time) error
+}
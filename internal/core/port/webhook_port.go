@@ -0,0 +1,71 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// DTO
+type WebhookRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	// Secret signs deliveries. It is generated when left empty on create;
+	// on update a non-empty value rotates it.
+	Secret string `json:"secret"`
+	// Active re-enables (true) or pauses (false) the subscription on update.
+	Active *bool `json:"active"`
+}
+
+// WebhookSecretResponse is returned by create and update. Secret is only
+// filled in when it was created or rotated, the only times it is shown.
+type WebhookSecretResponse struct {
+	domain.WebhookSubscription
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookRepository manages subscriptions and their delivery log for admins.
+type WebhookRepository interface {
+	Create(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetAll(ctx context.Context) ([]domain.WebhookSubscription, error)
+	GetByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error)
+	// Update saves only the named columns of sub, keeping what the sender
+	// changed meanwhile, such as the failure count.
+	Update(ctx context.Context, sub *domain.WebhookSubscription, columns ...string) error
+	Delete(ctx context.Context, id uint) error
+	// ListDeliveries returns the newest deliveries of a subscription first.
+	ListDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]domain.WebhookDelivery, error)
+	// GetDelivery includes the delivery's attempt log.
+	GetDelivery(ctx context.Context, subscriptionID, deliveryID uint) (*domain.WebhookDelivery, error)
+	// Requeue makes a delivery due again with a fresh attempt budget.
+	Requeue(ctx context.Context, subscriptionID, deliveryID uint, at time.Time) (*domain.WebhookDelivery, error)
+}
+
+// WebhookDeliveryRepository is the sender's view of the delivery queue.
+type WebhookDeliveryRepository interface {
+	// Enqueue queues body for every active subscription to the event's type
+	// and returns how many deliveries were created. Queuing the same event
+	// twice is a no-op.
+	Enqueue(ctx context.Context, event *domain.OutboxEvent, body []byte) (int, error)
+	// ClaimDue leases up to limit due deliveries of active subscriptions,
+	// with Subscription loaded.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// RecordAttempt logs attempt and stores the delivery's new state, provided
+	// the caller still holds its lease. It updates the subscription's failure
+	// streak and disables it once the streak reaches disableAfter, reporting
+	// whether it did.
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt, disableAfter int) (bool, error)
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *WebhookRequest) (*WebhookSecretResponse, error)
+	GetAllWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error)
+	GetWebhookByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, id uint, req *WebhookRequest) (*WebhookSecretResponse, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, id uint, limit int) ([]domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id, deliveryID uint) (*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id, deliveryID uint) (*domain.WebhookDelivery, error)
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// exportTimeout bounds a whole export. The body is streamed after the
+// handler returns, past the request's own REQUEST_TIMEOUT deadline.
+const exportTimeout = 10 * time.Minute
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// bookingExportHeader names the columns of a booking export. Times are UTC
+// except local_start, which is in the field's time zone.
+var bookingExportHeader = []string{
+	"booking_id", "status", "start_time", "end_time", "local_start", "minutes",
+	"field_id", "field_name", "venue", "time_zone",
+	"user_id", "user_name", "user_email",
+	"price_per_hour", "amount_paid", "payment_reference",
+	"created_at", "paid_at", "cancelled_at", "expired_at", "no_show_at",
+}
+
+// ExportBookings godoc
+// @Summary      Export bookings (Admin Only)
+// @Description  Download bookings with their field, customer, amount paid, payment reference and status times as
+// @Description  CSV or Excel, by start time, with the same filters as the booking list. Rows are streamed as they are
+// @Description  read. Times are UTC except local_start, in the field's time zone.
+// @Tags         Bookings
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security     BearerAuth
+// @Param        format query string false "csv (default) or xlsx"
+// @Param        status query string false "pending, paid, cancelled, expired or no_show"
+// @Param        field_id query int false "Only this field"
+// @Param        user_id query int false "Only this customer"
+// @Param        from query string false "Starting on or after, YYYY-MM-DD"
+// @Param        to query string false "Starting on or before, YYYY-MM-DD"
+// @Success      200 {file} file
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/bookings/export [get]
+func (h *BookingHandler) Export(c *fiber.Ctx) error {
+	q, err := bookingQuery(c)
+	if err != nil {
+		return err
+	}
+	var newWriter func(io.Writer) (exportWriter, error)
+	format := c.Query("format", "csv")
+	switch format {
+	case "csv":
+		newWriter = newCSVExport
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	case "xlsx":
+		newWriter = newXLSXExport
+		c.Set(fiber.HeaderContentType, xlsxContentType)
+	default:
+		return domain.NewValidationError("format must be csv or xlsx")
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), exportTimeout)
+	rows, err := h.service.ExportBookings(ctx, q)
+	if err != nil {
+		cancel()
+		return err
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="bookings-`+time.Now().UTC().Format("20060102")+`.`+format+`"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		defer rows.Close()
+		if err := writeBookings(w, newWriter, rows); err != nil {
+			logging.FromContext(ctx).Error("booking export failed", "format", format, "error", err)
+		}
+	})
+	return nil
+}
+
+func bookingQuery(c *fiber.Ctx) (*port.BookingQuery, error) {
+	var q port.BookingQuery
+	if err := c.QueryParser(&q); err != nil {
+		return nil, domain.NewValidationError("field_id and user_id must be numbers")
+	}
+	return &q, nil
+}
+
+// writeBookings writes the header and every row. Once streaming has begun
+// the status can no longer change, so a failure is marked in the file.
+func writeBookings(w io.Writer, newWriter func(io.Writer) (exportWriter, error), rows port.BookingRows) error {
+	out, err := newWriter(w)
+	if err != nil {
+		return err
+	}
+	header := make([]interface{}, len(bookingExportHeader))
+	for i, name := range bookingExportHeader {
+		header[i] = name
+	}
+	if err := out.Write(header); err != nil {
+		out.Fail()
+		return err
+	}
+
+	var row domain.BookingExportRow
+	for rows.Next() {
+		if err := rows.Scan(&row); err != nil {
+			out.Fail()
+			return err
+		}
+		if err := out.Write(exportValues(&row)); err != nil {
+			out.Fail()
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		out.Fail()
+		return err
+	}
+	return out.Close()
+}
+
+// exportValues lists row's values in bookingExportHeader order; nil stands
+// for an empty cell.
+func exportValues(row *domain.BookingExportRow) []interface{} {
+	return []interface{}{
+		row.BookingID, row.Status, row.StartTime, row.EndTime, row.LocalStart, row.Minutes,
+		row.FieldID, row.FieldName, row.Venue, row.TimeZone,
+		row.UserID, row.UserName, row.UserEmail,
+		row.PricePerHour, optional(row.AmountPaid), row.PaymentReference,
+		row.CreatedAt, optional(row.PaidAt), optional(row.CancelledAt), optional(row.ExpiredAt), optional(row.NoShowAt),
+	}
+}
+
+func optional[T any](v *T) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// exportWriter writes a file row by row.
+type exportWriter interface {
+	Write(values []interface{}) error
+	// Close finishes the file.
+	Close() error
+	// Fail ends a file that could not be finished.
+	Fail()
+}
+
+type csvExport struct {
+	w *csv.Writer
+}
+
+func newCSVExport(w io.Writer) (exportWriter, error) {
+	return &csvExport{w: csv.NewWriter(w)}, nil
+}
+
+func (e *csvExport) Write(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = csvSafe(v)
+		case time.Time:
+			record[i] = v.UTC().Format(time.RFC3339)
+		case uint:
+			record[i] = strconv.FormatUint(uint64(v), 10)
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExport) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Fail leaves a last line saying so, since the rows above it look complete.
+func (e *csvExport) Fail() {
+	e.w.Write([]string{"export failed; this file is incomplete"})
+	e.w.Flush()
+}
+
+// xlsxExport streams rows into a worksheet; excelize keeps them in a
+// temporary file rather than in memory once they grow large, and the
+// workbook is written out when it is closed.
+type xlsxExport struct {
+	file      *excelize.File
+	sheet     *excelize.StreamWriter
+	out       io.Writer
+	row       int
+	timeStyle int
+}
+
+func newXLSXExport(w io.Writer) (exportWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", "Bookings"); err != nil {
+		file.Close()
+		return nil, err
+	}
+	format := "yyyy-mm-dd hh:mm:ss"
+	timeStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	sheet, err := file.NewStreamWriter("Bookings")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxExport{file: file, sheet: sheet, out: w, timeStyle: timeStyle}, nil
+}
+
+func (e *xlsxExport) Write(values []interface{}) error {
+	e.row++
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			cells[i] = excelize.Cell{StyleID: e.timeStyle, Value: t.UTC()}
+		} else {
+			cells[i] = v
+		}
+	}
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.sheet.SetRow(cell, cells)
+}
+
+func (e *xlsxExport) Close() error {
+	defer e.file.Close()
+	if err := e.sheet.Flush(); err != nil {
+		return err
+	}
+	_, err := e.file.WriteTo(e.out)
+	return err
+}
+
+// Fail writes nothing: a workbook cut short could not be opened anyway.
+func (e *xlsxExport) Fail() {
+	e.file.Close()
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+	"github.com/xuri/excelize/v2"
+)
+
+// sliceRows serves export rows from memory, failing after failAfter rows
+// when it is set.
+type sliceRows struct {
+	rows      []domain.BookingExportRow
+	next      int
+	failAfter int
+	closed    bool
+}
+
+func (s *sliceRows) Next() bool {
+	if s.failAfter > 0 && s.next == s.failAfter {
+		return false
+	}
+	s.next++
+	return s.next <= len(s.rows)
+}
+
+func (s *sliceRows) Scan(row *domain.BookingExportRow) error {
+	*row = s.rows[s.next-1]
+	return nil
+}
+
+func (s *sliceRows) Err() error {
+	if s.failAfter > 0 && s.next == s.failAfter {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func (s *sliceRows) Close() error {
+	s.closed = true
+	return nil
+}
+
+func exportFixture() []domain.BookingExportRow {
+	paid := time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC)
+	amount := int64(150000)
+	return []domain.BookingExportRow{
+		{
+			BookingID: 7, Status: domain.BookingStatusPaid,
+			StartTime: time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC),
+			LocalStart: "2025-06-02 18:00", Minutes: 90,
+			FieldID: 1, FieldName: "Court A", Venue: "Jakarta", TimeZone: "Asia/Jakarta",
+			UserID: 3, UserName: "=cmd|' /C calc'!A0", UserEmail: "budi@example.com",
+			PricePerHour: 100000, AmountPaid: &amount, PaymentReference: "TRF-123",
+			CreatedAt: paid.Add(-time.Hour), PaidAt: &paid,
+		},
+		{BookingID: 8, Status: domain.BookingStatusPending, FieldID: 1, UserID: 4},
+	}
+}
+
+func newExportApp(svc *mockBookingService) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewBookingHandler(svc, newTestMetrics())
+	app.Get("/admin/bookings/export", h.Export)
+	return app
+}
+
+func TestBookingHandler_ExportCSV(t *testing.T) {
+	rows := &sliceRows{rows: exportFixture()}
+	svc := &mockBookingService{exportRows: rows}
+	app := newExportApp(svc)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/admin/bookings/export?status=paid&field_id=1&from=2025-06-01", nil))
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("expected a CSV, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if *svc.query != (port.BookingQuery{Status: "paid", FieldID: 1, From: "2025-06-01"}) {
+		t.Fatalf("unexpected query %+v", svc.query)
+	}
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("expected a header and two rows, got %v (%v)", records, err)
+	}
+	header := map[string]int{}
+	for i, name := range records[0] {
+		header[name] = i
+	}
+	if _, ok := header["password"]; ok || len(header) != len(bookingExportHeader) {
+		t.Fatalf("unexpected header %v", records[0])
+	}
+	first := records[1]
+	for column, want := range map[string]string{
+		"booking_id":        "7",
+		"start_time":        "2025-06-02T11:00:00Z",
+		"local_start":       "2025-06-02 18:00",
+		"amount_paid":       "150000",
+		"payment_reference": "TRF-123",
+		"paid_at":           "2025-06-01T09:30:00Z",
+		"cancelled_at":      "",
+		"user_name":         "'=cmd|' /C calc'!A0",
+	} {
+		if got := first[header[column]]; got != want {
+			t.Fatalf("%s: expected %q, got %q", column, want, got)
+		}
+	}
+	if records[2][header["amount_paid"]] != "" || records[2][header["paid_at"]] != "" {
+		t.Fatalf("expected an unpaid booking to leave payment columns empty, got %v", records[2])
+	}
+	if !rows.closed {
+		t.Fatal("expected the rows to be closed")
+	}
+}
+
+func TestBookingHandler_ExportXLSX(t *testing.T) {
+	app := newExportApp(&mockBookingService{exportRows: &sliceRows{rows: exportFixture()}})
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/admin/bookings/export?format=xlsx", nil))
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != xlsxContentType {
+		t.Fatalf("expected a workbook, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	book, err := excelize.OpenReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("open workbook: %v", err)
+	}
+	defer book.Close()
+	sheet, err := book.GetRows("Bookings")
+	if err != nil || len(sheet) != 3 {
+		t.Fatalf("expected a header and two rows, got %v (%v)", sheet, err)
+	}
+	if sheet[0][0] != "booking_id" || sheet[1][0] != "7" || sheet[1][2] != "2025-06-02 11:00:00" {
+		t.Fatalf("unexpected rows %v", sheet[:2])
+	}
+}
+
+func TestBookingHandler_ExportErrors(t *testing.T) {
+	app := newExportApp(&mockBookingService{exportRows: &sliceRows{rows: exportFixture()}})
+	for _, target := range []string{
+		"/admin/bookings/export?format=pdf",
+		"/admin/bookings/export?field_id=x",
+		"/admin/bookings/export?status=refunded",
+	} {
+		if resp, _ := app.Test(httptest.NewRequest(http.MethodGet, target, nil)); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", target, resp.StatusCode)
+		}
+	}
+
+	// A failure after the first row can only be reported in the file.
+	app = newExportApp(&mockBookingService{exportRows: &sliceRows{rows: exportFixture(), failAfter: 1}})
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/admin/bookings/export", nil))
+	reader := csv.NewReader(resp.Body)
+	reader.FieldsPerRecord = -1
+	records, _ := reader.ReadAll()
+	if len(records) != 3 || records[2][0] != "export failed; this file is incomplete" {
+		t.Fatalf("expected the file to end with a failure line, got %v", records)
+	}
+}
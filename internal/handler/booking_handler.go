@@ -1,18 +1,20 @@
 package handler
 
 import (
-	"strconv"
+	"errors"
 
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
 	"github.com/gofiber/fiber/v2"
 )
 
 type BookingHandler struct {
 	service port.BookingService
+	metrics port.BookingMetrics
 }
 
-func NewBookingHandler(service port.BookingService) *BookingHandler {
-	return &BookingHandler{service: service}
+func NewBookingHandler(service port.BookingService, metrics port.BookingMetrics) *BookingHandler {
+	return &BookingHandler{service: service, metrics: metrics}
 }
 
 // CreateBooking godoc
@@ -23,9 +25,14 @@ func NewBookingHandler(service port.BookingService) *BookingHandler {
 // @Produce      json
 // @Security     BearerAuth
 // @Param        booking body port.BookingRequest true "Booking Data"
+// @Param        Idempotency-Key header string false "Unique key; retries with the same key replay the first response for 24h"
 // @Success      201 {object} port.DataResponse
-// @Failure      400 {object} port.ErrorResponse "Invalid Input / Overlap"
+// @Failure      400 {object} port.ErrorResponse "Invalid Input"
 // @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      409 {object} port.ErrorResponse "Schedule Overlap / Idempotency-Key In Progress"
+// @Failure      422 {object} port.ErrorResponse "Idempotency-Key Reused With Different Body"
+// @Failure      429 {object} port.ErrorResponse "Rate Limited"
+// @Failure      500 {object} port.ErrorResponse
 // @Router       /bookings [post]
 func (h *BookingHandler) Create(c *fiber.Ctx) error {
 	userIDFloat, ok := c.Locals("user_id").(float64)
@@ -39,10 +46,14 @@ func (h *BookingHandler) Create(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input format"})
 	}
 
-	booking, err := h.service.CreateBooking(userID, &req)
+	booking, err := h.service.CreateBooking(c.UserContext(), userID, &req)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		if errors.Is(err, domain.ErrConflict) {
+			h.metrics.BookingConflict(c.UserContext())
+		}
+		return err
 	}
+	h.metrics.BookingCreated(c.UserContext())
 
 	return c.Status(201).JSON(fiber.Map{
 		"message": "Booking created successfully",
@@ -52,17 +63,28 @@ func (h *BookingHandler) Create(c *fiber.Ctx) error {
 
 // GetAllBookings godoc
 // @Summary      Get all bookings history
-// @Description  Retrieve a list of all bookings (Admin/User).
+// @Description  Retrieve a list of all bookings (Admin/User), newest first. Dates are the local days bookings start
+// @Description  on in their field's time zone.
 // @Tags         Bookings
 // @Produce      json
 // @Security     BearerAuth
+// @Param        status query string false "pending, paid, cancelled, expired or no_show"
+// @Param        field_id query int false "Only this field"
+// @Param        user_id query int false "Only this customer"
+// @Param        from query string false "Starting on or after, YYYY-MM-DD"
+// @Param        to query string false "Starting on or before, YYYY-MM-DD"
 // @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
 // @Failure      500 {object} port.ErrorResponse
 // @Router       /bookings [get]
 func (h *BookingHandler) GetAll(c *fiber.Ctx) error {
-	bookings, err := h.service.GetAllBookings()
+	q, err := bookingQuery(c)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return err
+	}
+	bookings, err := h.service.GetAllBookings(c.UserContext(), q)
+	if err != nil {
+		return err
 	}
 	return c.JSON(fiber.Map{
 		"message": "Success retrieving bookings",
@@ -78,14 +100,18 @@ func (h *BookingHandler) GetAll(c *fiber.Ctx) error {
 // @Security     BearerAuth
 // @Param        id path int true "Booking ID"
 // @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
 // @Failure      404 {object} port.ErrorResponse
 // @Router       /bookings/{id} [get]
 func (h *BookingHandler) GetByID(c *fiber.Ctx) error {
-	id, _ := strconv.Atoi(c.Params("id"))
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
 
-	booking, err := h.service.GetBookingByID(uint(id))
+	booking, err := h.service.GetBookingByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Booking not found"})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -94,28 +120,95 @@ func (h *BookingHandler) GetByID(c *fiber.Ctx) error {
 	})
 }
 
+// GetFieldAvailability godoc
+// @Summary      Get a field's availability for a day
+// @Description  The field's opening hours on a local day with the intervals taken by bookings and unexpired
+// @Description  holds, and the free ones in between. Times are UTC; date is in the field's time zone.
+// @Tags         Bookings
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Field ID"
+// @Param        date query string false "Local date (YYYY-MM-DD), today by default"
+// @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /fields/{id}/availability [get]
+func (h *BookingHandler) GetAvailability(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	availability, err := h.service.GetAvailability(c.UserContext(), id, c.Query("date"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Success retrieving availability",
+		"data":    availability,
+	})
+}
+
 // PayBooking godoc
 // @Summary      Pay for a booking (Mock Payment)
-// @Description  Change booking status from pending to paid.
+// @Description  Change booking status from pending to paid, recording the amount at the field's current price and
+// @Description  the payment reference (generated when omitted) for reconciliation.
 // @Tags         Payments
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        payment body object{booking_id=int} true "JSON: {booking_id: 1}"
+// @Param        payment body object{booking_id=int,payment_reference=string} true "JSON: {booking_id: 1, payment_reference: TRF-123 (optional)}"
+// @Param        Idempotency-Key header string false "Unique key; retries with the same key replay the first response for 24h"
 // @Success      200 {object} port.MessageResponse
 // @Failure      400 {object} port.ErrorResponse
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      409 {object} port.ErrorResponse "Booking Expired or Cancelled / Idempotency-Key In Progress"
+// @Failure      422 {object} port.ErrorResponse "Idempotency-Key Reused With Different Body"
+// @Failure      429 {object} port.ErrorResponse "Rate Limited"
+// @Failure      500 {object} port.ErrorResponse
 // @Router       /payments [post]
 func (h *BookingHandler) Pay(c *fiber.Ctx) error {
 	var req struct {
-		BookingID uint `json:"booking_id"`
+		BookingID        uint   `json:"booking_id"`
+		PaymentReference string `json:"payment_reference"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
 	}
 
-	if err := h.service.PayBooking(req.BookingID); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	if err := h.service.PayBooking(c.UserContext(), req.BookingID, req.PaymentReference); err != nil {
+		h.metrics.PaymentFailed(c.UserContext())
+		return err
 	}
+	h.metrics.PaymentSucceeded(c.UserContext())
 
 	return c.JSON(fiber.Map{"message": "Payment successful, booking status updated to paid"})
 }
+
+// MarkNoShow godoc
+// @Summary      Mark a booking as a no-show (Admin Only)
+// @Description  Record that the customer of a paid booking did not turn up, once it has started. The slot stays
+// @Description  paid for; the booking counts as a no-show in reports. Marking it again is harmless.
+// @Tags         Bookings
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Booking ID"
+// @Success      200 {object} port.MessageResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      409 {object} port.ErrorResponse "Booking Not Paid Or Not Started"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/bookings/{id}/no-show [post]
+func (h *BookingHandler) MarkNoShow(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.MarkNoShow(c.UserContext(), id); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "Booking marked as no-show"})
+}
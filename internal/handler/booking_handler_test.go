@@ -1,19 +1,28 @@
 package handler
 
 import (
+    "context"
     "bytes"
     "encoding/json"
     "errors"
     "net/http"
     "net/http/httptest"
+    "strings"
     "testing"
     "time"
 
     "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
     "github.com/HIUNCY/sagara-booking-api/internal/core/port"
+    "github.com/HIUNCY/sagara-booking-api/pkg/metrics"
     "github.com/gofiber/fiber/v2"
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/testutil"
 )
 
+func newTestMetrics() *metrics.Metrics {
+    return metrics.New(prometheus.NewRegistry())
+}
+
 type mockBookingService struct {
     createResp *domain.Booking
     createErr  error
@@ -22,25 +31,40 @@ type mockBookingService struct {
     byIDResp   *domain.Booking
     byIDErr    error
     payErr     error
+    exportRows port.BookingRows
+    query      *port.BookingQuery
 }
 
-func (m *mockBookingService) CreateBooking(userID uint, req *port.BookingRequest) (*domain.Booking, error) {
+func (m *mockBookingService) CreateBooking(ctx context.Context, userID uint, req *port.BookingRequest) (*domain.Booking, error) {
     if m.createErr != nil { return nil, m.createErr }
     return m.createResp, nil
 }
-func (m *mockBookingService) PayBooking(bookingID uint) error { return m.payErr }
-func (m *mockBookingService) GetAllBookings() ([]domain.Booking, error) {
+func (m *mockBookingService) PayBooking(ctx context.Context, bookingID uint, reference string) error { return m.payErr }
+func (m *mockBookingService) GetAllBookings(ctx context.Context, q *port.BookingQuery) ([]domain.Booking, error) {
     if m.allErr != nil { return nil, m.allErr }
     return m.allResp, nil
 }
-func (m *mockBookingService) GetBookingByID(id uint) (*domain.Booking, error) {
+func (m *mockBookingService) GetBookingByID(ctx context.Context, id uint) (*domain.Booking, error) {
     if m.byIDErr != nil { return nil, m.byIDErr }
     return m.byIDResp, nil
 }
+func (m *mockBookingService) GetAvailability(ctx context.Context, fieldID uint, date string) (*domain.Availability, error) {
+    if date == "bad" { return nil, domain.NewValidationError("date must be in YYYY-MM-DD form") }
+    return &domain.Availability{FieldID: fieldID, Date: date}, nil
+}
+func (m *mockBookingService) ExportBookings(ctx context.Context, q *port.BookingQuery) (port.BookingRows, error) {
+    m.query = q
+    if q.Status == "refunded" { return nil, domain.NewValidationError("status must be pending, paid, cancelled, expired or no_show") }
+    return m.exportRows, nil
+}
+func (m *mockBookingService) MarkNoShow(ctx context.Context, bookingID uint) error {
+    if bookingID != 1 { return domain.NewConflictError("booking has not started yet") }
+    return nil
+}
 
 func TestBookingHandler_Create_UnauthorizedAndSuccess(t *testing.T) {
-    app := fiber.New()
-    h := NewBookingHandler(&mockBookingService{createResp: &domain.Booking{}})
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewBookingHandler(&mockBookingService{createResp: &domain.Booking{}}, newTestMetrics())
     app.Post("/bookings", func(c *fiber.Ctx) error { return h.Create(c) })
 
     // unauthorized (no locals)
@@ -54,7 +78,7 @@ func TestBookingHandler_Create_UnauthorizedAndSuccess(t *testing.T) {
     }
 
     // success with user_id set
-    app2 := fiber.New()
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     app2.Post("/bookings", func(c *fiber.Ctx) error {
         c.Locals("user_id", float64(5))
         return h.Create(c)
@@ -67,7 +91,7 @@ func TestBookingHandler_Create_UnauthorizedAndSuccess(t *testing.T) {
     }
 
     // invalid json
-    app3 := fiber.New()
+    app3 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     app3.Post("/bookings", func(c *fiber.Ctx) error { c.Locals("user_id", float64(1)); return h.Create(c) })
     req3 := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader([]byte("{")))
     req3.Header.Set("Content-Type", "application/json")
@@ -76,21 +100,32 @@ func TestBookingHandler_Create_UnauthorizedAndSuccess(t *testing.T) {
         t.Fatalf("expected 400, got %d", resp3.StatusCode)
     }
 
-    // service error
-    app4 := fiber.New()
-    hErr := NewBookingHandler(&mockBookingService{createErr: errors.New("overlap")})
+    // overlap conflict
+    app4 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    hErr := NewBookingHandler(&mockBookingService{createErr: domain.NewConflictError("overlap")}, newTestMetrics())
     app4.Post("/bookings", func(c *fiber.Ctx) error { c.Locals("user_id", float64(1)); return hErr.Create(c) })
     req4 := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(b))
     req4.Header.Set("Content-Type", "application/json")
     resp4, _ := app4.Test(req4)
-    if resp4.StatusCode != http.StatusBadRequest {
-        t.Fatalf("expected 400 from service error, got %d", resp4.StatusCode)
+    if resp4.StatusCode != http.StatusConflict {
+        t.Fatalf("expected 409 from overlap, got %d", resp4.StatusCode)
+    }
+
+    // unexpected service error (e.g. database outage)
+    app5 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    hDown := NewBookingHandler(&mockBookingService{createErr: errors.New("dial tcp: connection refused")}, newTestMetrics())
+    app5.Post("/bookings", func(c *fiber.Ctx) error { c.Locals("user_id", float64(1)); return hDown.Create(c) })
+    req5 := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader(b))
+    req5.Header.Set("Content-Type", "application/json")
+    resp5, _ := app5.Test(req5)
+    if resp5.StatusCode != http.StatusInternalServerError {
+        t.Fatalf("expected 500 from unexpected error, got %d", resp5.StatusCode)
     }
 }
 
 func TestBookingHandler_GetAll_And_GetByID(t *testing.T) {
-    app := fiber.New()
-    h := NewBookingHandler(&mockBookingService{allResp: []domain.Booking{{}}, byIDResp: &domain.Booking{}})
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewBookingHandler(&mockBookingService{allResp: []domain.Booking{{}}, byIDResp: &domain.Booking{}}, newTestMetrics())
     app.Get("/bookings", h.GetAll)
     app.Get("/bookings/:id", h.GetByID)
 
@@ -102,8 +137,8 @@ func TestBookingHandler_GetAll_And_GetByID(t *testing.T) {
     }
 
     // get by id not found
-    app2 := fiber.New()
-    h2 := NewBookingHandler(&mockBookingService{byIDErr: errors.New("not found")})
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h2 := NewBookingHandler(&mockBookingService{byIDErr: domain.NewNotFoundError("booking not found")}, newTestMetrics())
     app2.Get("/bookings/:id", h2.GetByID)
     req2 := httptest.NewRequest(http.MethodGet, "/bookings/1", nil)
     resp2, _ := app2.Test(req2)
@@ -117,11 +152,18 @@ func TestBookingHandler_GetAll_And_GetByID(t *testing.T) {
     if resp3.StatusCode != http.StatusOK {
         t.Fatalf("expected 200, got %d", resp3.StatusCode)
     }
+
+    // get by id with malformed id
+    req4 := httptest.NewRequest(http.MethodGet, "/bookings/abc", nil)
+    resp4, _ := app.Test(req4)
+    if resp4.StatusCode != http.StatusBadRequest {
+        t.Fatalf("expected 400, got %d", resp4.StatusCode)
+    }
 }
 
 func TestBookingHandler_Pay(t *testing.T) {
-    app := fiber.New()
-    h := NewBookingHandler(&mockBookingService{})
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewBookingHandler(&mockBookingService{}, newTestMetrics())
     app.Post("/payments", h.Pay)
 
     // invalid json
@@ -143,8 +185,8 @@ func TestBookingHandler_Pay(t *testing.T) {
     }
 
     // service error
-    app2 := fiber.New()
-    hErr := NewBookingHandler(&mockBookingService{payErr: errors.New("boom")})
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    hErr := NewBookingHandler(&mockBookingService{payErr: errors.New("boom")}, newTestMetrics())
     app2.Post("/payments", hErr.Pay)
     req3 := httptest.NewRequest(http.MethodPost, "/payments", bytes.NewReader(b))
     req3.Header.Set("Content-Type", "application/json")
@@ -153,3 +195,91 @@ func TestBookingHandler_Pay(t *testing.T) {
         t.Fatalf("expected 500, got %d", resp3.StatusCode)
     }
 }
+
+func TestBookingHandler_RecordsBusinessMetrics(t *testing.T) {
+    reg := prometheus.NewRegistry()
+    m := metrics.New(reg)
+    svc := &mockBookingService{createResp: &domain.Booking{}}
+    h := NewBookingHandler(svc, m)
+
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    app.Post("/bookings", func(c *fiber.Ctx) error { c.Locals("user_id", float64(1)); return h.Create(c) })
+    app.Post("/payments", h.Pay)
+
+    post := func(path string, body any) {
+        b, _ := json.Marshal(body)
+        req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(b))
+        req.Header.Set("Content-Type", "application/json")
+        _, _ = app.Test(req)
+    }
+    booking := map[string]any{"field_id": 1, "start_time": time.Now(), "end_time": time.Now().Add(time.Hour)}
+
+    post("/bookings", booking)
+    svc.createErr = domain.NewConflictError("field is already booked at this time")
+    post("/bookings", booking)
+    svc.createErr = errors.New("dial tcp: connection refused")
+    post("/bookings", booking)
+
+    post("/payments", map[string]any{"booking_id": 1})
+    svc.payErr = domain.NewNotFoundError("booking not found")
+    post("/payments", map[string]any{"booking_id": 2})
+
+    expected := `
+# HELP sagara_booking_conflicts_total Booking attempts rejected because the slot was taken.
+# TYPE sagara_booking_conflicts_total counter
+sagara_booking_conflicts_total 1
+# HELP sagara_bookings_created_total Bookings successfully created.
+# TYPE sagara_bookings_created_total counter
+sagara_bookings_created_total 1
+# HELP sagara_payments_total Payment attempts by result (succeeded, failed).
+# TYPE sagara_payments_total counter
+sagara_payments_total{result="failed"} 1
+sagara_payments_total{result="succeeded"} 1
+`
+    err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+        "sagara_bookings_created_total", "sagara_booking_conflicts_total", "sagara_payments_total")
+    if err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestBookingHandler_GetAvailability(t *testing.T) {
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewBookingHandler(&mockBookingService{}, newTestMetrics())
+    app.Get("/fields/:id/availability", h.GetAvailability)
+
+    resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/fields/3/availability?date=2025-06-02", nil))
+    if resp.StatusCode != http.StatusOK {
+        t.Fatalf("expected 200, got %d", resp.StatusCode)
+    }
+    var body struct{ Data domain.Availability `json:"data"` }
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Data.FieldID != 3 || body.Data.Date != "2025-06-02" {
+        t.Fatalf("unexpected body %+v (%v)", body, err)
+    }
+
+    resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/fields/3/availability?date=bad", nil))
+    if resp.StatusCode != http.StatusBadRequest {
+        t.Fatalf("expected 400 for a bad date, got %d", resp.StatusCode)
+    }
+    resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/fields/x/availability", nil))
+    if resp.StatusCode != http.StatusBadRequest {
+        t.Fatalf("expected 400 for a bad id, got %d", resp.StatusCode)
+    }
+}
+
+func TestBookingHandler_MarkNoShow(t *testing.T) {
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewBookingHandler(&mockBookingService{}, newTestMetrics())
+    app.Post("/admin/bookings/:id/no-show", h.MarkNoShow)
+
+    for target, want := range map[string]int{
+        "/admin/bookings/1/no-show": http.StatusOK,
+        "/admin/bookings/2/no-show": http.StatusConflict,
+        "/admin/bookings/x/no-show": http.StatusBadRequest,
+    } {
+        resp, _ := app.Test(httptest.NewRequest(http.MethodPost, target, nil))
+        if resp.StatusCode != want {
+            t.Fatalf("%s: expected %d, got %d", target, want, resp.StatusCode)
+        }
+    }
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+// calendarFeedPath is where feeds are served, as routed in main.
+const calendarFeedPath = "/api/calendar/"
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	service port.CalendarService
+}
+
+func NewCalendarHandler(service port.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+// CreateMyCalendarFeed godoc
+// @Summary      Get a calendar feed URL for my bookings
+// @Description  Returns a secret iCalendar URL of your bookings to subscribe to in Google Calendar, Outlook or Apple
+// @Description  Calendar. Anyone with the URL can read it, so each call issues a new one and the old URL stops working.
+// @Tags         Calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Success      201 {object} port.DataResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /calendar-feed [post]
+func (h *CalendarHandler) CreateMyFeed(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	return h.createFeed(c, port.CalendarFeedOwner{UserID: uint(userIDFloat)})
+}
+
+// DeleteMyCalendarFeed godoc
+// @Summary      Revoke my calendar feed URL
+// @Tags         Calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} port.MessageResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /calendar-feed [delete]
+func (h *CalendarHandler) DeleteMyFeed(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	return h.deleteFeed(c, port.CalendarFeedOwner{UserID: uint(userIDFloat)})
+}
+
+// CreateFieldCalendarFeed godoc
+// @Summary      Get a calendar feed URL for a field (Admin Only)
+// @Description  Returns a secret iCalendar URL of every booking on the field, named after the customers, for the
+// @Description  front desk. Each call issues a new URL and the old one stops working.
+// @Tags         Calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Field ID"
+// @Success      201 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      404 {object} port.ErrorResponse "Field Not Found"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/fields/{id}/calendar-feed [post]
+func (h *CalendarHandler) CreateFieldFeed(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	return h.createFeed(c, port.CalendarFeedOwner{FieldID: id})
+}
+
+// DeleteFieldCalendarFeed godoc
+// @Summary      Revoke a field's calendar feed URL (Admin Only)
+// @Tags         Calendar
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Field ID"
+// @Success      200 {object} port.MessageResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/fields/{id}/calendar-feed [delete]
+func (h *CalendarHandler) DeleteFieldFeed(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	return h.deleteFeed(c, port.CalendarFeedOwner{FieldID: id})
+}
+
+func (h *CalendarHandler) createFeed(c *fiber.Ctx, owner port.CalendarFeedOwner) error {
+	token, err := h.service.CreateFeed(c.UserContext(), owner)
+	if err != nil {
+		return err
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Calendar feed created; keep the URL secret",
+		"data":    fiber.Map{"url": c.BaseURL() + calendarFeedPath + token + ".ics"},
+	})
+}
+
+func (h *CalendarHandler) deleteFeed(c *fiber.Ctx, owner port.CalendarFeedOwner) error {
+	if err := h.service.DeleteFeed(c.UserContext(), owner); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "Calendar feed revoked"})
+}
+
+// CalendarFeed godoc
+// @Summary      Calendar feed
+// @Description  The iCalendar feed a secret URL opens: a customer's or a field's bookings from 90 days ago on, with
+// @Description  cancelled and expired ones marked cancelled. Needs no login; the token in the URL is the credential.
+// @Tags         Calendar
+// @Produce      text/calendar
+// @Param        token path string true "Feed token"
+// @Success      200 {file} file
+// @Failure      404 {object} port.ErrorResponse "Unknown Or Revoked Feed"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /calendar/{token}.ics [get]
+func (h *CalendarHandler) Feed(c *fiber.Ctx) error {
+	calendar, err := h.service.Feed(c.UserContext(), c.Params("token"))
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, icsContentType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(renderCalendar(calendar))
+}
+
+// BookingCalendar godoc
+// @Summary      Download a booking as an .ics file
+// @Description  One booking as an iCalendar event to add to a calendar. Customers can download their own bookings,
+// @Description  admins any.
+// @Tags         Calendar
+// @Produce      text/calendar
+// @Security     BearerAuth
+// @Param        id path int true "Booking ID"
+// @Success      200 {file} file
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /bookings/{id}/ics [get]
+func (h *CalendarHandler) Booking(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	calendar, err := h.service.BookingCalendar(c.UserContext(), id, uint(userIDFloat), c.Locals("role") == "admin")
+	if err != nil {
+		return err
+	}
+	c.Attachment("booking-" + strconv.FormatUint(uint64(id), 10) + ".ics")
+	c.Set(fiber.HeaderContentType, icsContentType)
+	return c.Send(renderCalendar(calendar))
+}
+
+// icsStatus describes a booking's status to calendars.
+var (
+	icsStatus = map[string]string{
+		domain.BookingStatusPending:   "TENTATIVE",
+		domain.BookingStatusPaid:      "CONFIRMED",
+		domain.BookingStatusNoShow:    "CONFIRMED",
+		domain.BookingStatusCancelled: "CANCELLED",
+		domain.BookingStatusExpired:   "CANCELLED",
+	}
+	// icsSummaryPrefix flags bookings many calendars would otherwise show
+	// as if they were going ahead.
+	icsSummaryPrefix = map[string]string{
+		domain.BookingStatusPending:   "Unpaid: ",
+		domain.BookingStatusCancelled: "Cancelled: ",
+		domain.BookingStatusExpired:   "Expired: ",
+		domain.BookingStatusNoShow:    "No-show: ",
+	}
+)
+
+// icsSequence is b's revision number for calendars, the seconds from its
+// creation to its last update, so it rises with every change and clients
+// replace their copy.
+func icsSequence(b *domain.Booking) int {
+	if b.CreatedAt.IsZero() || !b.UpdatedAt.After(b.CreatedAt) {
+		return 0
+	}
+	return int(b.UpdatedAt.Sub(b.CreatedAt) / time.Second)
+}
+
+// renderCalendar writes calendar as RFC 5545 iCalendar.
+func renderCalendar(calendar *domain.Calendar) []byte {
+	var w icsWriter
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//Sagara//Booking API//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", icsText(calendar.Name))
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	w.line("X-PUBLISHED-TTL", "PT1H")
+	for i := range calendar.Bookings {
+		writeBookingEvent(&w, &calendar.Bookings[i], calendar.ForField)
+	}
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func writeBookingEvent(w *icsWriter, b *domain.Booking, forField bool) {
+	fieldName, location := fmt.Sprintf("Field #%d", b.FieldID), ""
+	if b.Field != nil {
+		fieldName, location = b.Field.Name, b.Field.Location
+	}
+	summary := fieldName
+	if forField {
+		summary = fmt.Sprintf("Customer #%d", b.UserID)
+		if b.User != nil && b.User.Name != "" {
+			summary = b.User.Name
+		}
+	}
+	stamp := b.UpdatedAt
+	if stamp.IsZero() {
+		stamp = b.CreatedAt
+	}
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", fmt.Sprintf("booking-%d@sagara-booking-api", b.ID))
+	w.line("DTSTAMP", icsTime(stamp))
+	w.line("LAST-MODIFIED", icsTime(stamp))
+	w.line("SEQUENCE", strconv.Itoa(icsSequence(b)))
+	if status, ok := icsStatus[b.Status]; ok {
+		w.line("STATUS", status)
+	}
+	w.line("DTSTART", icsTime(b.StartTime))
+	w.line("DTEND", icsTime(b.EndTime))
+	w.line("SUMMARY", icsText(icsSummaryPrefix[b.Status]+summary))
+	if location != "" {
+		w.line("LOCATION", icsText(location))
+	}
+	w.line("DESCRIPTION", icsText(fmt.Sprintf("Booking #%d on %s, %s", b.ID, fieldName, b.Status)))
+	if b.Status == domain.BookingStatusCancelled || b.Status == domain.BookingStatusExpired {
+		w.line("TRANSP", "TRANSPARENT")
+	}
+	w.line("END", "VEVENT")
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// icsText escapes a TEXT value.
+func icsText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// icsWriter writes content lines ending in CRLF, folded so none is longer
+// than 75 octets without splitting a UTF-8 character.
+type icsWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *icsWriter) line(name, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with the space.
+		limit = 74
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
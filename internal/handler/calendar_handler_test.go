@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockCalendarService struct {
+	owner   port.CalendarFeedOwner
+	deleted bool
+	token   string
+	admin   bool
+}
+
+func (m *mockCalendarService) CreateFeed(ctx context.Context, owner port.CalendarFeedOwner) (string, error) {
+	m.owner = owner
+	return "abc123", nil
+}
+
+func (m *mockCalendarService) DeleteFeed(ctx context.Context, owner port.CalendarFeedOwner) error {
+	m.owner, m.deleted = owner, true
+	return nil
+}
+
+func (m *mockCalendarService) Feed(ctx context.Context, token string) (*domain.Calendar, error) {
+	m.token = token
+	if token != "abc123" {
+		return nil, domain.NewNotFoundError("calendar feed not found")
+	}
+	return &domain.Calendar{Name: "Court A", ForField: true, Bookings: []domain.Booking{calendarBooking(domain.BookingStatusCancelled)}}, nil
+}
+
+func (m *mockCalendarService) BookingCalendar(ctx context.Context, bookingID, userID uint, isAdmin bool) (*domain.Calendar, error) {
+	m.admin = isAdmin
+	b := calendarBooking(domain.BookingStatusPaid)
+	b.ID = bookingID
+	return &domain.Calendar{Name: "Booking #5", Bookings: []domain.Booking{b}}, nil
+}
+
+func calendarBooking(status string) domain.Booking {
+	b := domain.Booking{
+		FieldID:   1,
+		Field:     &domain.Field{Name: "Court A", Location: "Senayan, Jakarta"},
+		UserID:    7,
+		User:      &domain.User{Name: "Budi"},
+		StartTime: time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC),
+		Status:    status,
+	}
+	b.ID = 12
+	b.UpdatedAt = time.Date(2025, 6, 1, 8, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	b.CreatedAt = b.UpdatedAt.Add(-2 * time.Hour)
+	return b
+}
+
+func newCalendarApp(svc port.CalendarService, role string) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewCalendarHandler(svc)
+	app.Get("/api/calendar/:token.ics", h.Feed)
+	auth := func(c *fiber.Ctx) error {
+		c.Locals("user_id", float64(7))
+		c.Locals("role", role)
+		return c.Next()
+	}
+	app.Post("/calendar-feed", auth, h.CreateMyFeed)
+	app.Delete("/calendar-feed", auth, h.DeleteMyFeed)
+	app.Post("/fields/:id/calendar-feed", auth, h.CreateFieldFeed)
+	app.Get("/bookings/:id/ics", auth, h.Booking)
+	return app
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(b)
+}
+
+func TestCalendarHandler_Feeds(t *testing.T) {
+	svc := &mockCalendarService{}
+	app := newCalendarApp(svc, "user")
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodPost, "http://example.com/calendar-feed", nil))
+	if resp.StatusCode != http.StatusCreated || svc.owner != (port.CalendarFeedOwner{UserID: 7}) {
+		t.Fatalf("unexpected create: %d %+v", resp.StatusCode, svc.owner)
+	}
+	if url := decodeBody(t, resp)["data"].(map[string]any)["url"]; url != "http://example.com/api/calendar/abc123.ics" {
+		t.Fatalf("unexpected feed url %v", url)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodPost, "/fields/3/calendar-feed", nil))
+	if resp.StatusCode != http.StatusCreated || svc.owner != (port.CalendarFeedOwner{FieldID: 3}) {
+		t.Fatalf("unexpected field feed: %d %+v", resp.StatusCode, svc.owner)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodDelete, "/calendar-feed", nil))
+	if resp.StatusCode != http.StatusOK || !svc.deleted || svc.owner.UserID != 7 {
+		t.Fatalf("unexpected delete: %d %+v", resp.StatusCode, svc)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/api/calendar/abc123.ics", nil))
+	if resp.StatusCode != http.StatusOK || svc.token != "abc123" {
+		t.Fatalf("unexpected feed: %d token %q", resp.StatusCode, svc.token)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != icsContentType {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := readBody(t, resp)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n", "X-WR-CALNAME:Court A\r\n",
+		"UID:booking-12@sagara-booking-api\r\n", "DTSTAMP:20250601T010000Z\r\n",
+		"SEQUENCE:7200\r\n", "STATUS:CANCELLED\r\n", "DTSTART:20250602T110000Z\r\n", "DTEND:20250602T123000Z\r\n",
+		"SUMMARY:Cancelled: Budi\r\n", "LOCATION:Senayan\\, Jakarta\r\n", "END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("feed lacks %q:\n%s", want, body)
+		}
+	}
+
+	if resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/api/calendar/nope.ics", nil)); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown token, got %d", resp.StatusCode)
+	}
+}
+
+func TestCalendarHandler_Booking(t *testing.T) {
+	svc := &mockCalendarService{}
+	resp, _ := newCalendarApp(svc, "admin").Test(httptest.NewRequest(http.MethodGet, "/bookings/5/ics", nil))
+	if resp.StatusCode != http.StatusOK || !svc.admin {
+		t.Fatalf("unexpected download: %d admin %v", resp.StatusCode, svc.admin)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); !strings.Contains(cd, "booking-5.ics") {
+		t.Fatalf("unexpected disposition %q", cd)
+	}
+	body := readBody(t, resp)
+	if !strings.Contains(body, "SUMMARY:Court A\r\n") || !strings.Contains(body, "STATUS:CONFIRMED\r\n") || !strings.Contains(body, "SEQUENCE:7200\r\n") {
+		t.Fatalf("unexpected event:\n%s", body)
+	}
+}
+
+func TestICSSequence_RisesWithEveryUpdate(t *testing.T) {
+	b := calendarBooking(domain.BookingStatusPaid)
+	paid := icsSequence(&b)
+	b.Status, b.UpdatedAt = domain.BookingStatusCancelled, b.UpdatedAt.Add(time.Second)
+	if cancelled := icsSequence(&b); cancelled <= paid {
+		t.Fatalf("expected the sequence to rise past %d, got %d", paid, cancelled)
+	}
+	if seq := icsSequence(&domain.Booking{}); seq != 0 {
+		t.Fatalf("expected 0 for a booking never saved, got %d", seq)
+	}
+}
+
+func TestICSWriter_FoldsAndEscapes(t *testing.T) {
+	if got := icsText("a;b,c\\d\ne"); got != `a\;b\,c\\d\ne` {
+		t.Fatalf("unexpected escape %q", got)
+	}
+
+	var w icsWriter
+	w.line("DESCRIPTION", strings.Repeat("é", 60))
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected a folded line, got %q", lines)
+	}
+	joined := lines[0]
+	for i, l := range lines {
+		if len(l) > 75 {
+			t.Fatalf("line %d is %d octets", i, len(l))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(l, " ") {
+				t.Fatalf("continuation %d must start with a space", i)
+			}
+			joined += l[1:]
+		}
+	}
+	if joined != "DESCRIPTION:"+strings.Repeat("é", 60) {
+		t.Fatalf("unfolding changed the value: %q", joined)
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorHandler is the application-wide Fiber error handler. Handlers return
+// service errors as-is and this maps domain error kinds to HTTP statuses.
+// Anything unrecognised is logged and reported as a generic 500 so database
+// and driver messages never reach the client.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	message := "Internal Server Error"
+
+	var fiberErr *fiber.Error
+	var domainErr *domain.Error
+	var rateErr *domain.RateLimitError
+	switch {
+	case errors.As(err, &fiberErr):
+		status = fiberErr.Code
+		message = fiberErr.Message
+	case errors.As(err, &rateErr):
+		status = fiber.StatusTooManyRequests
+		message = rateErr.Message
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(rateErr.RetryAfter)))
+	case errors.Is(err, context.DeadlineExceeded):
+		status = fiber.StatusServiceUnavailable
+		message = "Request timed out, please try again"
+	case errors.As(err, &domainErr):
+		status = statusForKind(domainErr.Kind)
+		message = domainErr.Message
+	case errors.Is(err, domain.ErrNotFound),
+		errors.Is(err, domain.ErrConflict),
+		errors.Is(err, domain.ErrValidation),
+		errors.Is(err, domain.ErrForbidden),
+		errors.Is(err, domain.ErrUnauthorized),
+		errors.Is(err, domain.ErrRateLimited):
+		status = statusForKind(err)
+		message = err.Error()
+	default:
+		logging.FromContext(c.UserContext()).Error("unhandled error",
+			"method", c.Method(), "path", c.Path(), "error", err)
+	}
+
+	return c.Status(status).JSON(fiber.Map{"error": message})
+}
+
+func statusForKind(kind error) int {
+	switch {
+	case errors.Is(kind, domain.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(kind, domain.ErrConflict):
+		return fiber.StatusConflict
+	case errors.Is(kind, domain.ErrValidation):
+		return fiber.StatusBadRequest
+	case errors.Is(kind, domain.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(kind, domain.ErrUnauthorized):
+		return fiber.StatusUnauthorized
+	case errors.Is(kind, domain.ErrRateLimited):
+		return fiber.StatusTooManyRequests
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// retryAfterSeconds rounds up so clients never retry too early.
+func retryAfterSeconds(d time.Duration) int {
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
+}
+
+// parseID reads a positive numeric route parameter.
+func parseID(c *fiber.Ctx, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Params(name), 10, 64)
+	if err != nil || id == 0 {
+		return 0, domain.NewValidationError("invalid " + name)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestErrorHandler_MapsDomainErrors(t *testing.T) {
+	cases := []struct {
+		err     error
+		status  int
+		message string
+	}{
+		{domain.NewNotFoundError("field not found"), http.StatusNotFound, "field not found"},
+		{domain.NewConflictError("email is already registered"), http.StatusConflict, "email is already registered"},
+		{domain.NewValidationError("invalid id"), http.StatusBadRequest, "invalid id"},
+		{domain.NewForbiddenError("not your booking"), http.StatusForbidden, "not your booking"},
+		{domain.NewUnauthorizedError("invalid email or password"), http.StatusUnauthorized, "invalid email or password"},
+		{fmt.Errorf("pay booking: %w", domain.NewNotFoundError("booking not found")), http.StatusNotFound, "booking not found"},
+		{fiber.NewError(http.StatusTeapot, "teapot"), http.StatusTeapot, "teapot"},
+		{fmt.Errorf("timeout: %w", context.DeadlineExceeded), http.StatusServiceUnavailable, "Request timed out, please try again"},
+		{errors.New(`ERROR: relation "bookings" does not exist (SQLSTATE 42P01)`), http.StatusInternalServerError, "Internal Server Error"},
+	}
+
+	for _, tc := range cases {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+		app.Get("/", func(c *fiber.Ctx) error { return tc.err })
+
+		resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%v: expected %d, got %d", tc.err, tc.status, resp.StatusCode)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		if body["error"] != tc.message {
+			t.Fatalf("%v: expected message %q, got %q", tc.err, tc.message, body["error"])
+		}
+	}
+}
@@ -1,8 +1,6 @@
 package handler
 
 import (
-	"strconv"
-
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
 	"github.com/gofiber/fiber/v2"
 )
@@ -34,8 +32,8 @@ func (h *FieldHandler) Create(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
 	}
 
-	if err := h.service.CreateField(&req); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	if err := h.service.CreateField(c.UserContext(), &req); err != nil {
+		return err
 	}
 	return c.Status(201).JSON(fiber.Map{"message": "Field created successfully"})
 }
@@ -50,9 +48,9 @@ func (h *FieldHandler) Create(c *fiber.Ctx) error {
 // @Failure      500 {object} port.ErrorResponse
 // @Router       /fields [get]
 func (h *FieldHandler) GetAll(c *fiber.Ctx) error {
-	fields, err := h.service.GetAllFields()
+	fields, err := h.service.GetAllFields(c.UserContext())
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(fields)
 }
@@ -65,13 +63,17 @@ func (h *FieldHandler) GetAll(c *fiber.Ctx) error {
 // @Security     BearerAuth
 // @Param        id path int true "Field ID"
 // @Success      200 {object} port.FieldResponse
+// @Failure      400 {object} port.ErrorResponse
 // @Failure      404 {object} port.ErrorResponse
 // @Router       /fields/{id} [get]
 func (h *FieldHandler) GetByID(c *fiber.Ctx) error {
-	id, _ := strconv.Atoi(c.Params("id"))
-	field, err := h.service.GetFieldByID(uint(id))
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	field, err := h.service.GetFieldByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Field not found"})
+		return err
 	}
 	return c.JSON(field)
 }
@@ -88,36 +90,48 @@ func (h *FieldHandler) GetByID(c *fiber.Ctx) error {
 // @Success      200 {object} port.MessageResponse
 // @Failure      400 {object} port.ErrorResponse
 // @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      404 {object} port.ErrorResponse
 // @Failure      500 {object} port.ErrorResponse
 // @Router       /fields/{id} [put]
 func (h *FieldHandler) Update(c *fiber.Ctx) error {
-	id, _ := strconv.Atoi(c.Params("id"))
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
 	var req port.CreateFieldRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
 	}
 
-	if err := h.service.UpdateField(uint(id), &req); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	if err := h.service.UpdateField(c.UserContext(), id, &req); err != nil {
+		return err
 	}
 	return c.JSON(fiber.Map{"message": "Field updated successfully"})
 }
 
 // DeleteField godoc
 // @Summary      Delete Field (Admin Only)
-// @Description  Permanently remove a field. Requires Admin role.
+// @Description  Remove a field. Requires Admin role. With policy=block (default) the request fails while the field
+// @Description  has upcoming bookings; with policy=cascade those bookings are cancelled and their owners notified.
 // @Tags         Fields
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id path int true "Field ID"
+// @Param        policy query string false "Deletion policy" Enums(block, cascade)
 // @Success      200 {object} port.MessageResponse
+// @Failure      400 {object} port.ErrorResponse
 // @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      409 {object} port.ErrorResponse "Field has upcoming bookings"
 // @Failure      500 {object} port.ErrorResponse
 // @Router       /fields/{id} [delete]
 func (h *FieldHandler) Delete(c *fiber.Ctx) error {
-	id, _ := strconv.Atoi(c.Params("id"))
-	if err := h.service.DeleteField(uint(id)); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.DeleteField(c.UserContext(), id, c.Query("policy", port.FieldDeletePolicyBlock)); err != nil {
+		return err
 	}
 	return c.JSON(fiber.Map{"message": "Field deleted successfully"})
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+    "context"
     "bytes"
     "encoding/json"
     "errors"
@@ -21,22 +22,26 @@ type mockFieldService struct {
     byIDErr   error
     updateErr error
     deleteErr error
+    deletePolicy string
 }
 
-func (m *mockFieldService) CreateField(req *port.CreateFieldRequest) error { return m.createErr }
-func (m *mockFieldService) GetAllFields() ([]domain.Field, error) {
+func (m *mockFieldService) CreateField(ctx context.Context, req *port.CreateFieldRequest) error { return m.createErr }
+func (m *mockFieldService) GetAllFields(ctx context.Context) ([]domain.Field, error) {
     if m.allErr != nil { return nil, m.allErr }
     return m.fields, nil
 }
-func (m *mockFieldService) GetFieldByID(id uint) (*domain.Field, error) {
+func (m *mockFieldService) GetFieldByID(ctx context.Context, id uint) (*domain.Field, error) {
     if m.byIDErr != nil { return nil, m.byIDErr }
     return m.byID, nil
 }
-func (m *mockFieldService) UpdateField(id uint, req *port.CreateFieldRequest) error { return m.updateErr }
-func (m *mockFieldService) DeleteField(id uint) error { return m.deleteErr }
+func (m *mockFieldService) UpdateField(ctx context.Context, id uint, req *port.CreateFieldRequest) error { return m.updateErr }
+func (m *mockFieldService) DeleteField(ctx context.Context, id uint, policy string) error {
+    m.deletePolicy = policy
+    return m.deleteErr
+}
 
 func TestFieldHandler_Create_And_GetAll(t *testing.T) {
-    app := fiber.New()
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     h := NewFieldHandler(&mockFieldService{fields: []domain.Field{{Name: "A"}}})
     app.Post("/fields", h.Create)
     app.Get("/fields", h.GetAll)
@@ -67,7 +72,7 @@ func TestFieldHandler_Create_And_GetAll(t *testing.T) {
     }
 
     // get all error
-    app2 := fiber.New()
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     h2 := NewFieldHandler(&mockFieldService{allErr: errors.New("boom")})
     app2.Get("/fields", h2.GetAll)
     req4 := httptest.NewRequest(http.MethodGet, "/fields", nil)
@@ -78,7 +83,7 @@ func TestFieldHandler_Create_And_GetAll(t *testing.T) {
 }
 
 func TestFieldHandler_GetByID_Update_Delete(t *testing.T) {
-    app := fiber.New()
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     h := NewFieldHandler(&mockFieldService{byID: &domain.Field{Name: "A"}})
     app.Get("/fields/:id", h.GetByID)
     app.Put("/fields/:id", h.Update)
@@ -92,8 +97,8 @@ func TestFieldHandler_GetByID_Update_Delete(t *testing.T) {
     }
 
     // get by id not found
-    app2 := fiber.New()
-    h2 := NewFieldHandler(&mockFieldService{byIDErr: errors.New("not found")})
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h2 := NewFieldHandler(&mockFieldService{byIDErr: domain.NewNotFoundError("field not found")})
     app2.Get("/fields/:id", h2.GetByID)
     req2 := httptest.NewRequest(http.MethodGet, "/fields/1", nil)
     resp2, _ := app2.Test(req2)
@@ -126,3 +131,29 @@ func TestFieldHandler_GetByID_Update_Delete(t *testing.T) {
         t.Fatalf("expected 200, got %d", resp5.StatusCode)
     }
 }
+
+func TestFieldHandler_Delete_Policy(t *testing.T) {
+    svc := &mockFieldService{}
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewFieldHandler(svc)
+    app.Delete("/fields/:id", h.Delete)
+
+    // defaults to block
+    resp, _ := app.Test(httptest.NewRequest(http.MethodDelete, "/fields/1", nil))
+    if resp.StatusCode != http.StatusOK || svc.deletePolicy != port.FieldDeletePolicyBlock {
+        t.Fatalf("expected 200 with block policy, got %d / %q", resp.StatusCode, svc.deletePolicy)
+    }
+
+    // cascade passed through
+    resp2, _ := app.Test(httptest.NewRequest(http.MethodDelete, "/fields/1?policy=cascade", nil))
+    if resp2.StatusCode != http.StatusOK || svc.deletePolicy != port.FieldDeletePolicyCascade {
+        t.Fatalf("expected 200 with cascade policy, got %d / %q", resp2.StatusCode, svc.deletePolicy)
+    }
+
+    // blocked by upcoming bookings
+    svc.deleteErr = domain.NewConflictError("field has 2 upcoming booking(s)")
+    resp3, _ := app.Test(httptest.NewRequest(http.MethodDelete, "/fields/1", nil))
+    if resp3.StatusCode != http.StatusConflict {
+        t.Fatalf("expected 409, got %d", resp3.StatusCode)
+    }
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FrontDeskHandler struct {
+	service port.FrontDeskService
+	metrics port.BookingMetrics
+}
+
+func NewFrontDeskHandler(service port.FrontDeskService, metrics port.BookingMetrics) *FrontDeskHandler {
+	return &FrontDeskHandler{service: service, metrics: metrics}
+}
+
+// CreateFrontDeskBooking godoc
+// @Summary      Book for a customer (Admin Only)
+// @Description  Takes a phone or walk-in booking for an existing user (user_id) or a guest (name and phone; guests
+// @Description  with the same phone number are one customer, and get no emails). With payment_method "cash" the
+// @Description  booking is paid at the counter straight away. The start-in-the-future and opening-hours rules can be
+// @Description  waived with overrides "past_start" and "opening_hours" and an override_reason; the admin, overrides
+// @Description  and reason are recorded on the booking. Even so, a started slot can be booked at most 24h after it
+// @Description  started, and a booking must end by midnight of the day it starts. Overlapping bookings and other
+// @Description  customers' holds are never allowed. There is no staff role; front desk staff use admin accounts.
+// @Tags         Bookings
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        booking body port.FrontDeskBookingRequest true "Booking Data"
+// @Param        Idempotency-Key header string false "Unique key; retries with the same key replay the first response for 24h"
+// @Success      201 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse "Invalid Input"
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      404 {object} port.ErrorResponse "Field Or User Not Found"
+// @Failure      409 {object} port.ErrorResponse "Schedule Overlap / Idempotency-Key In Progress"
+// @Failure      422 {object} port.ErrorResponse "Idempotency-Key Reused With Different Body"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/bookings [post]
+func (h *FrontDeskHandler) CreateBooking(c *fiber.Ctx) error {
+	staffIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req port.FrontDeskBookingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input format"})
+	}
+
+	booking, err := h.service.CreateBooking(c.UserContext(), uint(staffIDFloat), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			h.metrics.BookingConflict(c.UserContext())
+		}
+		return err
+	}
+	h.metrics.BookingCreated(c.UserContext())
+	if booking.Status == domain.BookingStatusPaid {
+		h.metrics.PaymentSucceeded(c.UserContext())
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Booking created successfully",
+		"data":    booking,
+	})
+}
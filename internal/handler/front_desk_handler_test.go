@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockFrontDeskService struct {
+	staffID uint
+	req     *port.FrontDeskBookingRequest
+}
+
+func (m *mockFrontDeskService) CreateBooking(ctx context.Context, staffID uint, req *port.FrontDeskBookingRequest) (*domain.Booking, error) {
+	m.staffID, m.req = staffID, req
+	if req.FieldID == 2 {
+		return nil, domain.NewConflictError("field is already booked or held at this time")
+	}
+	booking := &domain.Booking{FieldID: req.FieldID, UserID: 12, Status: domain.BookingStatusPending, CreatedBy: &staffID}
+	if req.PaymentMethod == domain.PaymentMethodCash {
+		booking.Status, booking.PaymentMethod = domain.BookingStatusPaid, domain.PaymentMethodCash
+	}
+	return booking, nil
+}
+
+func TestFrontDeskHandler_CreateBooking(t *testing.T) {
+	svc := &mockFrontDeskService{}
+	m := &paymentCountingMetrics{}
+	h := NewFrontDeskHandler(svc, m)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/admin/bookings", func(c *fiber.Ctx) error {
+		c.Locals("user_id", float64(3))
+		return c.Next()
+	}, h.CreateBooking)
+	post := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/admin/bookings", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := app.Test(req)
+		return resp
+	}
+
+	resp := post(`{"field_id":1,"start_time":"2025-06-02T19:00:00+07:00","end_time":"2025-06-02T20:30:00+07:00",
+		"guest":{"name":"Andi","phone":"0812345678"},"payment_method":"cash",
+		"overrides":["opening_hours"],"override_reason":"Tournament final"}`)
+	if resp.StatusCode != http.StatusCreated || svc.staffID != 3 {
+		t.Fatalf("unexpected response %d for staff %d", resp.StatusCode, svc.staffID)
+	}
+	if svc.req.Guest == nil || svc.req.Guest.Phone != "0812345678" || svc.req.StartTime.IsZero() ||
+		len(svc.req.Overrides) != 1 || svc.req.OverrideReason != "Tournament final" {
+		t.Fatalf("request not passed on: %+v", svc.req)
+	}
+	data := decodeBody(t, resp)["data"].(map[string]any)
+	if data["status"] != "paid" || data["payment_method"] != "cash" || data["created_by"] != float64(3) {
+		t.Fatalf("unexpected booking %v", data)
+	}
+	if m.created != 1 || m.paid != 1 {
+		t.Fatalf("expected a booking and a payment counted, got %+v", m)
+	}
+
+	if resp := post(`{"field_id":2,"user_id":7}`); resp.StatusCode != http.StatusConflict || m.conflicts != 1 {
+		t.Fatalf("expected a counted conflict, got %d %+v", resp.StatusCode, m)
+	}
+	if resp := post(`{`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for malformed json, got %d", resp.StatusCode)
+	}
+}
+
+type paymentCountingMetrics struct {
+	countingBookingMetrics
+	paid int
+}
+
+func (m *paymentCountingMetrics) PaymentSucceeded(ctx context.Context) { m.paid++ }
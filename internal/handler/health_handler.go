@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"github.com/HIUNCY/sagara-booking-api/pkg/buildinfo"
+	"github.com/HIUNCY/sagara-booking-api/pkg/health"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthHandler serves the probes used by load balancers and uptime
+// monitors. They live outside /api and need no authentication.
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// Liveness reports that the process is up and serving requests. It never
+// touches dependencies so a database outage does not get the dyno restarted.
+func (h *HealthHandler) Liveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": health.StatusOK})
+}
+
+// Readiness runs the dependency checks and answers 503 if any fails, so the
+// instance is taken out of rotation until it recovers. The response only
+// says which checks failed; why is logged.
+func (h *HealthHandler) Readiness(c *fiber.Ctx) error {
+	report := h.checker.Run(c.UserContext())
+	status := fiber.StatusOK
+	if report.Status != health.StatusOK {
+		status = fiber.StatusServiceUnavailable
+		for name, result := range report.Checks {
+			if result.Status != health.StatusOK {
+				logging.FromContext(c.UserContext()).Warn("readiness check failed",
+					"check", name, "error", result.Error, "duration_ms", result.DurationMS)
+			}
+		}
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
+
+// Version reports which build is running.
+func (h *HealthHandler) Version(c *fiber.Ctx) error {
+	return c.JSON(buildinfo.Get())
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/health"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHealthHandler_LivenessAndVersion(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewHealthHandler(health.NewChecker(time.Second))
+	app.Get("/healthz", h.Liveness)
+	app.Get("/version", h.Version)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp2, _ := app.Test(httptest.NewRequest(http.MethodGet, "/version", nil))
+	var info map[string]string
+	_ = json.NewDecoder(resp2.Body).Decode(&info)
+	if resp2.StatusCode != http.StatusOK || info["go_version"] == "" || info["commit"] == "" {
+		t.Fatalf("unexpected version response %d %v", resp2.StatusCode, info)
+	}
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	checker := health.NewChecker(50 * time.Millisecond)
+	dbErr := error(nil)
+	checker.Add("database", func(ctx context.Context) error { return dbErr })
+	checker.Add("migrations", func(ctx context.Context) error { return nil })
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewHealthHandler(checker)
+	app.Get("/readyz", h.Readiness)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	// a failing dependency makes the instance unready
+	dbErr = errors.New("connection refused")
+	resp2, _ := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	body := readBody(t, resp2)
+	if strings.Contains(body, "connection refused") {
+		t.Fatalf("the failure's details must only be logged: %s", body)
+	}
+	var report health.Report
+	_ = json.Unmarshal([]byte(body), &report)
+	if resp2.StatusCode != http.StatusServiceUnavailable || report.Checks["database"].Status != health.StatusFail || report.Checks["migrations"].Status != health.StatusOK {
+		t.Fatalf("unexpected readiness response %d %+v", resp2.StatusCode, report)
+	}
+
+	// a hanging check is cut off by the timeout
+	checker.Add("slow", func(ctx context.Context) error { time.Sleep(time.Second); return nil })
+	dbErr = nil
+	start := time.Now()
+	resp3, _ := app.Test(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if resp3.StatusCode != http.StatusServiceUnavailable || time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("expected timed out check to fail fast, got %d after %s", resp3.StatusCode, time.Since(start))
+	}
+}
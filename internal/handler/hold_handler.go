@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HoldHandler struct {
+	service port.HoldService
+	metrics port.BookingMetrics
+}
+
+func NewHoldHandler(service port.HoldService, metrics port.BookingMetrics) *HoldHandler {
+	return &HoldHandler{service: service, metrics: metrics}
+}
+
+// PlaceHold godoc
+// @Summary      Hold a slot during checkout
+// @Description  Reserve a field interval for up to SLOT_HOLD_MAX while paying, so nobody else can book or hold
+// @Description  it. Holding an overlapping slot again replaces your earlier hold; at most 3 holds at a time.
+// @Description  Unused holds are released when they expire.
+// @Tags         Holds
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        hold body port.HoldRequest true "Slot to hold"
+// @Success      201 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse "Invalid Input"
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      404 {object} port.ErrorResponse "Field Not Found"
+// @Failure      409 {object} port.ErrorResponse "Slot Booked Or Held / Too Many Holds"
+// @Failure      429 {object} port.ErrorResponse "Rate Limited"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /holds [post]
+func (h *HoldHandler) Create(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	var req port.HoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
+	}
+
+	hold, err := h.service.PlaceHold(c.UserContext(), uint(userIDFloat), &req)
+	if err != nil {
+		return err
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Slot held successfully",
+		"data":    hold,
+	})
+}
+
+// GetMyHolds godoc
+// @Summary      List my holds
+// @Description  Your unexpired holds, soonest to expire first, including slots offered from the waitlist.
+// @Tags         Holds
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} port.DataResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /holds [get]
+func (h *HoldHandler) GetMine(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	holds, err := h.service.GetMyHolds(c.UserContext(), uint(userIDFloat))
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"message": "Success retrieving holds",
+		"data":    holds,
+	})
+}
+
+// ReleaseHold godoc
+// @Summary      Release a hold
+// @Description  Free the slot straight away. Releasing a slot offered from the waitlist declines the offer.
+// @Tags         Holds
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Hold ID"
+// @Success      200 {object} port.MessageResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /holds/{id} [delete]
+func (h *HoldHandler) Delete(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.ReleaseHold(c.UserContext(), uint(userIDFloat), id); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "Hold released successfully"})
+}
+
+// CheckoutHold godoc
+// @Summary      Book a held slot
+// @Description  Turn an unexpired hold into a pending booking, consuming the hold. Pay for it with /payments.
+// @Tags         Holds
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Hold ID"
+// @Param        Idempotency-Key header string false "Unique key; retries with the same key replay the first response for 24h"
+// @Success      201 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      409 {object} port.ErrorResponse "Hold Expired / Schedule Overlap"
+// @Failure      429 {object} port.ErrorResponse "Rate Limited"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /holds/{id}/checkout [post]
+func (h *HoldHandler) Checkout(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+
+	booking, err := h.service.Checkout(c.UserContext(), uint(userIDFloat), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			h.metrics.BookingConflict(c.UserContext())
+		}
+		return err
+	}
+	h.metrics.BookingCreated(c.UserContext())
+
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Booking created successfully",
+		"data":    booking,
+	})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockHoldService struct {
+	userID   uint
+	released uint
+}
+
+func (m *mockHoldService) PlaceHold(ctx context.Context, userID uint, req *port.HoldRequest) (*domain.SlotHold, error) {
+	m.userID = userID
+	if req.Minutes > 10 {
+		return nil, domain.NewValidationError("minutes must be between 1 and 10")
+	}
+	if req.FieldID == 2 {
+		return nil, domain.NewConflictError("field is already booked or held at this time")
+	}
+	return &domain.SlotHold{ID: 1, UserID: userID, FieldID: req.FieldID}, nil
+}
+
+func (m *mockHoldService) GetMyHolds(ctx context.Context, userID uint) ([]domain.SlotHold, error) {
+	m.userID = userID
+	return []domain.SlotHold{{ID: 1, UserID: userID}}, nil
+}
+
+func (m *mockHoldService) ReleaseHold(ctx context.Context, userID, id uint) error {
+	if id != 1 {
+		return domain.NewNotFoundError("hold not found")
+	}
+	m.released = id
+	return nil
+}
+
+func (m *mockHoldService) Checkout(ctx context.Context, userID, id uint) (*domain.Booking, error) {
+	switch id {
+	case 1:
+		b := &domain.Booking{UserID: userID, Status: domain.BookingStatusPending}
+		b.ID = 5
+		return b, nil
+	case 2:
+		return nil, domain.NewConflictError("the hold has expired; hold or book the slot again")
+	}
+	return nil, domain.NewNotFoundError("hold not found")
+}
+
+type countingBookingMetrics struct {
+	created, conflicts int
+}
+
+func (m *countingBookingMetrics) BookingCreated(ctx context.Context)   { m.created++ }
+func (m *countingBookingMetrics) BookingConflict(ctx context.Context)  { m.conflicts++ }
+func (m *countingBookingMetrics) PaymentSucceeded(ctx context.Context) {}
+func (m *countingBookingMetrics) PaymentFailed(ctx context.Context)    {}
+func (m *countingBookingMetrics) BookingExpired(ctx context.Context)   {}
+
+func TestHoldHandler(t *testing.T) {
+	svc := &mockHoldService{}
+	m := &countingBookingMetrics{}
+	h := NewHoldHandler(svc, m)
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Test-User") != "" {
+			c.Locals("user_id", float64(9))
+		}
+		return c.Next()
+	})
+	app.Get("/holds", h.GetMine)
+	app.Post("/holds", h.Create)
+	app.Delete("/holds/:id", h.Delete)
+	app.Post("/holds/:id/checkout", h.Checkout)
+
+	do := func(method, target, body string) *http.Response {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-User", "1")
+		resp, _ := app.Test(req)
+		return resp
+	}
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodPost, "/holds", nil))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a user, got %d", resp.StatusCode)
+	}
+	for body, want := range map[string]int{
+		`{"field_id":1,"start_time":"2025-06-02T18:00:00+07:00","end_time":"2025-06-02T19:00:00+07:00","minutes":5}`: http.StatusCreated,
+		`{"field_id":1,"minutes":60}`: http.StatusBadRequest,
+		`{"field_id":2}`:              http.StatusConflict,
+		`{"field_id":`:                http.StatusBadRequest,
+	} {
+		if resp := do(http.MethodPost, "/holds", body); resp.StatusCode != want {
+			t.Fatalf("%s: expected %d, got %d", body, want, resp.StatusCode)
+		}
+	}
+	if svc.userID != 9 {
+		t.Fatalf("expected holds placed for user 9, got %d", svc.userID)
+	}
+
+	if resp := do(http.MethodGet, "/holds", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp = do(http.MethodPost, "/holds/1/checkout", "")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if data := decodeBody(t, resp)["data"].(map[string]any); data["ID"] != float64(5) {
+		t.Fatalf("expected booking 5, got %v", data)
+	}
+	if resp := do(http.MethodPost, "/holds/2/checkout", ""); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 for an expired hold, got %d", resp.StatusCode)
+	}
+	if m.created != 1 || m.conflicts != 1 {
+		t.Fatalf("expected one booking and one conflict counted, got %+v", m)
+	}
+
+	if resp := do(http.MethodDelete, "/holds/3", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+	if resp := do(http.MethodDelete, "/holds/1", ""); resp.StatusCode != http.StatusOK || svc.released != 1 {
+		t.Fatalf("expected hold 1 released, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"mime/multipart"
+	"strconv"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	service port.ImportService
+}
+
+func NewImportHandler(service port.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// Import godoc
+// @Summary      Import fields and bookings from CSV (Admin Only)
+// @Description  Upload a fields file, a bookings file or both. Every row is checked first, bookings with the rules of
+// @Description  booking creation except that they may be in the past, and either all rows are imported or none.
+// @Description  With dry_run=true nothing is imported and the row errors are reported. Fields have the columns name,
+// @Description  price_per_hour, location, time_zone, open_time and close_time. Bookings have field_id or field_name,
+// @Description  user_email, start_time and end_time (RFC 3339, or YYYY-MM-DD HH:MM in the field's time zone), status
+// @Description  (paid by default, cancelled or no_show), amount_paid and payment_reference. Imported bookings send no
+// @Description  notifications or webhooks.
+// @Tags         Import
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        fields formData file false "Fields CSV"
+// @Param        bookings formData file false "Bookings CSV"
+// @Param        dry_run query bool false "Only check the files"
+// @Success      200 {object} port.DataResponse "Dry Run"
+// @Success      201 {object} port.DataResponse "Imported"
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      409 {object} port.ErrorResponse "Overlapping Booking Made Meanwhile"
+// @Failure      422 {object} port.DataResponse "Row Errors, Nothing Imported"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/import [post]
+func (h *ImportHandler) Import(c *fiber.Ctx) error {
+	dryRun := false
+	if s := c.Query("dry_run"); s != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			return domain.NewValidationError("dry_run must be true or false")
+		}
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return domain.NewValidationError("send the fields and bookings files as multipart/form-data")
+	}
+
+	fields, err := openUpload(form, "fields")
+	if err != nil {
+		return err
+	}
+	if fields != nil {
+		defer fields.Close()
+	}
+	bookings, err := openUpload(form, "bookings")
+	if err != nil {
+		return err
+	}
+	if bookings != nil {
+		defer bookings.Close()
+	}
+
+	req := &port.ImportRequest{Fields: fields, Bookings: bookings, DryRun: dryRun}
+
+	result, err := h.service.Import(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(result.Errors) > 0 && !dryRun:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": "the files have errors; nothing was imported",
+			"data":  result,
+		})
+	case dryRun:
+		return c.JSON(fiber.Map{
+			"message": "Dry run complete; nothing was imported",
+			"data":    result,
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Import complete",
+		"data":    result,
+	})
+}
+
+// openUpload opens the file uploaded as name, if there is one. Uploads are
+// kept in memory or in temporary files that Fiber removes after the request.
+func openUpload(form *multipart.Form, name string) (multipart.File, error) {
+	files := form.File[name]
+	switch len(files) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, domain.NewValidationError("upload one " + name + " file")
+	}
+	return files[0].Open()
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockImportService struct {
+	fields, bookings string
+	dryRun           bool
+	errors           []domain.ImportError
+}
+
+func (m *mockImportService) Import(ctx context.Context, req *port.ImportRequest) (*domain.ImportResult, error) {
+	m.fields, m.bookings, m.dryRun = "", "", req.DryRun
+	if req.Fields != nil {
+		b, _ := io.ReadAll(req.Fields)
+		m.fields = string(b)
+	}
+	if req.Bookings != nil {
+		b, _ := io.ReadAll(req.Bookings)
+		m.bookings = string(b)
+	}
+	if req.Fields == nil && req.Bookings == nil {
+		return nil, domain.NewValidationError("nothing to import")
+	}
+	return &domain.ImportResult{DryRun: req.DryRun, Imported: len(m.errors) == 0 && !req.DryRun, Errors: m.errors}, nil
+}
+
+func importRequest(t *testing.T, target string, files map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, content := range files {
+		part, err := w.CreateFormFile(name, name+".csv")
+		if err != nil {
+			t.Fatalf("form file: %v", err)
+		}
+		part.Write([]byte(content))
+	}
+	w.Close()
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestImportHandler_Import(t *testing.T) {
+	svc := &mockImportService{}
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/import", NewImportHandler(svc).Import)
+
+	resp, _ := app.Test(importRequest(t, "/import?dry_run=true", map[string]string{"fields": "name\nA\n", "bookings": "user_email\n"}))
+	if resp.StatusCode != http.StatusOK || !svc.dryRun || svc.fields != "name\nA\n" || svc.bookings != "user_email\n" {
+		t.Fatalf("unexpected dry run: %d %+v", resp.StatusCode, svc)
+	}
+
+	resp, _ = app.Test(importRequest(t, "/import", map[string]string{"bookings": "user_email\n"}))
+	if resp.StatusCode != http.StatusCreated || svc.dryRun || svc.fields != "" {
+		t.Fatalf("unexpected import: %d %+v", resp.StatusCode, svc)
+	}
+
+	svc.errors = []domain.ImportError{{File: domain.ImportFileBookings, Row: 2, Message: "overlaps the booking on line 1"}}
+	resp, _ = app.Test(importRequest(t, "/import", map[string]string{"bookings": "user_email\n"}))
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for row errors, got %d", resp.StatusCode)
+	}
+	body := decodeBody(t, resp)
+	if errs := body["data"].(map[string]any)["errors"].([]any); len(errs) != 1 || body["error"] == nil {
+		t.Fatalf("expected the row errors in the body, got %v", body)
+	}
+
+	for name, req := range map[string]*http.Request{
+		"bad dry_run":   importRequest(t, "/import?dry_run=maybe", map[string]string{"fields": "name\n"}),
+		"no files":      importRequest(t, "/import", nil),
+		"not multipart": httptest.NewRequest(http.MethodPost, "/import", bytes.NewReader([]byte("name\n"))),
+	} {
+		if resp, _ := app.Test(req); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", name, resp.StatusCode)
+		}
+	}
+}
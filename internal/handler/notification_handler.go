@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHandler struct {
+	service port.NotificationPreferenceService
+}
+
+func NewNotificationHandler(service port.NotificationPreferenceService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// GetNotificationPreference godoc
+// @Summary      Get my notification preferences
+// @Description  The language of booking emails and whether optional messages such as reminders are sent.
+// @Tags         Notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.NotificationPreference
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /notification-preferences [get]
+func (h *NotificationHandler) Get(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	pref, err := h.service.GetPreference(c.UserContext(), uint(userIDFloat))
+	if err != nil {
+		return err
+	}
+	return c.JSON(pref)
+}
+
+// UpdateNotificationPreference godoc
+// @Summary      Update my notification preferences
+// @Description  Choose the email language (id or en) and opt out of non-transactional messages. Booking
+// @Description  confirmations, payment receipts, cancellations and expiries are always sent.
+// @Tags         Notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        preference body port.NotificationPreferenceRequest true "Preferences"
+// @Success      200 {object} domain.NotificationPreference
+// @Failure      400 {object} port.ErrorResponse "Invalid Input"
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /notification-preferences [put]
+func (h *NotificationHandler) Update(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	var req port.NotificationPreferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
+	}
+
+	pref, err := h.service.UpdatePreference(c.UserContext(), uint(userIDFloat), &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(pref)
+}
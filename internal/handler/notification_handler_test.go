@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockPreferenceService struct {
+	userID uint
+	req    *port.NotificationPreferenceRequest
+}
+
+func (m *mockPreferenceService) GetPreference(ctx context.Context, userID uint) (*domain.NotificationPreference, error) {
+	m.userID = userID
+	return &domain.NotificationPreference{UserID: userID, Language: "id"}, nil
+}
+
+func (m *mockPreferenceService) UpdatePreference(ctx context.Context, userID uint, req *port.NotificationPreferenceRequest) (*domain.NotificationPreference, error) {
+	m.userID, m.req = userID, req
+	if req.Language == "fr" {
+		return nil, domain.NewValidationError("language must be 'id' or 'en'")
+	}
+	return &domain.NotificationPreference{UserID: userID, Language: req.Language, OptOutNonTransactional: req.OptOutNonTransactional}, nil
+}
+
+func newNotificationApp(svc port.NotificationPreferenceService) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewNotificationHandler(svc)
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Test-User") != "" {
+			c.Locals("user_id", float64(9))
+		}
+		return c.Next()
+	})
+	app.Get("/notification-preferences", h.Get)
+	app.Put("/notification-preferences", h.Update)
+	return app
+}
+
+func TestNotificationHandler_Get(t *testing.T) {
+	svc := &mockPreferenceService{}
+	app := newNotificationApp(svc)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/notification-preferences", nil))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/notification-preferences", nil)
+	req.Header.Set("X-Test-User", "1")
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusOK || svc.userID != 9 {
+		t.Fatalf("expected 200 for user 9, got %d / %d", resp.StatusCode, svc.userID)
+	}
+	if body := decodeBody(t, resp); body["language"] != "id" {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
+
+func TestNotificationHandler_Update(t *testing.T) {
+	svc := &mockPreferenceService{}
+	app := newNotificationApp(svc)
+
+	put := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPut, "/notification-preferences", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-User", "1")
+		resp, _ := app.Test(req)
+		return resp
+	}
+
+	if resp := put("{"); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	if resp := put(`{"language":"fr"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	resp := put(`{"language":"en","opt_out_non_transactional":true}`)
+	if resp.StatusCode != http.StatusOK || !svc.req.OptOutNonTransactional {
+		t.Fatalf("expected 200 with opt-out, got %d / %+v", resp.StatusCode, svc.req)
+	}
+	if body := decodeBody(t, resp); body["opt_out_non_transactional"] != true {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
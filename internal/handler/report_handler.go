@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportHandler struct {
+	service port.ReportService
+}
+
+func NewReportHandler(service port.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// RevenueReport godoc
+// @Summary      Revenue report (Admin Only)
+// @Description  Confirmed (paid and no-show) bookings and their revenue per local day, week or month, by field,
+// @Description  venue (field location) or overall. Revenue uses each field's current hourly price. Bookings count
+// @Description  on the day they start in their field's time zone.
+// @Tags         Reports
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param        to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param        field_id query int false "Only this field"
+// @Param        period query string false "day (default), week or month"
+// @Param        group query string false "field (default), venue or all"
+// @Param        format query string false "json (default) or csv"
+// @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/reports/revenue [get]
+func (h *ReportHandler) Revenue(c *fiber.Ctx) error {
+	q, err := reportQuery(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.service.Revenue(c.UserContext(), q, c.Query("period"), c.Query("group"))
+	if err != nil {
+		return err
+	}
+	return sendReport(c, "revenue", rows,
+		[]string{"period_start", "field_id", "field_name", "venue", "bookings", "hours", "revenue"},
+		func(yield func(...string)) {
+			for _, r := range rows {
+				yield(r.PeriodStart, formatID(r.FieldID), r.FieldName, r.Venue,
+					formatInt(r.Bookings), formatFloat(r.Hours), formatInt(r.Revenue))
+			}
+		})
+}
+
+// UtilizationReport godoc
+// @Summary      Utilization report (Admin Only)
+// @Description  Each current field's confirmed hours as a percentage of its opening hours over the range.
+// @Tags         Reports
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param        to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param        field_id query int false "Only this field"
+// @Param        format query string false "json (default) or csv"
+// @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      404 {object} port.ErrorResponse "Field Not Found"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/reports/utilization [get]
+func (h *ReportHandler) Utilization(c *fiber.Ctx) error {
+	q, err := reportQuery(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.service.Utilization(c.UserContext(), q)
+	if err != nil {
+		return err
+	}
+	return sendReport(c, "utilization", rows,
+		[]string{"field_id", "field_name", "booked_hours", "open_hours", "utilization_pct"},
+		func(yield func(...string)) {
+			for _, r := range rows {
+				yield(formatID(r.FieldID), r.FieldName,
+					formatFloat(r.BookedHours), formatFloat(r.OpenHours), formatFloat(r.UtilizationPct))
+			}
+		})
+}
+
+// HeatmapReport godoc
+// @Summary      Peak-hour heatmap (Admin Only)
+// @Description  Confirmed bookings by the weekday and local hour they start, all 7x24 cells from Monday 00:00.
+// @Tags         Reports
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param        to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param        field_id query int false "Only this field"
+// @Param        format query string false "json (default) or csv"
+// @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/reports/heatmap [get]
+func (h *ReportHandler) Heatmap(c *fiber.Ctx) error {
+	q, err := reportQuery(c)
+	if err != nil {
+		return err
+	}
+	cells, err := h.service.Heatmap(c.UserContext(), q)
+	if err != nil {
+		return err
+	}
+	return sendReport(c, "heatmap", cells,
+		[]string{"weekday", "day", "hour", "bookings"},
+		func(yield func(...string)) {
+			for _, cell := range cells {
+				yield(strconv.Itoa(cell.Weekday), cell.Day, strconv.Itoa(cell.Hour), formatInt(cell.Bookings))
+			}
+		})
+}
+
+// CancellationReport godoc
+// @Summary      Cancellation and no-show report (Admin Only)
+// @Description  Each field's bookings by outcome, with a final total row (field_id 0). The cancellation rate is
+// @Description  over all bookings, the no-show rate over confirmed ones.
+// @Tags         Reports
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param        to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param        field_id query int false "Only this field"
+// @Param        format query string false "json (default) or csv"
+// @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/reports/cancellations [get]
+func (h *ReportHandler) Cancellations(c *fiber.Ctx) error {
+	q, err := reportQuery(c)
+	if err != nil {
+		return err
+	}
+	rows, err := h.service.Cancellations(c.UserContext(), q)
+	if err != nil {
+		return err
+	}
+	return sendReport(c, "cancellations", rows,
+		[]string{"field_id", "field_name", "bookings", "paid", "cancelled", "expired", "no_shows", "cancellation_rate_pct", "no_show_rate_pct"},
+		func(yield func(...string)) {
+			for _, r := range rows {
+				yield(strconv.FormatUint(uint64(r.FieldID), 10), r.FieldName, formatInt(r.Bookings), formatInt(r.Paid),
+					formatInt(r.Cancelled), formatInt(r.Expired), formatInt(r.NoShows),
+					formatFloat(r.CancellationRate), formatFloat(r.NoShowRate))
+			}
+		})
+}
+
+// TopCustomersReport godoc
+// @Summary      Top customers (Admin Only)
+// @Description  The customers with the most confirmed revenue over the range.
+// @Tags         Reports
+// @Produce      json
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Param        from query string false "First day, YYYY-MM-DD (default 29 days before to)"
+// @Param        to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param        field_id query int false "Only this field"
+// @Param        limit query int false "How many, 1-100 (default 10)"
+// @Param        format query string false "json (default) or csv"
+// @Success      200 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      403 {object} port.ErrorResponse "Forbidden"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/reports/top-customers [get]
+func (h *ReportHandler) TopCustomers(c *fiber.Ctx) error {
+	q, err := reportQuery(c)
+	if err != nil {
+		return err
+	}
+	limit := 0
+	if s := c.Query("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil {
+			return domain.NewValidationError("limit must be a number")
+		}
+	}
+	rows, err := h.service.TopCustomers(c.UserContext(), q, limit)
+	if err != nil {
+		return err
+	}
+	return sendReport(c, "top-customers", rows,
+		[]string{"user_id", "name", "email", "bookings", "hours", "revenue"},
+		func(yield func(...string)) {
+			for _, r := range rows {
+				yield(formatID(r.UserID), r.Name, r.Email, formatInt(r.Bookings), formatFloat(r.Hours), formatInt(r.Revenue))
+			}
+		})
+}
+
+func reportQuery(c *fiber.Ctx) (*port.ReportQuery, error) {
+	var q port.ReportQuery
+	if err := c.QueryParser(&q); err != nil {
+		return nil, domain.NewValidationError("field_id must be a number")
+	}
+	return &q, nil
+}
+
+// sendReport answers with data as JSON, or with the rows each yields as a
+// CSV attachment when the admin asks for format=csv.
+func sendReport(c *fiber.Ctx, name string, data interface{}, header []string, each func(yield func(...string))) error {
+	switch c.Query("format") {
+	case "", "json":
+		return c.JSON(fiber.Map{
+			"message": "Success retrieving report",
+			"data":    data,
+		})
+	case "csv":
+	default:
+		return domain.NewValidationError("format must be json or csv")
+	}
+
+	c.Attachment(name + ".csv")
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	w := csv.NewWriter(c)
+	w.Write(header)
+	each(func(record ...string) {
+		for i, value := range record {
+			record[i] = csvSafe(value)
+		}
+		w.Write(record)
+	})
+	w.Flush()
+	return w.Error()
+}
+
+// csvSafe stops spreadsheets from running a customer-supplied value, such
+// as a name starting with "=", as a formula.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// formatID leaves an unset ID blank, as in revenue rows not grouped by
+// field.
+func formatID(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
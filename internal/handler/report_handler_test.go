@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockReportService struct {
+	query port.ReportQuery
+	limit int
+}
+
+func (m *mockReportService) Revenue(ctx context.Context, q *port.ReportQuery, period, group string) ([]domain.RevenueRow, error) {
+	m.query = *q
+	if period == "year" {
+		return nil, domain.NewValidationError("period must be day, week or month")
+	}
+	return []domain.RevenueRow{{PeriodStart: "2025-06-02", FieldID: 1, FieldName: "=HYPERLINK(\"x\")", Bookings: 2, Hours: 1.5, Revenue: 150000}}, nil
+}
+
+func (m *mockReportService) Utilization(ctx context.Context, q *port.ReportQuery) ([]domain.UtilizationRow, error) {
+	return []domain.UtilizationRow{{FieldID: 1, FieldName: "A", BookedHours: 7, OpenHours: 14, UtilizationPct: 50}}, nil
+}
+
+func (m *mockReportService) Heatmap(ctx context.Context, q *port.ReportQuery) ([]domain.HeatmapCell, error) {
+	return []domain.HeatmapCell{{Weekday: 1, Day: "Monday", Hour: 18, Bookings: 3}}, nil
+}
+
+func (m *mockReportService) Cancellations(ctx context.Context, q *port.ReportQuery) ([]domain.CancellationRow, error) {
+	return []domain.CancellationRow{{FieldName: "All fields", Bookings: 4, Cancelled: 1, CancellationRate: 25}}, nil
+}
+
+func (m *mockReportService) TopCustomers(ctx context.Context, q *port.ReportQuery, limit int) ([]domain.CustomerRow, error) {
+	m.limit = limit
+	return []domain.CustomerRow{{UserID: 9, Name: "Budi", Email: "budi@example.com", Bookings: 2, Revenue: 300000}}, nil
+}
+
+func newReportApp(svc port.ReportService) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewReportHandler(svc)
+	app.Get("/reports/revenue", h.Revenue)
+	app.Get("/reports/utilization", h.Utilization)
+	app.Get("/reports/heatmap", h.Heatmap)
+	app.Get("/reports/cancellations", h.Cancellations)
+	app.Get("/reports/top-customers", h.TopCustomers)
+	return app
+}
+
+func TestReportHandler_JSON(t *testing.T) {
+	svc := &mockReportService{}
+	app := newReportApp(svc)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/reports/revenue?from=2025-06-01&to=2025-06-30&field_id=1", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if svc.query != (port.ReportQuery{From: "2025-06-01", To: "2025-06-30", FieldID: 1}) {
+		t.Fatalf("unexpected query %+v", svc.query)
+	}
+	if rows := decodeBody(t, resp)["data"].([]any); rows[0].(map[string]any)["revenue"] != float64(150000) {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+
+	for _, target := range []string{"/reports/utilization", "/reports/heatmap", "/reports/cancellations", "/reports/top-customers?limit=5"} {
+		if resp, _ := app.Test(httptest.NewRequest(http.MethodGet, target, nil)); resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", target, resp.StatusCode)
+		}
+	}
+	if svc.limit != 5 {
+		t.Fatalf("expected limit 5, got %d", svc.limit)
+	}
+
+	for _, target := range []string{
+		"/reports/revenue?field_id=x",
+		"/reports/revenue?period=year",
+		"/reports/revenue?format=pdf",
+		"/reports/top-customers?limit=ten",
+	} {
+		if resp, _ := app.Test(httptest.NewRequest(http.MethodGet, target, nil)); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestReportHandler_CSV(t *testing.T) {
+	app := newReportApp(&mockReportService{})
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/reports/revenue?format=csv", nil))
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Fatalf("expected a CSV, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename="revenue.csv"` {
+		t.Fatalf("unexpected Content-Disposition %q", got)
+	}
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("expected a header and one row, got %v (%v)", records, err)
+	}
+	if records[0][0] != "period_start" || records[1][1] != "1" || records[1][3] != "" || records[1][5] != "1.5" {
+		t.Fatalf("unexpected records %v", records)
+	}
+	if records[1][2] != `'=HYPERLINK("x")` {
+		t.Fatalf("expected the formula to be neutralised, got %q", records[1][2])
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/reports/cancellations?format=csv", nil))
+	records, _ = csv.NewReader(resp.Body).ReadAll()
+	if len(records) != 2 || records[1][0] != "0" || records[1][7] != "25" {
+		t.Fatalf("unexpected records %v", records)
+	}
+}
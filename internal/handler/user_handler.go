@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -22,6 +23,7 @@ func NewUserHandler(service port.UserService) *UserHandler {
 // @Param        user body port.RegisterRequest true "User Data"
 // @Success      201 {object} port.MessageResponse "message: User created successfully"
 // @Failure      400 {object} port.ErrorResponse "Invalid Input"
+// @Failure      409 {object} port.ErrorResponse "Email Already Registered"
 // @Failure      500 {object} port.ErrorResponse "Internal Server Error"
 // @Router       /register [post]
 func (h *UserHandler) Register(c *fiber.Ctx) error {
@@ -30,8 +32,8 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
 	}
 
-	if err := h.service.Register(&req); err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	if err := h.service.Register(c.UserContext(), &req); err != nil {
+		return err
 	}
 
 	return c.Status(201).JSON(fiber.Map{"message": "User created successfully"})
@@ -47,16 +49,18 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 // @Success      200 {object} port.LoginResponse
 // @Failure      400 {object} port.ErrorResponse "Invalid Input"
 // @Failure      401 {object} port.ErrorResponse "Invalid Email or Password"
+// @Failure      429 {object} port.ErrorResponse "Too Many Failed Attempts"
 // @Router       /login [post]
 func (h *UserHandler) Login(c *fiber.Ctx) error {
 	var req port.LoginRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
 	}
+	req.ClientIP = middleware.ClientIP(c)
 
-	res, err := h.service.Login(&req)
+	res, err := h.service.Login(c.UserContext(), &req)
 	if err != nil {
-		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(res)
@@ -1,13 +1,16 @@
 package handler
 
 import (
+    "context"
     "bytes"
     "encoding/json"
     "errors"
     "net/http"
     "net/http/httptest"
     "testing"
+    "time"
 
+    "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
     "github.com/HIUNCY/sagara-booking-api/internal/core/port"
     "github.com/gofiber/fiber/v2"
 )
@@ -18,14 +21,14 @@ type mockUserService struct {
     loginErr    error
 }
 
-func (m *mockUserService) Register(req *port.RegisterRequest) error { return m.registerErr }
-func (m *mockUserService) Login(req *port.LoginRequest) (*port.LoginResponse, error) {
+func (m *mockUserService) Register(ctx context.Context, req *port.RegisterRequest) error { return m.registerErr }
+func (m *mockUserService) Login(ctx context.Context, req *port.LoginRequest) (*port.LoginResponse, error) {
     if m.loginErr != nil { return nil, m.loginErr }
     return m.loginResp, nil
 }
 
 func TestUserHandler_Register(t *testing.T) {
-    app := fiber.New()
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     h := NewUserHandler(&mockUserService{})
     app.Post("/register", h.Register)
 
@@ -47,7 +50,7 @@ func TestUserHandler_Register(t *testing.T) {
     }
 
     // service error
-    app2 := fiber.New()
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     h2 := NewUserHandler(&mockUserService{registerErr: errors.New("boom")})
     app2.Post("/register", h2.Register)
     req3 := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(b))
@@ -56,10 +59,21 @@ func TestUserHandler_Register(t *testing.T) {
     if resp3.StatusCode != http.StatusInternalServerError {
         t.Fatalf("expected 500, got %d", resp3.StatusCode)
     }
+
+    // duplicate email
+    app3 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h3 := NewUserHandler(&mockUserService{registerErr: domain.NewConflictError("email is already registered")})
+    app3.Post("/register", h3.Register)
+    req4 := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(b))
+    req4.Header.Set("Content-Type", "application/json")
+    resp4, _ := app3.Test(req4)
+    if resp4.StatusCode != http.StatusConflict {
+        t.Fatalf("expected 409, got %d", resp4.StatusCode)
+    }
 }
 
 func TestUserHandler_Login(t *testing.T) {
-    app := fiber.New()
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
     h := NewUserHandler(&mockUserService{loginResp: &port.LoginResponse{Token: "tok"}})
     app.Post("/login", h.Login)
 
@@ -81,8 +95,8 @@ func TestUserHandler_Login(t *testing.T) {
     }
 
     // invalid credentials
-    app2 := fiber.New()
-    h2 := NewUserHandler(&mockUserService{loginErr: errors.New("bad")})
+    app2 := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h2 := NewUserHandler(&mockUserService{loginErr: domain.NewUnauthorizedError("invalid email or password")})
     app2.Post("/login", h2.Login)
     req3 := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(b))
     req3.Header.Set("Content-Type", "application/json")
@@ -91,3 +105,20 @@ func TestUserHandler_Login(t *testing.T) {
         t.Fatalf("expected 401, got %d", resp3.StatusCode)
     }
 }
+
+func TestUserHandler_Login_RateLimited(t *testing.T) {
+    app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+    h := NewUserHandler(&mockUserService{loginErr: domain.NewRateLimitError("too many failed login attempts", 1500*time.Millisecond)})
+    app.Post("/login", h.Login)
+
+    b, _ := json.Marshal(map[string]any{"email": "a@mail", "password": "x"})
+    req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(b))
+    req.Header.Set("Content-Type", "application/json")
+    resp, _ := app.Test(req)
+    if resp.StatusCode != http.StatusTooManyRequests {
+        t.Fatalf("expected 429, got %d", resp.StatusCode)
+    }
+    if got := resp.Header.Get("Retry-After"); got != "2" {
+        t.Fatalf("expected Retry-After 2, got %q", got)
+    }
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WaitlistHandler struct {
+	service port.WaitlistService
+}
+
+func NewWaitlistHandler(service port.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{service: service}
+}
+
+// JoinWaitlist godoc
+// @Summary      Join the waitlist for a booked slot
+// @Description  Wait for a field interval that is currently taken. When it frees up it is held for the first
+// @Description  customer in line for WAITLIST_HOLD, who is emailed and confirms by booking the slot before the
+// @Description  hold runs out; otherwise it goes to the next in line.
+// @Tags         Waitlist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        entry body port.WaitlistRequest true "Slot to wait for"
+// @Success      201 {object} port.DataResponse
+// @Failure      400 {object} port.ErrorResponse "Invalid Input / Slot Is Free"
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      404 {object} port.ErrorResponse "Field Not Found"
+// @Failure      409 {object} port.ErrorResponse "Already Waiting For This Slot"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /waitlist [post]
+func (h *WaitlistHandler) Join(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	var req port.WaitlistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
+	}
+
+	entry, err := h.service.JoinWaitlist(c.UserContext(), uint(userIDFloat), &req)
+	if err != nil {
+		return err
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Added to the waitlist",
+		"data":    entry,
+	})
+}
+
+// GetMyWaitlist godoc
+// @Summary      List my waitlist entries
+// @Description  Newest first, including finished ones. Offered entries carry the time their hold runs out.
+// @Tags         Waitlist
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} port.DataResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /waitlist [get]
+func (h *WaitlistHandler) GetMine(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	entries, err := h.service.GetMyWaitlist(c.UserContext(), uint(userIDFloat))
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"message": "Success retrieving waitlist",
+		"data":    entries,
+	})
+}
+
+// LeaveWaitlist godoc
+// @Summary      Leave the waitlist
+// @Description  Withdraw an entry. Leaving while the slot is offered declines it, passing it to the next in line.
+// @Tags         Waitlist
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Waitlist entry ID"
+// @Success      200 {object} port.MessageResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      401 {object} port.ErrorResponse "Unauthorized"
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /waitlist/{id} [delete]
+func (h *WaitlistHandler) Leave(c *fiber.Ctx) error {
+	userIDFloat, ok := c.Locals("user_id").(float64)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.LeaveWaitlist(c.UserContext(), uint(userIDFloat), id); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "Removed from the waitlist"})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockWaitlistService struct {
+	userID uint
+	left   uint
+}
+
+func (m *mockWaitlistService) JoinWaitlist(ctx context.Context, userID uint, req *port.WaitlistRequest) (*domain.WaitlistEntry, error) {
+	m.userID = userID
+	if req.FieldID == 0 {
+		return nil, domain.NewValidationError("field_id is required")
+	}
+	return &domain.WaitlistEntry{ID: 1, UserID: userID, FieldID: req.FieldID, Status: domain.WaitlistStatusWaiting}, nil
+}
+
+func (m *mockWaitlistService) GetMyWaitlist(ctx context.Context, userID uint) ([]domain.WaitlistEntry, error) {
+	m.userID = userID
+	return []domain.WaitlistEntry{{ID: 1, UserID: userID}}, nil
+}
+
+func (m *mockWaitlistService) LeaveWaitlist(ctx context.Context, userID, id uint) error {
+	m.userID = userID
+	if id != 1 {
+		return domain.NewNotFoundError("waitlist entry not found")
+	}
+	m.left = id
+	return nil
+}
+
+func (m *mockWaitlistService) SlotFreed(ctx context.Context, fieldID uint, start, end time.Time) error {
+	return nil
+}
+
+func newWaitlistApp(svc port.WaitlistService) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewWaitlistHandler(svc)
+	app.Use(func(c *fiber.Ctx) error {
+		if c.Get("X-Test-User") != "" {
+			c.Locals("user_id", float64(9))
+		}
+		return c.Next()
+	})
+	app.Get("/waitlist", h.GetMine)
+	app.Post("/waitlist", h.Join)
+	app.Delete("/waitlist/:id", h.Leave)
+	return app
+}
+
+func TestWaitlistHandler(t *testing.T) {
+	svc := &mockWaitlistService{}
+	app := newWaitlistApp(svc)
+
+	do := func(method, target, body string) *http.Response {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-User", "1")
+		resp, _ := app.Test(req)
+		return resp
+	}
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/waitlist", nil))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a user, got %d", resp.StatusCode)
+	}
+
+	resp = do(http.MethodPost, "/waitlist", `{"field_id":3,"start_time":"2025-06-02T18:00:00+07:00","end_time":"2025-06-02T19:00:00+07:00"}`)
+	if resp.StatusCode != http.StatusCreated || svc.userID != 9 {
+		t.Fatalf("expected 201 for user 9, got %d / %d", resp.StatusCode, svc.userID)
+	}
+	if body := decodeBody(t, resp); body["data"].(map[string]any)["status"] != domain.WaitlistStatusWaiting {
+		t.Fatalf("unexpected body %v", body)
+	}
+	if resp = do(http.MethodPost, "/waitlist", `{"field_id":`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a broken body, got %d", resp.StatusCode)
+	}
+	if resp = do(http.MethodPost, "/waitlist", `{}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a validation error, got %d", resp.StatusCode)
+	}
+
+	if resp = do(http.MethodGet, "/waitlist", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if resp = do(http.MethodDelete, "/waitlist/abc", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad id, got %d", resp.StatusCode)
+	}
+	if resp = do(http.MethodDelete, "/waitlist/2", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+	if resp = do(http.MethodDelete, "/waitlist/1", ""); resp.StatusCode != http.StatusOK || svc.left != 1 {
+		t.Fatalf("expected 200 leaving entry 1, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	service port.WebhookService
+}
+
+func NewWebhookHandler(service port.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateWebhook godoc
+// @Summary      Create Webhook Subscription (Admin Only)
+// @Description  Subscribe a partner URL to booking events. Deliveries are signed with the secret, which is
+// @Description  generated when omitted and only returned in this response.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        webhook body port.WebhookRequest true "Subscription"
+// @Success      201 {object} port.DataResponse{data=port.WebhookSecretResponse}
+// @Failure      400 {object} port.ErrorResponse "Invalid Input"
+// @Failure      403 {object} port.ErrorResponse "Forbidden: Admin only"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/webhooks [post]
+func (h *WebhookHandler) Create(c *fiber.Ctx) error {
+	var req port.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
+	}
+
+	sub, err := h.service.CreateWebhook(c.UserContext(), &req)
+	if err != nil {
+		return err
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Webhook created successfully",
+		"data":    sub,
+	})
+}
+
+// GetAllWebhooks godoc
+// @Summary      List Webhook Subscriptions (Admin Only)
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.WebhookSubscription
+// @Failure      403 {object} port.ErrorResponse "Forbidden: Admin only"
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/webhooks [get]
+func (h *WebhookHandler) GetAll(c *fiber.Ctx) error {
+	subs, err := h.service.GetAllWebhooks(c.UserContext())
+	if err != nil {
+		return err
+	}
+	return c.JSON(subs)
+}
+
+// GetWebhookByID godoc
+// @Summary      Get Webhook Subscription (Admin Only)
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Webhook ID"
+// @Success      200 {object} domain.WebhookSubscription
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	sub, err := h.service.GetWebhookByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(sub)
+}
+
+// UpdateWebhook godoc
+// @Summary      Update Webhook Subscription (Admin Only)
+// @Description  Replace the URL, event types and description. A non-empty secret rotates it; active=true
+// @Description  re-enables a subscription that was disabled after repeated failures.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Webhook ID"
+// @Param        webhook body port.WebhookRequest true "Subscription"
+// @Success      200 {object} port.DataResponse{data=port.WebhookSecretResponse}
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      403 {object} port.ErrorResponse "Forbidden: Admin only"
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      500 {object} port.ErrorResponse
+// @Router       /admin/webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	var req port.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Input"})
+	}
+
+	sub, err := h.service.UpdateWebhook(c.UserContext(), id, &req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"message": "Webhook updated successfully",
+		"data":    sub,
+	})
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete Webhook Subscription (Admin Only)
+// @Description  Remove a subscription together with its delivery log.
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Webhook ID"
+// @Success      200 {object} port.MessageResponse
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      403 {object} port.ErrorResponse "Forbidden: Admin only"
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.DeleteWebhook(c.UserContext(), id); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "Webhook deleted successfully"})
+}
+
+// ListWebhookDeliveries godoc
+// @Summary      List Webhook Deliveries (Admin Only)
+// @Description  The subscription's most recent deliveries, newest first.
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Webhook ID"
+// @Param        limit query int false "Maximum deliveries (default 50, max 200)"
+// @Success      200 {array} domain.WebhookDelivery
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	deliveries, err := h.service.ListDeliveries(c.UserContext(), id, c.QueryInt("limit"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(deliveries)
+}
+
+// GetWebhookDelivery godoc
+// @Summary      Get Webhook Delivery (Admin Only)
+// @Description  A delivery with the log of every attempt: response status, truncated body, error and duration.
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Webhook ID"
+// @Param        deliveryId path int true "Delivery ID"
+// @Success      200 {object} domain.WebhookDelivery
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      404 {object} port.ErrorResponse
+// @Router       /admin/webhooks/{id}/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	deliveryID, err := parseID(c, "deliveryId")
+	if err != nil {
+		return err
+	}
+	delivery, err := h.service.GetDelivery(c.UserContext(), id, deliveryID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(delivery)
+}
+
+// RedeliverWebhook godoc
+// @Summary      Redeliver Webhook (Admin Only)
+// @Description  Queue a delivery to be sent again with a fresh set of retries, whatever its current state.
+// @Tags         Webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "Webhook ID"
+// @Param        deliveryId path int true "Delivery ID"
+// @Success      202 {object} port.DataResponse{data=domain.WebhookDelivery}
+// @Failure      400 {object} port.ErrorResponse
+// @Failure      404 {object} port.ErrorResponse
+// @Failure      409 {object} port.ErrorResponse "Webhook is disabled"
+// @Router       /admin/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	id, err := parseID(c, "id")
+	if err != nil {
+		return err
+	}
+	deliveryID, err := parseID(c, "deliveryId")
+	if err != nil {
+		return err
+	}
+	delivery, err := h.service.Redeliver(c.UserContext(), id, deliveryID)
+	if err != nil {
+		return err
+	}
+	return c.Status(202).JSON(fiber.Map{
+		"message": "Delivery queued",
+		"data":    delivery,
+	})
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/gofiber/fiber/v2"
+)
+
+type mockWebhookService struct {
+	createReq    *port.WebhookRequest
+	createErr    error
+	byIDErr      error
+	limit        int
+	redeliverErr error
+}
+
+func (m *mockWebhookService) CreateWebhook(ctx context.Context, req *port.WebhookRequest) (*port.WebhookSecretResponse, error) {
+	m.createReq = req
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	return &port.WebhookSecretResponse{
+		WebhookSubscription: domain.WebhookSubscription{ID: 1, URL: req.URL, EventTypes: req.EventTypes, Secret: "whsec_x", Active: true},
+		Secret:              "whsec_x",
+	}, nil
+}
+
+func (m *mockWebhookService) GetAllWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return []domain.WebhookSubscription{{ID: 1, Secret: "whsec_x"}}, nil
+}
+
+func (m *mockWebhookService) GetWebhookByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error) {
+	if m.byIDErr != nil {
+		return nil, m.byIDErr
+	}
+	return &domain.WebhookSubscription{ID: id, Secret: "whsec_x"}, nil
+}
+
+func (m *mockWebhookService) UpdateWebhook(ctx context.Context, id uint, req *port.WebhookRequest) (*port.WebhookSecretResponse, error) {
+	return &port.WebhookSecretResponse{WebhookSubscription: domain.WebhookSubscription{ID: id}}, nil
+}
+
+func (m *mockWebhookService) DeleteWebhook(ctx context.Context, id uint) error { return m.byIDErr }
+
+func (m *mockWebhookService) ListDeliveries(ctx context.Context, id uint, limit int) ([]domain.WebhookDelivery, error) {
+	m.limit = limit
+	return []domain.WebhookDelivery{{ID: 3, SubscriptionID: id}}, nil
+}
+
+func (m *mockWebhookService) GetDelivery(ctx context.Context, id, deliveryID uint) (*domain.WebhookDelivery, error) {
+	return &domain.WebhookDelivery{ID: deliveryID, SubscriptionID: id, AttemptLog: []domain.WebhookAttempt{{ResponseStatus: 500}}}, nil
+}
+
+func (m *mockWebhookService) Redeliver(ctx context.Context, id, deliveryID uint) (*domain.WebhookDelivery, error) {
+	if m.redeliverErr != nil {
+		return nil, m.redeliverErr
+	}
+	return &domain.WebhookDelivery{ID: deliveryID, Status: domain.WebhookDeliveryPending}, nil
+}
+
+func newWebhookApp(svc port.WebhookService) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	h := NewWebhookHandler(svc)
+	app.Post("/webhooks", h.Create)
+	app.Get("/webhooks", h.GetAll)
+	app.Get("/webhooks/:id", h.GetByID)
+	app.Put("/webhooks/:id", h.Update)
+	app.Delete("/webhooks/:id", h.Delete)
+	app.Get("/webhooks/:id/deliveries", h.ListDeliveries)
+	app.Get("/webhooks/:id/deliveries/:deliveryId", h.GetDelivery)
+	app.Post("/webhooks/:id/deliveries/:deliveryId/redeliver", h.Redeliver)
+	return app
+}
+
+func decodeBody(t *testing.T, resp *http.Response) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return body
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	svc := &mockWebhookService{}
+	app := newWebhookApp(svc)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte("{")))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := app.Test(req)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	b, _ := json.Marshal(map[string]any{"url": "https://partner.example/hook", "event_types": []string{"booking.paid"}})
+	req = httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	data := decodeBody(t, resp)["data"].(map[string]any)
+	if data["secret"] != "whsec_x" || data["url"] != "https://partner.example/hook" {
+		t.Fatalf("expected the secret in the create response, got %v", data)
+	}
+	if svc.createReq.EventTypes[0] != "booking.paid" {
+		t.Fatalf("request not passed through: %+v", svc.createReq)
+	}
+
+	svc.createErr = domain.NewValidationError("unknown event type booking.deleted")
+	req = httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(b))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestWebhookHandler_SecretIsNotListed(t *testing.T) {
+	app := newWebhookApp(&mockWebhookService{})
+	for _, path := range []string{"/webhooks", "/webhooks/1"} {
+		resp, _ := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		if resp.StatusCode != http.StatusOK || bytes.Contains(buf.Bytes(), []byte("whsec_x")) {
+			t.Fatalf("%s: expected 200 without secret, got %d %s", path, resp.StatusCode, buf.String())
+		}
+	}
+}
+
+func TestWebhookHandler_DeliveriesAndRedeliver(t *testing.T) {
+	svc := &mockWebhookService{}
+	app := newWebhookApp(svc)
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries?limit=10", nil))
+	if resp.StatusCode != http.StatusOK || svc.limit != 10 {
+		t.Fatalf("expected 200 with limit 10, got %d / %d", resp.StatusCode, svc.limit)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries/3", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if log := decodeBody(t, resp)["attempt_log"].([]any); len(log) != 1 {
+		t.Fatalf("expected attempt log, got %v", log)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries/abc", nil))
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest(http.MethodPost, "/webhooks/1/deliveries/3/redeliver", nil))
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.StatusCode)
+	}
+
+	svc.redeliverErr = domain.NewConflictError("webhook is disabled; re-enable it before redelivering")
+	resp, _ = app.Test(httptest.NewRequest(http.MethodPost, "/webhooks/1/deliveries/3/redeliver", nil))
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", resp.StatusCode)
+	}
+}
+
+func TestWebhookHandler_DeleteNotFound(t *testing.T) {
+	app := newWebhookApp(&mockWebhookService{byIDErr: domain.NewNotFoundError("webhook not found")})
+	resp, _ := app.Test(httptest.NewRequest(http.MethodDelete, "/webhooks/1", nil))
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,48 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+// FileNotifier writes each notification as an .eml file, for development
+// and staging where mail must not leave the machine.
+type FileNotifier struct {
+	dir  string
+	from *mail.Address
+	now  func() time.Time
+}
+
+func NewFileNotifier(dir, from string) (port.Notifier, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("notification: invalid sender %q: %w", from, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileNotifier{dir: dir, from: addr, now: time.Now}, nil
+}
+
+func (n *FileNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	now := n.now()
+	raw, err := formatMessage(n.from, msg, now)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(n.dir, fmt.Sprintf("%s-%s-user%d-*.eml", now.UTC().Format("20060102T150405"), msg.Kind, msg.UserID))
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,24 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// LogNotifier writes notifications to the application log instead of
+// sending them. It is the default sink; the body is logged at debug level.
+type LogNotifier struct{}
+
+func NewLogNotifier() port.Notifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	logger := logging.FromContext(ctx)
+	logger.Info("notification", "kind", msg.Kind, "user_id", msg.UserID, "to", msg.To, "subject", msg.Subject)
+	logger.Debug("notification body", "kind", msg.Kind, "user_id", msg.UserID, "body", msg.Body)
+	return nil
+}
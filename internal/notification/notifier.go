@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// Options configure how booking notifications are rendered.
+type Options struct {
+	// DefaultLanguage is used for customers without a saved preference.
+	DefaultLanguage string
+	// Location is the time zone times are shown in when the booking's field
+	// has none; normally each field's own zone is used.
+	Location *time.Location
+	// PaymentWindow, when set, adds a pay-before deadline to the
+	// booking created message.
+	PaymentWindow time.Duration
+}
+
+type BookingNotifierImpl struct {
+	users  port.UserRepository
+	fields port.FieldRepository
+	prefs  port.NotificationPreferenceRepository
+	sink   port.Notifier
+	opts   Options
+}
+
+func NewBookingNotifier(users port.UserRepository, fields port.FieldRepository, prefs port.NotificationPreferenceRepository, sink port.Notifier, opts Options) port.BookingNotifier {
+	if opts.DefaultLanguage == "" {
+		opts.DefaultLanguage = domain.LanguageIndonesian
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &BookingNotifierImpl{users: users, fields: fields, prefs: prefs, sink: sink, opts: opts}
+}
+
+func (n *BookingNotifierImpl) NotifyBooking(ctx context.Context, kind string, booking *domain.Booking, reason string) error {
+	logger := logging.FromContext(ctx).With("kind", kind, "booking_id", booking.ID, "user_id", booking.UserID)
+	if kind == domain.NotificationBookingCreated && booking.Status != domain.BookingStatusPending {
+		// Paid at the counter; the payment email that follows confirms it.
+		logger.Debug("notification skipped: booking created paid")
+		return nil
+	}
+	return n.notify(ctx, logger, kind, booking, func(d *bookingDetails) {
+		d.reason = reason
+		if kind == domain.NotificationBookingCreated && n.opts.PaymentWindow > 0 && !booking.CreatedAt.IsZero() {
+			d.payBefore = booking.CreatedAt.Add(n.opts.PaymentWindow)
+		}
+	})
+}
+
+// NotifyWaitlistOffer tells a waiting customer their slot is held for them
+// and until when. The entry's slot is rendered like a booking without an ID.
+func (n *BookingNotifierImpl) NotifyWaitlistOffer(ctx context.Context, entry *domain.WaitlistEntry) error {
+	logger := logging.FromContext(ctx).With("kind", domain.NotificationWaitlistOffer, "waitlist_entry_id", entry.ID, "user_id", entry.UserID)
+	slot := &domain.Booking{
+		FieldID:   entry.FieldID,
+		UserID:    entry.UserID,
+		StartTime: entry.StartTime,
+		EndTime:   entry.EndTime,
+	}
+	return n.notify(ctx, logger, domain.NotificationWaitlistOffer, slot, func(d *bookingDetails) {
+		if entry.HoldExpiresAt != nil {
+			d.holdUntil = *entry.HoldExpiresAt
+		}
+	})
+}
+
+// notify renders kind for the booking's customer in their language and
+// field's time zone and sends it. Customers who have since been deleted, or
+// have no email like front desk guests, are skipped; set fills in the
+// details specific to kind.
+func (n *BookingNotifierImpl) notify(ctx context.Context, logger *slog.Logger, kind string, booking *domain.Booking, set func(*bookingDetails)) error {
+	user, err := n.users.GetByID(ctx, booking.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		logger.Warn("notification skipped: user no longer exists")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		logger.Debug("notification skipped: user has no email")
+		return nil
+	}
+
+	pref, err := n.prefs.Get(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	lang := n.opts.DefaultLanguage
+	if pref != nil {
+		lang = pref.Language
+		if pref.OptOutNonTransactional && !domain.IsTransactional(kind) {
+			logger.Debug("notification skipped: user opted out")
+			return nil
+		}
+	}
+
+	field, err := n.fields.GetByIDWithDeleted(ctx, booking.FieldID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	loc := n.opts.Location
+	if field != nil && field.TimeZone != "" {
+		if zone, err := field.Zone(); err == nil {
+			loc = zone
+		}
+	}
+	details := bookingDetails{name: user.Name, booking: booking, field: field, loc: loc}
+	set(&details)
+	subject, body, err := render(kind, lang, newTemplateData(lang, details))
+	if err != nil {
+		return err
+	}
+
+	return n.sink.Send(ctx, &domain.Notification{
+		Kind:     kind,
+		UserID:   user.ID,
+		Name:     user.Name,
+		To:       user.Email,
+		Language: lang,
+		Subject:  subject,
+		Body:     body,
+	})
+}
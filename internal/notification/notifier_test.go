@@ -0,0 +1,470 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+type stubUsers struct{ users map[uint]*domain.User }
+
+func (s *stubUsers) CreateUser(ctx context.Context, user *domain.User) error { return nil }
+func (s *stubUsers) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return nil, domain.NewNotFoundError("user not found")
+}
+func (s *stubUsers) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, domain.NewNotFoundError("user not found")
+}
+
+// stubFields only implements the lookups the notifier uses.
+type stubFields struct {
+	port.FieldRepository
+	fields map[uint]*domain.Field
+}
+
+func (s *stubFields) GetByIDWithDeleted(ctx context.Context, id uint) (*domain.Field, error) {
+	if f, ok := s.fields[id]; ok {
+		return f, nil
+	}
+	return nil, domain.NewNotFoundError("field not found")
+}
+
+type stubPrefs struct {
+	prefs map[uint]*domain.NotificationPreference
+}
+
+func (s *stubPrefs) Get(ctx context.Context, userID uint) (*domain.NotificationPreference, error) {
+	return s.prefs[userID], nil
+}
+func (s *stubPrefs) Save(ctx context.Context, pref *domain.NotificationPreference) error { return nil }
+
+type recordingSink struct{ sent []*domain.Notification }
+
+func (s *recordingSink) Send(ctx context.Context, n *domain.Notification) error {
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+var jakarta = time.FixedZone("WIB", 7*60*60)
+
+func newTestNotifier(prefs map[uint]*domain.NotificationPreference) (port.BookingNotifier, *recordingSink) {
+	user := &domain.User{Name: "Budi", Email: "budi@example.com"}
+	user.ID = 7
+	field := &domain.Field{Name: "Lapangan A", Location: "Jakarta Selatan", PricePerHour: 100000}
+	field.ID = 3
+	sink := &recordingSink{}
+	n := NewBookingNotifier(
+		&stubUsers{users: map[uint]*domain.User{7: user}},
+		&stubFields{fields: map[uint]*domain.Field{3: field}},
+		&stubPrefs{prefs: prefs},
+		sink,
+		Options{DefaultLanguage: domain.LanguageIndonesian, Location: jakarta, PaymentWindow: 30 * time.Minute},
+	)
+	return n, sink
+}
+
+func testBooking() *domain.Booking {
+	b := &domain.Booking{
+		FieldID:   3,
+		UserID:    7,
+		StartTime: time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC), // 18:00 WIB, a Monday
+		EndTime:   time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC),
+		Status:    domain.BookingStatusPending,
+	}
+	b.ID = 42
+	b.CreatedAt = time.Date(2025, 6, 1, 2, 0, 0, 0, time.UTC)
+	return b
+}
+
+func TestBookingNotifier_RendersInTheCustomersLanguage(t *testing.T) {
+	n, sink := newTestNotifier(map[uint]*domain.NotificationPreference{})
+	ctx := context.Background()
+
+	if err := n.NotifyBooking(ctx, domain.NotificationBookingCreated, testBooking(), ""); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	msg := sink.sent[0]
+	if msg.To != "budi@example.com" || msg.Language != "id" || msg.Subject != "Pemesanan #42 diterima: Lapangan A, Senin, 2 Juni 2025" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	for _, want := range []string{"Halo Budi,", "18:00 - 19:30 WIB", "Rp150.000", "Jakarta Selatan", "sebelum Minggu, 1 Juni 2025 09:30 WIB"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Fatalf("body missing %q:\n%s", want, msg.Body)
+		}
+	}
+
+	n, sink = newTestNotifier(map[uint]*domain.NotificationPreference{7: {UserID: 7, Language: domain.LanguageEnglish}})
+	_ = n.NotifyBooking(ctx, domain.NotificationPaymentReceived, testBooking(), "")
+	msg = sink.sent[0]
+	if msg.Subject != "Payment received for booking #42" || !strings.Contains(msg.Body, "Monday, 2 June 2025") || !strings.Contains(msg.Body, "IDR 150,000") {
+		t.Fatalf("unexpected english message: %s\n%s", msg.Subject, msg.Body)
+	}
+}
+
+func TestBookingNotifier_EveryKindAndLanguageRenders(t *testing.T) {
+	for _, lang := range languages {
+		n, sink := newTestNotifier(map[uint]*domain.NotificationPreference{7: {UserID: 7, Language: lang}})
+		for _, kind := range kinds {
+			if kind == domain.NotificationWaitlistOffer {
+				// Not about a booking; see TestBookingNotifier_WaitlistOffer.
+				continue
+			}
+			if err := n.NotifyBooking(context.Background(), kind, testBooking(), "rain"); err != nil {
+				t.Fatalf("%s/%s: %v", lang, kind, err)
+			}
+			msg := sink.sent[len(sink.sent)-1]
+			if msg.Subject == "" || strings.Contains(msg.Body, "<no value>") || !strings.Contains(msg.Body, "#42") {
+				t.Fatalf("%s/%s rendered badly: %q\n%s", lang, kind, msg.Subject, msg.Body)
+			}
+		}
+	}
+}
+
+func TestBookingNotifier_WaitlistOffer(t *testing.T) {
+	b := testBooking()
+	holdUntil := time.Date(2025, 6, 1, 8, 15, 0, 0, time.UTC)
+	entry := &domain.WaitlistEntry{ID: 5, FieldID: b.FieldID, UserID: b.UserID, StartTime: b.StartTime, EndTime: b.EndTime,
+		Status: domain.WaitlistStatusOffered, HoldExpiresAt: &holdUntil}
+
+	for lang, want := range map[string][]string{
+		domain.LanguageIndonesian: {"Jadwal yang Anda tunggu tersedia: Lapangan A, Senin, 2 Juni 2025", "18:00 - 19:30 WIB", "sebelum Minggu, 1 Juni 2025 15:15 WIB"},
+		domain.LanguageEnglish:    {"A slot you wanted is free: Lapangan A, Monday, 2 June 2025", "18:00 - 19:30 WIB", "before Sunday, 1 June 2025 15:15 WIB"},
+	} {
+		n, sink := newTestNotifier(map[uint]*domain.NotificationPreference{7: {UserID: 7, Language: lang, OptOutNonTransactional: true}})
+		if err := n.NotifyWaitlistOffer(context.Background(), entry); err != nil {
+			t.Fatalf("%s: %v", lang, err)
+		}
+		if len(sink.sent) != 1 {
+			t.Fatalf("%s: offers are transactional and must ignore the opt-out, sent %d", lang, len(sink.sent))
+		}
+		msg := sink.sent[0]
+		if msg.Kind != domain.NotificationWaitlistOffer || msg.Subject != want[0] {
+			t.Fatalf("%s: unexpected message %+v", lang, msg)
+		}
+		for _, w := range want[1:] {
+			if !strings.Contains(msg.Body, w) {
+				t.Fatalf("%s: body missing %q:\n%s", lang, w, msg.Body)
+			}
+		}
+	}
+}
+
+func TestBookingNotifier_CancellationOfRemovedField(t *testing.T) {
+	n, sink := newTestNotifier(nil)
+	b := testBooking()
+	if err := n.NotifyBooking(context.Background(), domain.NotificationBookingCancelled, b, domain.CancelReasonFieldRemoved); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if !strings.Contains(sink.sent[0].Body, "karena lapangan telah dihapus oleh pengelola") {
+		t.Fatalf("expected translated reason:\n%s", sink.sent[0].Body)
+	}
+
+	// A field that cannot be found at all still gets a message.
+	b.FieldID = 99
+	if err := n.NotifyBooking(context.Background(), domain.NotificationBookingCancelled, b, ""); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if !strings.Contains(sink.sent[1].Body, "#99") {
+		t.Fatalf("expected field number fallback:\n%s", sink.sent[1].Body)
+	}
+}
+
+func TestBookingNotifier_OptOutOnlySkipsNonTransactional(t *testing.T) {
+	n, sink := newTestNotifier(map[uint]*domain.NotificationPreference{
+		7: {UserID: 7, Language: domain.LanguageIndonesian, OptOutNonTransactional: true},
+	})
+	ctx := context.Background()
+	_ = n.NotifyBooking(ctx, domain.NotificationBookingReminder, testBooking(), "")
+	if len(sink.sent) != 0 {
+		t.Fatalf("reminder sent despite opt-out")
+	}
+	_ = n.NotifyBooking(ctx, domain.NotificationBookingExpired, testBooking(), "")
+	if len(sink.sent) != 1 {
+		t.Fatalf("transactional message must still be sent")
+	}
+
+	// unknown users are skipped without failing the event
+	b := testBooking()
+	b.UserID = 8
+	if err := n.NotifyBooking(ctx, domain.NotificationBookingCreated, b, ""); err != nil || len(sink.sent) != 1 {
+		t.Fatalf("expected silent skip, got err=%v sent=%d", err, len(sink.sent))
+	}
+}
+
+func TestBookingNotifier_SkipsGuestsAndCounterPayments(t *testing.T) {
+	n, sink := newTestNotifier(map[uint]*domain.NotificationPreference{})
+	ctx := context.Background()
+
+	// Paid at the counter: only the payment email goes out.
+	b := testBooking()
+	b.Status = domain.BookingStatusPaid
+	_ = n.NotifyBooking(ctx, domain.NotificationBookingCreated, b, "")
+	_ = n.NotifyBooking(ctx, domain.NotificationPaymentReceived, b, "")
+	if len(sink.sent) != 1 || sink.sent[0].Kind != domain.NotificationPaymentReceived {
+		t.Fatalf("expected only the payment email, got %+v", sink.sent)
+	}
+
+	guest := &domain.User{Name: "Andi", Phone: "0812345678", Role: domain.RoleGuest}
+	guest.ID = 9
+	sink = &recordingSink{}
+	n = NewBookingNotifier(&stubUsers{users: map[uint]*domain.User{9: guest}}, &stubFields{}, &stubPrefs{}, sink, Options{})
+	b.UserID = 9
+	if err := n.NotifyBooking(ctx, domain.NotificationPaymentReceived, b, ""); err != nil || len(sink.sent) != 0 {
+		t.Fatalf("guests have no email to send to, got err=%v sent=%d", err, len(sink.sent))
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	cases := []struct{ got, want string }{
+		{formatRupiah("id", 150000), "Rp150.000"},
+		{formatRupiah("en", 1250000), "IDR 1,250,000"},
+		{formatRupiah("id", 500), "Rp500"},
+		{formatDate("id", time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)), "Minggu, 17 Agustus 2025"},
+		{formatDate("en", time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)), "Sunday, 17 August 2025"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("got %q, want %q", c.got, c.want)
+		}
+	}
+	if total := totalPrice(100000, 90*time.Minute); total != 150000 {
+		t.Errorf("pro rata total = %d", total)
+	}
+}
+
+func testNotification() *domain.Notification {
+	return &domain.Notification{
+		Kind:     domain.NotificationPaymentReceived,
+		UserID:   7,
+		Name:     "Budi Santoso",
+		To:       "budi@example.com",
+		Language: domain.LanguageIndonesian,
+		Subject:  "Pembayaran diterima – terima kasih",
+		Body:     "Halo Budi,\n\nTerima kasih.\n",
+	}
+}
+
+// readMessage parses a raw message and decodes its body.
+func readMessage(t *testing.T, raw io.Reader) (*mail.Message, string) {
+	t.Helper()
+	m, err := mail.ReadMessage(raw)
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(m.Body))
+	if err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	return m, strings.ReplaceAll(string(body), "\r\n", "\n")
+}
+
+func TestFileNotifier_WritesEmlFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sink, err := NewFileNotifier(dir, "Sagara Booking <no-reply@sagara.test>")
+	if err != nil {
+		t.Fatalf("NewFileNotifier: %v", err)
+	}
+	if err := sink.Send(context.Background(), testNotification()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*-payment_received-user7-*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected one .eml file, got %v", files)
+	}
+	f, _ := os.Open(files[0])
+	defer f.Close()
+	m, body := readMessage(t, f)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != "Pembayaran diterima – terima kasih" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	if to, _ := m.Header.AddressList("To"); len(to) != 1 || to[0].Name != "Budi Santoso" {
+		t.Fatalf("unexpected recipient %v", to)
+	}
+	if body != "Halo Budi,\n\nTerima kasih.\n" {
+		t.Fatalf("unexpected body %q", body)
+	}
+
+	if _, err := NewFileNotifier(dir, "not an address"); err == nil {
+		t.Fatalf("expected invalid sender error")
+	}
+}
+
+// fakeSMTP accepts one message without STARTTLS or AUTH.
+func fakeSMTP(t *testing.T) (addr string, received <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	out := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+		reply("220 fake ESMTP")
+		var envelope, data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(cmd, "MAIL FROM"), strings.HasPrefix(cmd, "RCPT TO"):
+				envelope.WriteString(strings.TrimSpace(line) + "\n")
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				out <- envelope.String() + "\n" + data.String()
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+func TestSMTPNotifier_Sends(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	host, port, _ := net.SplitHostPort(addr)
+	sink, err := NewSMTPNotifier(SMTPConfig{Host: host, Port: port, From: "Sagara Booking <no-reply@sagara.test>"})
+	if err != nil {
+		t.Fatalf("NewSMTPNotifier: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sink.Send(ctx, testNotification()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	got := <-received
+	envelope, raw, _ := strings.Cut(got, "\n\n")
+	if !strings.Contains(envelope, "MAIL FROM:<no-reply@sagara.test>") || !strings.Contains(envelope, "RCPT TO:<budi@example.com>") {
+		t.Fatalf("unexpected envelope:\n%s", envelope)
+	}
+	m, body := readMessage(t, strings.NewReader(raw))
+	if m.Header.Get("Content-Language") != "id" || body != "Halo Budi,\n\nTerima kasih.\n" {
+		t.Fatalf("unexpected message: %v %q", m.Header, body)
+	}
+}
+
+func TestSMTPNotifier_RespectsContext(t *testing.T) {
+	// A server that accepts but never greets must not hang the sender.
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			time.Sleep(time.Second)
+			conn.Close()
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	sink, _ := NewSMTPNotifier(SMTPConfig{Host: host, Port: port, From: "no-reply@sagara.test"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := sink.Send(ctx, testNotification()); err == nil {
+		t.Fatalf("expected timeout error")
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("send ignored the context deadline")
+	}
+}
+
+type stubBookings struct {
+	port.BookingRepository
+	bookings map[uint]*domain.Booking
+}
+
+func (s *stubBookings) GetByID(ctx context.Context, id uint) (*domain.Booking, error) {
+	if b, ok := s.bookings[id]; ok {
+		return b, nil
+	}
+	return nil, domain.NewNotFoundError("booking not found")
+}
+
+func TestReminderHandler_SkipsChangedBookings(t *testing.T) {
+	n, sink := newTestNotifier(nil)
+	booking := testBooking()
+	booking.Status = domain.BookingStatusPaid
+	bookings := &stubBookings{bookings: map[uint]*domain.Booking{42: booking}}
+	handler := ReminderHandler(bookings, n)
+
+	event, _ := domain.NewBookingEvent(domain.EventBookingReminderDue, booking, "", time.Now())
+	ctx := context.Background()
+	if err := handler.HandleEvent(ctx, &event); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(sink.sent) != 1 || sink.sent[0].Kind != domain.NotificationBookingReminder {
+		t.Fatalf("expected one reminder, got %+v", sink.sent)
+	}
+
+	// cancelled after the reminder was queued
+	booking.Status = domain.BookingStatusCancelled
+	_ = handler.HandleEvent(ctx, &event)
+	// moved to another time
+	booking.Status = domain.BookingStatusPaid
+	booking.StartTime = booking.StartTime.Add(24 * time.Hour)
+	_ = handler.HandleEvent(ctx, &event)
+	// deleted altogether
+	delete(bookings.bookings, 42)
+	if err := handler.HandleEvent(ctx, &event); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if len(sink.sent) != 1 {
+		t.Fatalf("expected no further reminders, got %d", len(sink.sent))
+	}
+}
+
+func TestBookingNotifier_UsesTheFieldsTimeZone(t *testing.T) {
+	n, sink := newTestNotifier(nil)
+	field := n.(*BookingNotifierImpl).fields.(*stubFields).fields[3]
+	field.TimeZone = "Asia/Makassar"
+
+	_ = n.NotifyBooking(context.Background(), domain.NotificationPaymentReceived, testBooking(), "")
+	if !strings.Contains(sink.sent[0].Body, "19:00 - 20:30 WITA") {
+		t.Fatalf("expected Makassar time:\n%s", sink.sent[0].Body)
+	}
+}
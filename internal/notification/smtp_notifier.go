@@ -0,0 +1,108 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+// SMTPConfig points at the relay that sends customer email.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier sends notifications through an SMTP relay, upgrading to TLS
+// with STARTTLS whenever the server offers it.
+type SMTPNotifier struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from *mail.Address
+	now  func() time.Time
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) (port.Notifier, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("notification: invalid sender %q: %w", cfg.From, err)
+	}
+	n := &SMTPNotifier{
+		addr: net.JoinHostPort(cfg.Host, cfg.Port),
+		host: cfg.Host,
+		from: from,
+		now:  time.Now,
+	}
+	if cfg.Username != "" {
+		// PlainAuth refuses to send credentials over an unencrypted
+		// connection to anything but localhost.
+		n.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return n, nil
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	raw, err := formatMessage(n.from, msg, n.now())
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp knows nothing about contexts; the deadline bounds the whole
+	// conversation instead.
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server does not support authentication")
+		}
+		if err := c.Auth(n.auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.from.Address); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
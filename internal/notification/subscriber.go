@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// eventKinds maps the booking events customers hear about to the
+// notification sent for them.
+var eventKinds = map[string]string{
+	domain.EventBookingCreated:   domain.NotificationBookingCreated,
+	domain.EventBookingPaid:      domain.NotificationPaymentReceived,
+	domain.EventBookingCancelled: domain.NotificationBookingCancelled,
+	domain.EventBookingExpired:   domain.NotificationBookingExpired,
+}
+
+// EventTypes are the outbox events EventHandler should be subscribed to.
+func EventTypes() []string {
+	return []string{
+		domain.EventBookingCreated,
+		domain.EventBookingPaid,
+		domain.EventBookingCancelled,
+		domain.EventBookingExpired,
+	}
+}
+
+// EventHandler emails customers about their booking events. A retried event
+// is only handled again if sending its email failed, or the instance died
+// before recording that it was sent.
+func EventHandler(notifier port.BookingNotifier) port.EventHandler {
+	return port.EventHandlerFunc(func(ctx context.Context, event *domain.OutboxEvent) error {
+		kind, ok := eventKinds[event.EventType]
+		if !ok {
+			return nil
+		}
+		p, err := event.BookingPayload()
+		if err != nil {
+			return err
+		}
+		booking := bookingFromPayload(p)
+		return notifier.NotifyBooking(ctx, kind, &booking, p.Reason)
+	})
+}
+
+// ReminderHandler sends the reminder for booking.reminder_due events. The
+// booking is looked up again first, so one cancelled or rescheduled since
+// the reminder was queued is not reminded about.
+func ReminderHandler(bookings port.BookingRepository, notifier port.BookingNotifier) port.EventHandler {
+	return port.EventHandlerFunc(func(ctx context.Context, event *domain.OutboxEvent) error {
+		p, err := event.BookingPayload()
+		if err != nil {
+			return err
+		}
+		booking, err := bookings.GetByID(ctx, p.BookingID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if booking.Status != domain.BookingStatusPaid || !booking.StartTime.Equal(p.StartTime) {
+			logging.FromContext(ctx).Debug("reminder skipped: booking changed", "booking_id", booking.ID, "status", booking.Status)
+			return nil
+		}
+		return notifier.NotifyBooking(ctx, domain.NotificationBookingReminder, booking, "")
+	})
+}
+
+// WaitlistHandler tells customers about waitlist.offered events.
+func WaitlistHandler(notifier port.BookingNotifier) port.EventHandler {
+	return port.EventHandlerFunc(func(ctx context.Context, event *domain.OutboxEvent) error {
+		p, err := event.WaitlistPayload()
+		if err != nil {
+			return err
+		}
+		entry := domain.WaitlistEntry{
+			ID:            p.EntryID,
+			FieldID:       p.FieldID,
+			UserID:        p.UserID,
+			StartTime:     p.StartTime,
+			EndTime:       p.EndTime,
+			Status:        domain.WaitlistStatusOffered,
+			HoldExpiresAt: p.HoldExpiresAt,
+		}
+		return notifier.NotifyWaitlistOffer(ctx, &entry)
+	})
+}
+
+func bookingFromPayload(p domain.BookingEventPayload) domain.Booking {
+	booking := domain.Booking{
+		FieldID:   p.FieldID,
+		UserID:    p.UserID,
+		StartTime: p.StartTime,
+		EndTime:   p.EndTime,
+		Status:    p.Status,
+	}
+	booking.ID = p.BookingID
+	booking.CreatedAt = p.CreatedAt
+	return booking
+}
@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var kinds = []string{
+	domain.NotificationBookingCreated,
+	domain.NotificationPaymentReceived,
+	domain.NotificationBookingReminder,
+	domain.NotificationBookingCancelled,
+	domain.NotificationBookingExpired,
+	domain.NotificationWaitlistOffer,
+}
+
+var languages = []string{domain.LanguageIndonesian, domain.LanguageEnglish}
+
+// templates holds one parsed template per language and kind, each defining
+// "subject" and "body". Parsing them all up front turns a missing or broken
+// template into a startup failure rather than a lost email.
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]*template.Template {
+	parsed := map[string]*template.Template{}
+	for _, lang := range languages {
+		for _, kind := range kinds {
+			name := "templates/" + lang + "/" + kind + ".tmpl"
+			t := template.Must(template.ParseFS(templateFS, name))
+			for _, part := range []string{"subject", "body"} {
+				if t.Lookup(part) == nil {
+					panic(fmt.Sprintf("notification: %s does not define %q", name, part))
+				}
+			}
+			parsed[lang+"/"+kind] = t
+		}
+	}
+	return parsed
+}
+
+// reasonTranslations localise the cancellation reasons the system itself
+// gives. Other reasons are shown as written.
+var reasonTranslations = map[string]map[string]string{
+	domain.LanguageIndonesian: {
+		domain.CancelReasonFieldRemoved: "lapangan telah dihapus oleh pengelola",
+	},
+}
+
+var (
+	indonesianDays   = []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+	indonesianMonths = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+)
+
+// templateData is what every template can use. Times are already converted
+// to the venue's time zone and formatted for the language.
+type templateData struct {
+	Name      string
+	BookingID uint
+	FieldName string
+	Location  string
+	Date      string
+	From      string
+	To        string
+	Zone      string
+	Total     string
+	PayBefore string
+	HoldUntil string
+	Reason    string
+}
+
+type bookingDetails struct {
+	name      string
+	booking   *domain.Booking
+	field     *domain.Field
+	reason    string
+	payBefore time.Time
+	holdUntil time.Time
+	loc       *time.Location
+}
+
+func newTemplateData(lang string, d bookingDetails) templateData {
+	start, end := d.booking.StartTime.In(d.loc), d.booking.EndTime.In(d.loc)
+	data := templateData{
+		Name:      d.name,
+		BookingID: d.booking.ID,
+		FieldName: fmt.Sprintf("#%d", d.booking.FieldID),
+		Date:      formatDate(lang, start),
+		From:      start.Format("15:04"),
+		To:        end.Format("15:04"),
+		Zone:      start.Format("MST"),
+		Reason:    d.reason,
+	}
+	if translated, ok := reasonTranslations[lang][d.reason]; ok {
+		data.Reason = translated
+	}
+	if d.field != nil {
+		data.FieldName = d.field.Name
+		data.Location = d.field.Location
+		data.Total = formatRupiah(lang, totalPrice(d.field.PricePerHour, end.Sub(start)))
+	}
+	if !d.payBefore.IsZero() {
+		at := d.payBefore.In(d.loc)
+		data.PayBefore = formatDate(lang, at) + " " + at.Format("15:04 MST")
+	}
+	if !d.holdUntil.IsZero() {
+		at := d.holdUntil.In(d.loc)
+		data.HoldUntil = formatDate(lang, at) + " " + at.Format("15:04 MST")
+	}
+	return data
+}
+
+// render executes the template for kind in lang.
+func render(kind, lang string, data templateData) (subject, body string, err error) {
+	t, ok := templates[lang+"/"+kind]
+	if !ok {
+		return "", "", fmt.Errorf("notification: no %s template for language %q", kind, lang)
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+	buf.Reset()
+	if err := t.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimLeft(buf.String(), "\n"), nil
+}
+
+// formatDate writes e.g. "Senin, 2 Juni 2025" or "Monday, 2 June 2025".
+func formatDate(lang string, t time.Time) string {
+	if lang == domain.LanguageIndonesian {
+		return fmt.Sprintf("%s, %d %s %d", indonesianDays[t.Weekday()], t.Day(), indonesianMonths[t.Month()-1], t.Year())
+	}
+	return t.Format("Monday, 2 January 2006")
+}
+
+// totalPrice charges the hourly price pro rata to the minute.
+func totalPrice(pricePerHour int, d time.Duration) int {
+	return pricePerHour * int(d/time.Minute) / 60
+}
+
+// formatRupiah writes e.g. "Rp150.000" or "IDR 150,000".
+func formatRupiah(lang string, amount int) string {
+	digits := strconv.Itoa(amount)
+	sep, prefix := ",", "IDR "
+	if lang == domain.LanguageIndonesian {
+		sep, prefix = ".", "Rp"
+	}
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteRune(r)
+	}
+	return prefix + b.String()
+}
@@ -0,0 +1,220 @@
+// Package outbox delivers domain events written to outbox_events to the
+// in-process handlers subscribed to them.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// Options tune the dispatcher. Zero values fall back to DefaultOptions.
+type Options struct {
+	// PollInterval is how long to sleep when no event was due.
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease is how long a claimed batch is reserved for this instance. It
+	// must comfortably exceed BatchSize × HandlerTimeout.
+	Lease          time.Duration
+	HandlerTimeout time.Duration
+	// MaxAttempts failed deliveries move an event to the dead state.
+	MaxAttempts int
+	// Retries back off exponentially from BaseBackoff, capped at MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+var DefaultOptions = Options{
+	PollInterval:   time.Second,
+	BatchSize:      20,
+	Lease:          5 * time.Minute,
+	HandlerTimeout: 10 * time.Second,
+	MaxAttempts:    10,
+	BaseBackoff:    5 * time.Second,
+	MaxBackoff:     time.Hour,
+}
+
+type subscriber struct {
+	name    string
+	handler port.EventHandler
+}
+
+// Dispatcher claims due events, runs every handler subscribed to the event
+// type and records the outcome. An event is delivered only when all its
+// handlers succeed; otherwise the next attempt runs only the handlers that
+// have not succeeded yet, so one failing subscriber does not make the
+// others repeat their work.
+type Dispatcher struct {
+	repo        port.OutboxRepository
+	opts        Options
+	subscribers map[string][]subscriber
+	now         func() time.Time
+}
+
+func NewDispatcher(repo port.OutboxRepository, opts Options) *Dispatcher {
+	d := DefaultOptions
+	if opts.PollInterval > 0 {
+		d.PollInterval = opts.PollInterval
+	}
+	if opts.BatchSize > 0 {
+		d.BatchSize = opts.BatchSize
+	}
+	if opts.Lease > 0 {
+		d.Lease = opts.Lease
+	}
+	if opts.HandlerTimeout > 0 {
+		d.HandlerTimeout = opts.HandlerTimeout
+	}
+	if opts.MaxAttempts > 0 {
+		d.MaxAttempts = opts.MaxAttempts
+	}
+	if opts.BaseBackoff > 0 {
+		d.BaseBackoff = opts.BaseBackoff
+	}
+	if opts.MaxBackoff > 0 {
+		d.MaxBackoff = opts.MaxBackoff
+	}
+	return &Dispatcher{
+		repo:        repo,
+		opts:        d,
+		subscribers: map[string][]subscriber{},
+		now:         time.Now,
+	}
+}
+
+// Subscribe registers handler for eventType. name identifies it in logs, in
+// the event's last error and in the record of the events it handled, so it
+// must be unique per event type and kept across releases. Subscribe before
+// Run.
+func (d *Dispatcher) Subscribe(eventType, name string, handler port.EventHandler) {
+	for _, sub := range d.subscribers[eventType] {
+		if sub.name == name {
+			panic(fmt.Sprintf("outbox: %q is already subscribed to %s", name, eventType))
+		}
+	}
+	d.subscribers[eventType] = append(d.subscribers[eventType], subscriber{name: name, handler: handler})
+}
+
+// Run dispatches until ctx is cancelled, calling beat once per iteration so
+// a stalled loop shows up in readiness checks.
+func (d *Dispatcher) Run(ctx context.Context, beat func()) {
+	for {
+		beat()
+		n, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("outbox: dispatch failed", "error", err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.opts.PollInterval):
+		}
+	}
+}
+
+// DispatchOnce claims and processes one batch, returning how many events it
+// claimed.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	now := d.now()
+	events, err := d.repo.ClaimDue(ctx, now, now.Add(d.opts.Lease), d.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range events {
+		if ctx.Err() != nil {
+			// Unprocessed events stay leased and are retried after the lease.
+			return len(events), ctx.Err()
+		}
+		d.process(ctx, &events[i])
+	}
+	return len(events), nil
+}
+
+func (d *Dispatcher) process(ctx context.Context, event *domain.OutboxEvent) {
+	logger := logging.FromContext(ctx).With("event_id", event.ID, "event_type", event.EventType)
+	event.Attempts++
+
+	if err := d.deliver(ctx, event); err != nil {
+		event.LastError = err.Error()
+		if event.Attempts >= d.opts.MaxAttempts {
+			event.Status = domain.OutboxStatusDead
+			logger.Error("outbox: event dead-lettered", "attempts", event.Attempts, "error", err)
+		} else {
+			event.NextAttemptAt = d.now().Add(d.backoff(event.Attempts))
+			logger.Warn("outbox: delivery failed, will retry",
+				"attempts", event.Attempts, "next_attempt_at", event.NextAttemptAt, "error", err)
+		}
+		if err := d.repo.MarkFailed(ctx, event); err != nil {
+			logger.Error("outbox: recording failure", "error", err)
+		}
+		return
+	}
+
+	if err := d.repo.MarkDelivered(ctx, event, d.now()); err != nil {
+		logger.Error("outbox: recording delivery", "error", err)
+	}
+}
+
+// deliver runs every subscriber that has not handled event yet, records
+// each that succeeds, and joins their failures.
+func (d *Dispatcher) deliver(ctx context.Context, event *domain.OutboxEvent) error {
+	done := make(map[string]bool, len(event.DeliveredTo))
+	for _, name := range event.DeliveredTo {
+		done[name] = true
+	}
+	var failures []string
+	for _, sub := range d.subscribers[event.EventType] {
+		if done[sub.name] {
+			continue
+		}
+		if err := d.handle(ctx, sub, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+			continue
+		}
+		// Failing to record a success only means the subscriber may run
+		// again if the event is retried.
+		if err := d.repo.MarkSubscriberDelivered(ctx, event, sub.name, d.now()); err != nil {
+			logging.FromContext(ctx).Error("outbox: recording subscriber delivery",
+				"event_id", event.ID, "subscriber", sub.name, "error", err)
+		}
+		event.DeliveredTo = append(event.DeliveredTo, sub.name)
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// handle runs one subscriber with a timeout and turns a panic into an error
+// so one bad handler cannot take the worker down.
+func (d *Dispatcher) handle(ctx context.Context, sub subscriber, event *domain.OutboxEvent) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.HandlerTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handler.HandleEvent(ctx, event)
+}
+
+// backoff doubles from BaseBackoff for each attempt, capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.BaseBackoff
+	for i := 1; i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.opts.MaxBackoff {
+		delay = d.opts.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,307 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/internal/notification"
+)
+
+// memoryRepo is an in-memory OutboxRepository with the same lease rules as
+// the Postgres one.
+type memoryRepo struct {
+	events    []*domain.OutboxEvent
+	delivered map[uint][]string
+}
+
+func (r *memoryRepo) add(eventType string, payload string) *domain.OutboxEvent {
+	e := &domain.OutboxEvent{
+		ID:        uint(len(r.events) + 1),
+		EventType: eventType,
+		Payload:   []byte(payload),
+		Status:    domain.OutboxStatusPending,
+	}
+	r.events = append(r.events, e)
+	return e
+}
+
+func (r *memoryRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	var claimed []domain.OutboxEvent
+	for _, e := range r.events {
+		if len(claimed) == limit {
+			break
+		}
+		if e.Status != domain.OutboxStatusPending || e.NextAttemptAt.After(now) {
+			continue
+		}
+		if e.LockedUntil != nil && e.LockedUntil.After(now) {
+			continue
+		}
+		lease := leaseUntil
+		e.LockedUntil = &lease
+		claim := *e
+		claim.DeliveredTo = append([]string(nil), r.delivered[e.ID]...)
+		claimed = append(claimed, claim)
+	}
+	return claimed, nil
+}
+
+func (r *memoryRepo) MarkSubscriberDelivered(ctx context.Context, event *domain.OutboxEvent, subscriber string, at time.Time) error {
+	if r.delivered == nil {
+		r.delivered = map[uint][]string{}
+	}
+	r.delivered[event.ID] = append(r.delivered[event.ID], subscriber)
+	return nil
+}
+
+func (r *memoryRepo) release(event *domain.OutboxEvent) (*domain.OutboxEvent, error) {
+	stored := r.events[event.ID-1]
+	if stored.LockedUntil == nil || event.LockedUntil == nil || !stored.LockedUntil.Equal(*event.LockedUntil) {
+		return nil, domain.NewConflictError("outbox event lease was lost")
+	}
+	*stored = *event
+	stored.LockedUntil = nil
+	return stored, nil
+}
+
+func (r *memoryRepo) MarkDelivered(ctx context.Context, event *domain.OutboxEvent, at time.Time) error {
+	stored, err := r.release(event)
+	if err != nil {
+		return err
+	}
+	stored.Status = domain.OutboxStatusDelivered
+	stored.DeliveredAt = &at
+	return nil
+}
+
+func (r *memoryRepo) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	_, err := r.release(event)
+	return err
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestDispatcher(repo port.OutboxRepository, opts Options) (*Dispatcher, *clock) {
+	c := &clock{t: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)}
+	d := NewDispatcher(repo, opts)
+	d.now = c.now
+	return d, c
+}
+
+func TestDispatcher_DeliversToEverySubscriber(t *testing.T) {
+	repo := &memoryRepo{}
+	created := repo.add(domain.EventBookingCreated, `{}`)
+	unhandled := repo.add("field.renamed", `{}`)
+
+	d, _ := newTestDispatcher(repo, Options{})
+	var calls []string
+	for _, name := range []string{"a", "b"} {
+		name := name
+		d.Subscribe(domain.EventBookingCreated, name, port.EventHandlerFunc(func(ctx context.Context, e *domain.OutboxEvent) error {
+			calls = append(calls, name)
+			return nil
+		}))
+	}
+
+	n, err := d.DispatchOnce(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 claimed, got %d err=%v", n, err)
+	}
+	if strings.Join(calls, ",") != "a,b" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+	// Events nobody subscribes to are delivered too, so they do not pile up.
+	for _, e := range []*domain.OutboxEvent{created, unhandled} {
+		if e.Status != domain.OutboxStatusDelivered || e.Attempts != 1 || e.DeliveredAt == nil {
+			t.Fatalf("event %d not delivered: %+v", e.ID, e)
+		}
+	}
+	if n, _ := d.DispatchOnce(context.Background()); n != 0 {
+		t.Fatalf("delivered events must not be claimed again, got %d", n)
+	}
+}
+
+func TestDispatcher_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	repo := &memoryRepo{}
+	event := repo.add(domain.EventBookingPaid, `{}`)
+
+	d, c := newTestDispatcher(repo, Options{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: 90 * time.Second})
+	d.Subscribe(domain.EventBookingPaid, "flaky", port.EventHandlerFunc(func(ctx context.Context, e *domain.OutboxEvent) error {
+		return errors.New("smtp unavailable")
+	}))
+
+	_, _ = d.DispatchOnce(context.Background())
+	if event.Status != domain.OutboxStatusPending || event.Attempts != 1 || !event.NextAttemptAt.Equal(c.t.Add(time.Second)) {
+		t.Fatalf("expected retry in 1s, got %+v", event)
+	}
+	if event.LastError != "flaky: smtp unavailable" {
+		t.Fatalf("unexpected last error %q", event.LastError)
+	}
+
+	// not due yet
+	if n, _ := d.DispatchOnce(context.Background()); n != 0 {
+		t.Fatalf("event claimed before its backoff elapsed")
+	}
+
+	c.advance(time.Second)
+	_, _ = d.DispatchOnce(context.Background())
+	if event.Attempts != 2 || !event.NextAttemptAt.Equal(c.t.Add(2*time.Second)) {
+		t.Fatalf("expected retry in 2s, got %+v", event)
+	}
+
+	c.advance(2 * time.Second)
+	_, _ = d.DispatchOnce(context.Background())
+	if event.Status != domain.OutboxStatusDead || event.Attempts != 3 {
+		t.Fatalf("expected dead-lettered event, got %+v", event)
+	}
+}
+
+func TestDispatcher_RetriesOnlyFailedSubscribers(t *testing.T) {
+	repo := &memoryRepo{}
+	event := repo.add(domain.EventBookingCancelled, `{}`)
+
+	d, c := newTestDispatcher(repo, Options{BaseBackoff: time.Second})
+	calls := map[string]int{}
+	for _, name := range []string{"notifications", "waitlist", "webhooks"} {
+		name := name
+		d.Subscribe(domain.EventBookingCancelled, name, port.EventHandlerFunc(func(ctx context.Context, e *domain.OutboxEvent) error {
+			calls[name]++
+			if name == "waitlist" && calls[name] == 1 {
+				return errors.New("database unavailable")
+			}
+			return nil
+		}))
+	}
+
+	_, _ = d.DispatchOnce(context.Background())
+	if event.Status != domain.OutboxStatusPending || event.LastError != "waitlist: database unavailable" {
+		t.Fatalf("expected a retry for the waitlist, got %+v", event)
+	}
+	c.advance(time.Second)
+	_, _ = d.DispatchOnce(context.Background())
+	if event.Status != domain.OutboxStatusDelivered || event.Attempts != 2 {
+		t.Fatalf("expected delivery on the second attempt, got %+v", event)
+	}
+	if calls["notifications"] != 1 || calls["webhooks"] != 1 || calls["waitlist"] != 2 {
+		t.Fatalf("expected only the failed subscriber to run again, got %v", calls)
+	}
+}
+
+func TestDispatcher_SubscribingANameTwicePanics(t *testing.T) {
+	d, _ := newTestDispatcher(&memoryRepo{}, Options{})
+	nop := port.EventHandlerFunc(func(ctx context.Context, e *domain.OutboxEvent) error { return nil })
+	d.Subscribe(domain.EventBookingPaid, "notifications", nop)
+	d.Subscribe(domain.EventBookingCreated, "notifications", nop)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	d.Subscribe(domain.EventBookingPaid, "notifications", nop)
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d, _ := newTestDispatcher(&memoryRepo{}, Options{BaseBackoff: 5 * time.Second, MaxBackoff: time.Minute})
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, w := range want {
+		if got := d.backoff(i + 1); got != w {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+}
+
+func TestDispatcher_RecoversFromPanics(t *testing.T) {
+	repo := &memoryRepo{}
+	event := repo.add(domain.EventBookingCreated, `{}`)
+
+	d, _ := newTestDispatcher(repo, Options{})
+	d.Subscribe(domain.EventBookingCreated, "broken", port.EventHandlerFunc(func(ctx context.Context, e *domain.OutboxEvent) error {
+		panic("nil map")
+	}))
+
+	if _, err := d.DispatchOnce(context.Background()); err != nil {
+		t.Fatalf("dispatch error: %v", err)
+	}
+	if event.Status != domain.OutboxStatusPending || !strings.Contains(event.LastError, "panic: nil map") {
+		t.Fatalf("expected panic recorded as a failure, got %+v", event)
+	}
+}
+
+func TestDispatcher_LostLeaseIsNotOverwritten(t *testing.T) {
+	repo := &memoryRepo{}
+	event := repo.add(domain.EventBookingCreated, `{}`)
+
+	d, c := newTestDispatcher(repo, Options{Lease: time.Minute})
+	d.Subscribe(domain.EventBookingCreated, "slow", port.EventHandlerFunc(func(ctx context.Context, e *domain.OutboxEvent) error {
+		// Another instance reclaims the event after the lease ran out.
+		c.advance(2 * time.Minute)
+		_, _ = repo.ClaimDue(ctx, c.t, c.t.Add(time.Minute), 1)
+		return nil
+	}))
+
+	_, _ = d.DispatchOnce(context.Background())
+	if event.Status != domain.OutboxStatusPending || event.Attempts != 0 {
+		t.Fatalf("stale lease holder must not record the outcome, got %+v", event)
+	}
+}
+
+type recordingNotifier struct {
+	kinds    []string
+	bookings []domain.Booking
+	reasons  []string
+}
+
+func (n *recordingNotifier) NotifyBooking(ctx context.Context, kind string, booking *domain.Booking, reason string) error {
+	n.kinds = append(n.kinds, kind)
+	n.bookings = append(n.bookings, *booking)
+	n.reasons = append(n.reasons, reason)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyWaitlistOffer(ctx context.Context, entry *domain.WaitlistEntry) error {
+	n.kinds = append(n.kinds, domain.NotificationWaitlistOffer)
+	return nil
+}
+
+func TestDispatcher_BookingNotifications(t *testing.T) {
+	repo := &memoryRepo{}
+	booking := domain.Booking{FieldID: 3, UserID: 7, Status: domain.BookingStatusCancelled,
+		StartTime: time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 6, 2, 19, 0, 0, 0, time.UTC)}
+	booking.ID = 42
+	booking.CreatedAt = time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	event, err := domain.NewBookingEvent(domain.EventBookingCancelled, &booking, domain.CancelReasonFieldRemoved, time.Now())
+	if err != nil {
+		t.Fatalf("NewBookingEvent: %v", err)
+	}
+	stored := repo.add(event.EventType, string(event.Payload))
+	repo.add(domain.EventBookingPaid, string(event.Payload))
+
+	notifier := &recordingNotifier{}
+	d, _ := newTestDispatcher(repo, Options{})
+	for _, eventType := range notification.EventTypes() {
+		d.Subscribe(eventType, "notifications", notification.EventHandler(notifier))
+	}
+
+	_, _ = d.DispatchOnce(context.Background())
+	if stored.Status != domain.OutboxStatusDelivered {
+		t.Fatalf("expected delivered, got %+v", stored)
+	}
+	if len(notifier.kinds) != 2 || notifier.kinds[0] != domain.NotificationBookingCancelled || notifier.kinds[1] != domain.NotificationPaymentReceived {
+		t.Fatalf("unexpected notifications: %v", notifier.kinds)
+	}
+	got := notifier.bookings[0]
+	if got.ID != 42 || got.UserID != 7 || got.FieldID != 3 || !got.StartTime.Equal(booking.StartTime) || !got.CreatedAt.Equal(booking.CreatedAt) {
+		t.Fatalf("unexpected booking: %+v", got)
+	}
+	if notifier.reasons[0] != domain.CancelReasonFieldRemoved {
+		t.Fatalf("unexpected reason %q", notifier.reasons[0])
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+)
+
+// bookingExportColumns selects a domain.BookingExportRow; the customer's
+// password is never read.
+const bookingExportColumns = `bookings.id AS booking_id, bookings.status, bookings.start_time, bookings.end_time,
+	to_char(bookings.start_time AT TIME ZONE fields.time_zone, 'YYYY-MM-DD HH24:MI') AS local_start,
+	EXTRACT(EPOCH FROM bookings.end_time - bookings.start_time)::bigint / 60 AS minutes,
+	fields.id AS field_id, fields.name AS field_name, fields.location AS venue, fields.time_zone,
+	users.id AS user_id, users.name AS user_name, COALESCE(users.email, '') AS user_email,
+	fields.price_per_hour, bookings.amount_paid, COALESCE(bookings.payment_reference, '') AS payment_reference,
+	bookings.created_at, bookings.paid_at, bookings.cancelled_at, bookings.expired_at, bookings.no_show_at`
+
+// Export streams from a single query, so the connection stays busy until
+// the rows are closed.
+func (r *BookingRepositoryDB) Export(ctx context.Context, filter port.BookingFilter) (port.BookingRows, error) {
+	db := r.db.WithContext(ctx)
+	rows, err := filterBookings(db.Table("bookings"), filter).
+		Joins("JOIN users ON users.id = bookings.user_id").
+		Where("bookings.deleted_at IS NULL").
+		Select(bookingExportColumns).
+		Order("bookings.start_time, bookings.id").
+		Rows()
+	if err != nil {
+		return nil, translateError(err, "booking")
+	}
+	return &bookingRows{db: db, rows: rows}, nil
+}
+
+type bookingRows struct {
+	db   *gorm.DB
+	rows *sql.Rows
+}
+
+func (b *bookingRows) Next() bool {
+	return b.rows.Next()
+}
+
+func (b *bookingRows) Scan(row *domain.BookingExportRow) error {
+	*row = domain.BookingExportRow{}
+	return translateError(b.db.ScanRows(b.rows, row), "booking")
+}
+
+func (b *bookingRows) Err() error {
+	return translateError(b.rows.Err(), "booking")
+}
+
+func (b *bookingRows) Close() error {
+	return b.rows.Close()
+}
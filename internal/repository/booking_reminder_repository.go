@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookingReminderRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewBookingReminderRepository(db *gorm.DB) port.BookingReminderRepository {
+	return &BookingReminderRepositoryDB{db: db}
+}
+
+// ScheduleDue skips bookings locked by another instance, and the
+// booking_reminders primary key turns any reminder that slips through into a
+// no-op, so each reminder gets exactly one event.
+func (r *BookingReminderRepositoryDB) ScheduleDue(ctx context.Context, offset time.Duration, after, until time.Time, limit int) ([]domain.Booking, error) {
+	seconds := int64(offset / time.Second)
+	var due []domain.Booking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		due = nil
+		var candidates []domain.Booking
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND start_time > ? AND start_time <= ?", domain.BookingStatusPaid, after, until).
+			Where("NOT EXISTS (SELECT 1 FROM booking_reminders r WHERE r.booking_id = bookings.id AND r.offset_seconds = ?)", seconds).
+			Order("start_time").Limit(limit).
+			Find(&candidates).Error
+		if err != nil || len(candidates) == 0 {
+			return err
+		}
+		for _, booking := range candidates {
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&domain.BookingReminder{BookingID: booking.ID, OffsetSeconds: seconds})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 1 {
+				due = append(due, booking)
+			}
+		}
+		return appendBookingEvents(tx, domain.EventBookingReminderDue, "", due...)
+	})
+	if err != nil {
+		return nil, translateError(err, "booking reminder")
+	}
+	return due, nil
+}
@@ -1,13 +1,43 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// statusEvents maps a booking status change to the event it publishes.
+var statusEvents = map[string]string{
+	domain.BookingStatusPaid:      domain.EventBookingPaid,
+	domain.BookingStatusCancelled: domain.EventBookingCancelled,
+	domain.BookingStatusExpired:   domain.EventBookingExpired,
+}
+
+// statusTimes maps a booking status to the column recording when it was
+// reached.
+var statusTimes = map[string]string{
+	domain.BookingStatusPaid:      "paid_at",
+	domain.BookingStatusCancelled: "cancelled_at",
+	domain.BookingStatusExpired:   "expired_at",
+	domain.BookingStatusNoShow:    "no_show_at",
+}
+
+// statusUpdates sets a booking's status and the time it was reached.
+func statusUpdates(status string) map[string]interface{} {
+	updates := map[string]interface{}{"status": status}
+	if column, ok := statusTimes[status]; ok {
+		updates[column] = gorm.Expr("now()")
+	}
+	return updates
+}
+
 type BookingRepositoryDB struct {
 	db *gorm.DB
 }
@@ -16,37 +46,259 @@ func NewBookingRepository(db *gorm.DB) port.BookingRepository {
 	return &BookingRepositoryDB{db: db}
 }
 
-func (r *BookingRepositoryDB) Create(booking *domain.Booking) error {
-	return r.db.Create(booking).Error
+// Create also consumes the customer's own holds on the slot, completing any
+// waitlist offer they came from. A booking created paid, at the counter,
+// publishes booking.paid after booking.created.
+func (r *BookingRepositoryDB) Create(ctx context.Context, booking *domain.Booking) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockField(tx, booking.FieldID); err != nil {
+			return err
+		}
+		if err := checkFieldLive(tx, booking.FieldID); err != nil {
+			return err
+		}
+		if guest := booking.User; guest != nil && guest.ID == 0 && guest.Role == domain.RoleGuest {
+			if err := saveGuest(tx, guest); err != nil {
+				return err
+			}
+			booking.UserID = guest.ID
+		}
+		var held int64
+		if err := othersHolds(tx, booking.FieldID, booking.UserID, booking.StartTime, booking.EndTime).Count(&held).Error; err != nil {
+			return err
+		}
+		if held > 0 {
+			return errSlotHeld
+		}
+		if err := tx.Omit(clause.Associations).Create(booking).Error; err != nil {
+			return err
+		}
+		if err := consumeHolds(tx, booking); err != nil {
+			return err
+		}
+		if err := appendBookingEvents(tx, domain.EventBookingCreated, "", *booking); err != nil {
+			return err
+		}
+		if booking.Status == domain.BookingStatusPaid {
+			return appendBookingEvents(tx, domain.EventBookingPaid, "", *booking)
+		}
+		return nil
+	})
+	if errors.Is(err, errSlotHeld) {
+		return domain.NewConflictError("field is held for another customer at this time")
+	}
+	if errors.Is(err, errFieldDeleted) {
+		return domain.NewNotFoundError("field not found")
+	}
+	err = translateError(err, "booking")
+	if errors.Is(err, domain.ErrConflict) {
+		// The bookings_no_overlap constraint caught a race that slipped past
+		// CheckAvailability.
+		logging.FromContext(ctx).Warn("booking overlap rejected by constraint",
+			"field_id", booking.FieldID, "start_time", booking.StartTime, "end_time", booking.EndTime)
+		return domain.NewConflictError("field is already booked at this time")
+	}
+	return err
 }
 
-func (r *BookingRepositoryDB) CheckAvailability(fieldID uint, start, end time.Time) (bool, error) {
-	var count int64
-	err := r.db.Model(&domain.Booking{}).
-		Where("field_id = ?", fieldID).
-		Where("status != ?", "cancelled").
-		Where("start_time < ? AND end_time > ?", end, start).
-		Count(&count).Error
-
+func (r *BookingRepositoryDB) CheckAvailability(ctx context.Context, fieldID, userID uint, start, end time.Time) (bool, error) {
+	taken, err := slotTaken(r.db.WithContext(ctx), fieldID, userID, start, end)
 	if err != nil {
-		return false, err
+		return false, translateError(err, "booking")
 	}
+	return taken, nil
+}
 
-	return count > 0, nil
+func (r *BookingRepositoryDB) ListBusy(ctx context.Context, fieldID uint, from, to time.Time) ([]domain.BusySlot, error) {
+	db := r.db.WithContext(ctx)
+	var bookings, holds []domain.TimeRange
+	if err := activeBookings(db, fieldID, from, to).Select("start_time", "end_time").Find(&bookings).Error; err != nil {
+		return nil, translateError(err, "booking")
+	}
+	if err := unexpiredHolds(db, fieldID, from, to).Select("start_time", "end_time").Find(&holds).Error; err != nil {
+		return nil, translateError(err, "hold")
+	}
+
+	busy := make([]domain.BusySlot, 0, len(bookings)+len(holds))
+	for _, b := range bookings {
+		busy = append(busy, domain.BusySlot{TimeRange: b, Kind: domain.BusyBooking})
+	}
+	for _, h := range holds {
+		busy = append(busy, domain.BusySlot{TimeRange: h, Kind: domain.BusyHold})
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].StartTime.Before(busy[j].StartTime) })
+	return busy, nil
+}
+
+var (
+	errSlotHeld     = errors.New("slot held for another customer")
+	errFieldDeleted = errors.New("field deleted")
+)
+
+// consumeHolds releases the holds booking's customer had on its slot and
+// marks the waitlist entries they were offered through as booked. A hold
+// reaching past the booking frees the rest of its time, which is announced
+// with a hold.released event.
+func consumeHolds(tx *gorm.DB, booking *domain.Booking) error {
+	var holds []domain.SlotHold
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("field_id = ? AND user_id = ?", booking.FieldID, booking.UserID).
+		Where("start_time < ? AND end_time > ?", booking.EndTime, booking.StartTime).
+		Find(&holds).Error
+	if err != nil || len(holds) == 0 {
+		return err
+	}
+	ids := make([]uint, len(holds))
+	var wider []domain.SlotHold
+	for i, hold := range holds {
+		ids[i] = hold.ID
+		if hold.StartTime.Before(booking.StartTime) || hold.EndTime.After(booking.EndTime) {
+			wider = append(wider, hold)
+		}
+	}
+	err = tx.Model(&domain.WaitlistEntry{}).
+		Where("status = ? AND hold_id IN ?", domain.WaitlistStatusOffered, ids).
+		Updates(map[string]interface{}{"status": domain.WaitlistStatusBooked, "hold_expires_at": nil}).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Delete(&domain.SlotHold{}, ids).Error; err != nil {
+		return err
+	}
+	return appendHoldEvents(tx, wider...)
 }
 
-func (r *BookingRepositoryDB) GetAll() ([]domain.Booking, error) {
+func (r *BookingRepositoryDB) GetAll(ctx context.Context, filter port.BookingFilter) ([]domain.Booking, error) {
 	var bookings []domain.Booking
-	err := r.db.Preload("User").Preload("Field").Order("created_at desc").Find(&bookings).Error
-	return bookings, err
+	err := filterBookings(r.db.WithContext(ctx).Model(&domain.Booking{}), filter).
+		Preload("User").Preload("Field").Order("bookings.created_at desc").Find(&bookings).Error
+	return bookings, translateError(err, "booking")
+}
+
+// filterBookings joins the bookings' fields, deleted or not, and narrows
+// them to filter. Dates are compared in each field's time zone; the UTC
+// bounds only let the start time index narrow the scan.
+func filterBookings(q *gorm.DB, filter port.BookingFilter) *gorm.DB {
+	q = q.Joins("JOIN fields ON fields.id = bookings.field_id")
+	if filter.Status != "" {
+		q = q.Where("bookings.status = ?", filter.Status)
+	}
+	if filter.FieldID != 0 {
+		q = q.Where("bookings.field_id = ?", filter.FieldID)
+	}
+	if filter.UserID != 0 {
+		q = q.Where("bookings.user_id = ?", filter.UserID)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("bookings.start_time >= ?", filter.From.Add(-zoneAhead)).
+			Where("(bookings.start_time AT TIME ZONE fields.time_zone)::date >= ?", filter.From.Format("2006-01-02"))
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("bookings.start_time < ?", filter.To.AddDate(0, 0, 1).Add(zoneBehind)).
+			Where("(bookings.start_time AT TIME ZONE fields.time_zone)::date <= ?", filter.To.Format("2006-01-02"))
+	}
+	return q
 }
 
-func (r *BookingRepositoryDB) GetByID(id uint) (*domain.Booking, error) {
+func (r *BookingRepositoryDB) GetByID(ctx context.Context, id uint) (*domain.Booking, error) {
 	var booking domain.Booking
-	err := r.db.Preload("User").Preload("Field").First(&booking, id).Error
-	return &booking, err
+	err := r.db.WithContext(ctx).Preload("User").Preload("Field").First(&booking, id).Error
+	if err != nil {
+		return nil, translateError(err, "booking")
+	}
+	return &booking, nil
 }
 
-func (r *BookingRepositoryDB) UpdateStatus(id uint, status string) error {
-	return r.db.Model(&domain.Booking{}).Where("id = ?", id).Update("status", status).Error
+func (r *BookingRepositoryDB) UpdateStatus(ctx context.Context, id uint, from, to string) error {
+	return r.changeStatus(ctx, id, from, to, nil)
+}
+
+func (r *BookingRepositoryDB) MarkPaid(ctx context.Context, id uint, reference string) error {
+	return r.changeStatus(ctx, id, domain.BookingStatusPending, domain.BookingStatusPaid, func(tx *gorm.DB, booking *domain.Booking, updates map[string]interface{}) error {
+		var field domain.Field
+		if err := tx.Unscoped().Select("price_per_hour").First(&field, booking.FieldID).Error; err != nil {
+			return err
+		}
+		updates["amount_paid"] = field.Price(booking.StartTime, booking.EndTime)
+		updates["payment_reference"] = reference
+		return nil
+	})
+}
+
+// changeStatus moves a booking from status from to status under a row lock,
+// with any other updates set adds, and records the event the change
+// publishes. The status is checked under the lock, so a booking expired or
+// cancelled since the caller looked is not changed.
+func (r *BookingRepositoryDB) changeStatus(ctx context.Context, id uint, from, status string,
+	set func(tx *gorm.DB, booking *domain.Booking, updates map[string]interface{}) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var booking domain.Booking
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&booking, id).Error; err != nil {
+			return err
+		}
+		switch booking.Status {
+		case status:
+			return nil
+		case from:
+		default:
+			return errStatusChanged{booking.Status}
+		}
+		updates := statusUpdates(status)
+		if set != nil {
+			if err := set(tx, &booking, updates); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&booking).Updates(updates).Error; err != nil {
+			return err
+		}
+		booking.Status = status
+		if eventType, ok := statusEvents[status]; ok {
+			return appendBookingEvents(tx, eventType, "", booking)
+		}
+		return nil
+	})
+	var changed errStatusChanged
+	if errors.As(err, &changed) {
+		return domain.NewConflictError("booking is now " + changed.status)
+	}
+	return translateError(err, "booking")
+}
+
+// errStatusChanged reports the status a booking had when changeStatus
+// found it in an unexpected one.
+type errStatusChanged struct{ status string }
+
+func (e errStatusChanged) Error() string { return "booking is " + e.status }
+
+// ExpirePending skips rows locked by another instance, so concurrent
+// expirers never expire (and announce) the same booking twice.
+func (r *BookingRepositoryDB) ExpirePending(ctx context.Context, cutoff time.Time, limit int) ([]domain.Booking, error) {
+	var expired []domain.Booking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND created_at < ?", domain.BookingStatusPending, cutoff).
+			Order("created_at").Limit(limit).
+			Find(&expired).Error
+		if err != nil || len(expired) == 0 {
+			return err
+		}
+		ids := make([]uint, len(expired))
+		for i := range expired {
+			ids[i] = expired[i].ID
+			expired[i].Status = domain.BookingStatusExpired
+		}
+		// Only ever expire bookings still pending, whatever else the rows
+		// were locked for.
+		err = tx.Model(&domain.Booking{}).Where("id IN ? AND status = ?", ids, domain.BookingStatusPending).
+			Updates(statusUpdates(domain.BookingStatusExpired)).Error
+		if err != nil {
+			return err
+		}
+		return appendBookingEvents(tx, domain.EventBookingExpired, "", expired...)
+	})
+	if err != nil {
+		return nil, translateError(err, "booking")
+	}
+	return expired, nil
 }
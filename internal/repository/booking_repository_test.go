@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBookingRepository_NoShowKeepsItsSlot(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewBookingRepository(db)
+	start := time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC)
+
+	_, _ = repo.CheckAvailability(context.Background(), 1, 7, start, start.Add(time.Hour))
+	_, _ = repo.ListBusy(context.Background(), 1, start, start.Add(24*time.Hour))
+	queries := 0
+	for _, sql := range *statements {
+		if !strings.Contains(sql, `FROM "bookings"`) {
+			continue
+		}
+		queries++
+		if !strings.Contains(sql, "status IN ('pending','paid','no_show')") {
+			t.Fatalf("a no-show must still take its slot:\n%s", sql)
+		}
+	}
+	if queries < 2 {
+		t.Fatalf("expected the availability and busy queries, got %q", *statements)
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) port.CalendarRepository {
+	return &CalendarRepositoryDB{db: db}
+}
+
+// ownedBy narrows q to owner's rows.
+func ownedBy(q *gorm.DB, owner port.CalendarFeedOwner) *gorm.DB {
+	if owner.FieldID != 0 {
+		return q.Where("field_id = ?", owner.FieldID)
+	}
+	return q.Where("user_id = ?", owner.UserID)
+}
+
+// SaveFeed upserts on the owner's unique index, so concurrent calls leave
+// one feed, with the last token saved.
+func (r *CalendarRepositoryDB) SaveFeed(ctx context.Context, owner port.CalendarFeedOwner, tokenHash string) error {
+	feed, column := &domain.CalendarFeed{TokenHash: tokenHash}, "user_id"
+	if owner.FieldID != 0 {
+		feed.FieldID, column = &owner.FieldID, "field_id"
+	} else {
+		feed.UserID = &owner.UserID
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: column}},
+		// The partial index's predicate, verbatim, for Postgres to infer it.
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: column + " IS NOT NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"token_hash", "created_at"}),
+	}).Create(feed).Error
+	return translateError(err, "calendar feed")
+}
+
+func (r *CalendarRepositoryDB) DeleteFeed(ctx context.Context, owner port.CalendarFeedOwner) error {
+	err := ownedBy(r.db.WithContext(ctx), owner).Delete(&domain.CalendarFeed{}).Error
+	return translateError(err, "calendar feed")
+}
+
+func (r *CalendarRepositoryDB) GetFeedByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	if err := r.db.WithContext(ctx).First(&feed, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, translateError(err, "calendar feed")
+	}
+	return &feed, nil
+}
+
+// ListBookings loads only the customers' names, never their passwords.
+func (r *CalendarRepositoryDB) ListBookings(ctx context.Context, owner port.CalendarFeedOwner, since time.Time) ([]domain.Booking, error) {
+	var bookings []domain.Booking
+	err := ownedBy(r.db.WithContext(ctx), owner).
+		Preload("Field", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("start_time >= ?", since).
+		Order("start_time, id").
+		Find(&bookings).Error
+	return bookings, translateError(err, "booking")
+}
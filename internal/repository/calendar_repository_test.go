@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+func TestCalendarRepository_SaveFeedUpserts(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewCalendarRepository(db)
+
+	for _, tc := range []struct {
+		owner  port.CalendarFeedOwner
+		column string
+	}{
+		{port.CalendarFeedOwner{UserID: 7}, "user_id"},
+		{port.CalendarFeedOwner{FieldID: 3}, "field_id"},
+	} {
+		*statements = nil
+		if err := repo.SaveFeed(context.Background(), tc.owner, "hash"); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if len(*statements) != 1 {
+			t.Fatalf("expected a single statement, got %q", *statements)
+		}
+		sql := (*statements)[0]
+		for _, want := range []string{
+			`INSERT INTO "calendar_feeds"`,
+			`ON CONFLICT ("` + tc.column + `")`,
+			`WHERE ` + tc.column + ` IS NOT NULL DO UPDATE SET "token_hash"="excluded"."token_hash"`,
+		} {
+			if !strings.Contains(sql, want) {
+				t.Fatalf("expected %q in:\n%s", want, sql)
+			}
+		}
+	}
+}
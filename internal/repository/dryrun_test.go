@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB returns a database that runs no statements, and the SQL of each
+// statement it was asked to run, with its arguments inlined.
+func dryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunPool{}}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	var statements []string
+	record := func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().After("gorm:create").Register("test:record", record),
+		cb.Query().After("gorm:query").Register("test:record", record),
+		cb.Update().After("gorm:update").Register("test:record", record),
+		cb.Delete().After("gorm:delete").Register("test:record", record),
+		cb.Row().After("gorm:row").Register("test:record", record),
+		cb.Raw().After("gorm:raw").Register("test:record", record),
+	} {
+		if err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	}
+	return db, &statements
+}
+
+// dryRunPool lets dry runs open transactions. Dry runs never send a
+// statement, so the pool has no connection behind it.
+type dryRunPool struct{ gorm.ConnPool }
+
+func (p dryRunPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &dryRunTx{p}, nil
+}
+
+type dryRunTx struct{ dryRunPool }
+
+func (*dryRunTx) Commit() error   { return nil }
+func (*dryRunTx) Rollback() error { return nil }
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgExclusionViolation  = "23P01"
+)
+
+// translateError converts gorm and Postgres errors into domain errors so the
+// raw driver message never reaches the client. entity names the resource in
+// the resulting message, e.g. "field not found".
+func translateError(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.NewNotFoundError(entity + " not found")
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return domain.NewConflictError(entity + " already exists")
+		case pgExclusionViolation:
+			return domain.NewConflictError(entity + " overlaps an existing " + entity)
+		case pgForeignKeyViolation:
+			return domain.NewValidationError(entity + " references a record that does not exist")
+		case pgCheckViolation:
+			return domain.NewValidationError(entity + " violates a data constraint")
+		}
+	}
+
+	return err
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type FieldRepositoryDB struct {
@@ -14,29 +18,103 @@ func NewFieldRepository(db *gorm.DB) port.FieldRepository {
 	return &FieldRepositoryDB{db: db}
 }
 
-func (r *FieldRepositoryDB) Create(field *domain.Field) error {
-	return r.db.Create(field).Error
+func (r *FieldRepositoryDB) Create(ctx context.Context, field *domain.Field) error {
+	return translateError(r.db.WithContext(ctx).Create(field).Error, "field")
 }
 
-func (r *FieldRepositoryDB) GetAll() ([]domain.Field, error) {
+func (r *FieldRepositoryDB) GetAll(ctx context.Context) ([]domain.Field, error) {
 	var fields []domain.Field
-	err := r.db.Find(&fields).Error
-	return fields, err
+	err := r.db.WithContext(ctx).Find(&fields).Error
+	return fields, translateError(err, "field")
 }
 
-func (r *FieldRepositoryDB) GetByID(id uint) (*domain.Field, error) {
+func (r *FieldRepositoryDB) GetByID(ctx context.Context, id uint) (*domain.Field, error) {
 	var field domain.Field
-	err := r.db.First(&field, id).Error
+	err := r.db.WithContext(ctx).First(&field, id).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "field")
 	}
 	return &field, nil
 }
 
-func (r *FieldRepositoryDB) Update(field *domain.Field) error {
-	return r.db.Save(field).Error
+func (r *FieldRepositoryDB) GetByIDWithDeleted(ctx context.Context, id uint) (*domain.Field, error) {
+	var field domain.Field
+	err := r.db.WithContext(ctx).Unscoped().First(&field, id).Error
+	if err != nil {
+		return nil, translateError(err, "field")
+	}
+	return &field, nil
+}
+
+func (r *FieldRepositoryDB) Update(ctx context.Context, field *domain.Field) error {
+	return translateError(r.db.WithContext(ctx).Save(field).Error, "field")
+}
+
+// upcomingBookings scopes a booking query to active bookings of a field that
+// have not finished yet.
+func upcomingBookings(db *gorm.DB, fieldID uint, after time.Time) *gorm.DB {
+	return db.Model(&domain.Booking{}).
+		Where("field_id = ?", fieldID).
+		Where("status IN ?", []string{domain.BookingStatusPending, domain.BookingStatusPaid}).
+		Where("end_time > ?", after)
 }
 
-func (r *FieldRepositoryDB) Delete(id uint) error {
-	return r.db.Delete(&domain.Field{}, id).Error
+// DeleteIfUnbooked counts and deletes under the field's lock, so a booking
+// made meanwhile is either counted or refused once the field is gone.
+func (r *FieldRepositoryDB) DeleteIfUnbooked(ctx context.Context, id uint, after time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockField(tx, id); err != nil {
+			return err
+		}
+		if err := upcomingBookings(tx, id, after).Count(&count).Error; err != nil || count > 0 {
+			return err
+		}
+		return deleteField(tx, id)
+	})
+	return count, translateError(err, "field")
+}
+
+// DeleteAndCancelBookings cancels every upcoming booking of the field and
+// soft-deletes it in a single transaction, returning the cancelled bookings.
+func (r *FieldRepositoryDB) DeleteAndCancelBookings(ctx context.Context, id uint, after time.Time) ([]domain.Booking, error) {
+	var cancelled []domain.Booking
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockField(tx, id); err != nil {
+			return err
+		}
+		if err := upcomingBookings(tx, id, after).Clauses(clause.Locking{Strength: "UPDATE"}).Find(&cancelled).Error; err != nil {
+			return err
+		}
+		if len(cancelled) > 0 {
+			ids := make([]uint, len(cancelled))
+			for i := range cancelled {
+				ids[i] = cancelled[i].ID
+				cancelled[i].Status = domain.BookingStatusCancelled
+			}
+			if err := tx.Model(&domain.Booking{}).Where("id IN ?", ids).Updates(statusUpdates(domain.BookingStatusCancelled)).Error; err != nil {
+				return err
+			}
+			if err := appendBookingEvents(tx, domain.EventBookingCancelled, domain.CancelReasonFieldRemoved, cancelled...); err != nil {
+				return err
+			}
+		}
+		return deleteField(tx, id)
+	})
+	if err != nil {
+		return nil, translateError(err, "field")
+	}
+	return cancelled, nil
+}
+
+// deleteField soft-deletes the field and closes its waitlist on tx.
+func deleteField(tx *gorm.DB, id uint) error {
+	result := tx.Delete(&domain.Field{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return closeWaitlist(tx, id)
 }
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldRepository_DeletesUnderTheFieldLock(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewFieldRepository(db)
+	now := time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC)
+
+	for name, remove := range map[string]func() error{
+		"block": func() error {
+			_, err := repo.DeleteIfUnbooked(context.Background(), 3, now)
+			return err
+		},
+		"cascade": func() error {
+			_, err := repo.DeleteAndCancelBookings(context.Background(), 3, now)
+			return err
+		},
+	} {
+		*statements = nil
+		_ = remove()
+		if len(*statements) < 3 {
+			t.Fatalf("%s: expected the lock, the bookings and the delete, got %q", name, *statements)
+		}
+		first, second := (*statements)[0], (*statements)[1]
+		if !strings.Contains(first, "pg_advisory_xact_lock") || !strings.Contains(second, `FROM "bookings"`) {
+			t.Fatalf("%s: expected the bookings read under the field's lock, got %q", name, *statements)
+		}
+		deleted := false
+		for _, sql := range (*statements)[2:] {
+			deleted = deleted || strings.HasPrefix(sql, `UPDATE "fields" SET "deleted_at"=`)
+		}
+		if !deleted {
+			t.Fatalf("%s: expected the field deleted in the same transaction, got %q", name, *statements)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type HoldRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) port.HoldRepository {
+	return &HoldRepositoryDB{db: db}
+}
+
+// Create checks and inserts under the same field lock as bookings, so a
+// hold and a booking racing for a slot never both succeed.
+func (r *HoldRepositoryDB) Create(ctx context.Context, hold *domain.SlotHold, maxActive int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockField(tx, hold.FieldID); err != nil {
+			return err
+		}
+		taken, err := slotTaken(tx, hold.FieldID, hold.UserID, hold.StartTime, hold.EndTime)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return domain.NewConflictError("field is already booked or held at this time")
+		}
+
+		// Holding a slot again, say for longer, replaces the earlier hold.
+		var replaced []domain.SlotHold
+		err = checkoutHolds(tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("field_id = ? AND user_id = ?", hold.FieldID, hold.UserID).
+			Where("start_time < ? AND end_time > ?", hold.EndTime, hold.StartTime)).
+			Find(&replaced).Error
+		if err != nil {
+			return err
+		}
+		if err := deleteHolds(tx, replaced); err != nil {
+			return err
+		}
+		var active int64
+		err = checkoutHolds(tx.Model(&domain.SlotHold{}).Where("user_id = ? AND expires_at > now()", hold.UserID)).
+			Count(&active).Error
+		if err != nil {
+			return err
+		}
+		if active >= int64(maxActive) {
+			return domain.NewConflictError(fmt.Sprintf("you already hold %d slots; book or release one first", active))
+		}
+		return tx.Create(hold).Error
+	})
+	return translateError(err, "hold")
+}
+
+func (r *HoldRepositoryDB) GetByID(ctx context.Context, id uint) (*domain.SlotHold, error) {
+	var hold domain.SlotHold
+	if err := r.db.WithContext(ctx).First(&hold, id).Error; err != nil {
+		return nil, translateError(err, "hold")
+	}
+	return &hold, nil
+}
+
+func (r *HoldRepositoryDB) ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]domain.SlotHold, error) {
+	var holds []domain.SlotHold
+	err := r.db.WithContext(ctx).Where("user_id = ? AND expires_at > ?", userID, now).
+		Order("expires_at").Find(&holds).Error
+	return holds, translateError(err, "hold")
+}
+
+func (r *HoldRepositoryDB) Release(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var hold domain.SlotHold
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&hold, id).Error; err != nil {
+			return err
+		}
+		var offers []domain.WaitlistEntry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("hold_id = ? AND status = ?", id, domain.WaitlistStatusOffered).
+			Find(&offers).Error
+		if err != nil {
+			return err
+		}
+		if len(offers) > 0 {
+			err := tx.Model(&domain.WaitlistEntry{}).Where("hold_id = ?", id).
+				Updates(map[string]interface{}{"status": domain.WaitlistStatusCancelled, "hold_id": nil, "hold_expires_at": nil}).Error
+			if err != nil {
+				return err
+			}
+			if err := appendWaitlistEvents(tx, domain.EventWaitlistReleased, offers...); err != nil {
+				return err
+			}
+			// The offer's release passes the slot on.
+			return tx.Delete(&hold).Error
+		}
+		return deleteHolds(tx, []domain.SlotHold{hold})
+	})
+	return translateError(err, "hold")
+}
+
+// DeleteExpired skips holds locked by a booking or release in progress;
+// they are picked up on a later run.
+func (r *HoldRepositoryDB) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	var expired []domain.SlotHold
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := checkoutHolds(tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("expires_at <= ?", now)).
+			Order("expires_at").Limit(limit).
+			Find(&expired).Error
+		if err != nil {
+			return err
+		}
+		return deleteHolds(tx, expired)
+	})
+	if err != nil {
+		return 0, translateError(err, "hold")
+	}
+	return int64(len(expired)), nil
+}
+
+// deleteHolds removes checkout holds and writes a hold.released event for
+// each on tx, so waiters on their slots are offered them.
+func deleteHolds(tx *gorm.DB, holds []domain.SlotHold) error {
+	if len(holds) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(holds))
+	for i := range holds {
+		ids[i] = holds[i].ID
+	}
+	if err := tx.Delete(&domain.SlotHold{}, ids).Error; err != nil {
+		return err
+	}
+	return appendHoldEvents(tx, holds...)
+}
+
+// appendHoldEvents writes a hold.released event per hold on tx.
+func appendHoldEvents(tx *gorm.DB, holds ...domain.SlotHold) error {
+	if len(holds) == 0 {
+		return nil
+	}
+	now := time.Now()
+	events := make([]domain.OutboxEvent, len(holds))
+	for i := range holds {
+		event, err := domain.NewHoldEvent(domain.EventHoldReleased, &holds[i], now)
+		if err != nil {
+			return err
+		}
+		events[i] = event
+	}
+	return tx.Create(&events).Error
+}
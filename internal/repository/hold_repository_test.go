@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHoldRepository_DeleteExpiredClaimsCheckoutHolds(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewHoldRepository(db)
+	now := time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC)
+
+	n, err := repo.DeleteExpired(context.Background(), now, 50)
+	if err != nil || n != 0 {
+		t.Fatalf("expected nothing to delete, got %d (%v)", n, err)
+	}
+	// The expired holds are read before they are deleted, so each can be
+	// announced with a hold.released event.
+	if len(*statements) != 1 {
+		t.Fatalf("expected only the claim, got %q", *statements)
+	}
+	claim := (*statements)[0]
+	for _, want := range []string{
+		`SELECT * FROM "slot_holds"`,
+		"NOT EXISTS (SELECT 1 FROM waitlist_entries w WHERE w.hold_id = slot_holds.id)",
+		"expires_at <= '2025-06-02 03:00:00'",
+		"LIMIT 50 FOR UPDATE SKIP LOCKED",
+	} {
+		if !strings.Contains(claim, want) {
+			t.Fatalf("expected the claim to contain %q:\n%s", want, claim)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importBatchSize keeps each insert well under Postgres' 65535 parameters.
+const importBatchSize = 500
+
+type ImportRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) port.ImportRepository {
+	return &ImportRepositoryDB{db: db}
+}
+
+// Import locks the existing fields it books, in ID order so two imports
+// cannot deadlock, and rechecks under the locks, as Create does, that they
+// have not been deleted or held; the bookings_no_overlap constraint catches
+// bookings made since validation.
+func (r *ImportRepositoryDB) Import(ctx context.Context, fields []domain.Field, bookings []domain.Booking) error {
+	var held *domain.Booking
+	var deleted uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked []uint
+		seen := map[uint]bool{}
+		for _, b := range bookings {
+			if b.Field == nil && !seen[b.FieldID] {
+				seen[b.FieldID] = true
+				locked = append(locked, b.FieldID)
+			}
+		}
+		sort.Slice(locked, func(i, j int) bool { return locked[i] < locked[j] })
+		for _, id := range locked {
+			if err := lockField(tx, id); err != nil {
+				return err
+			}
+			if err := checkFieldLive(tx, id); err != nil {
+				deleted = id
+				return err
+			}
+		}
+
+		if len(fields) > 0 {
+			if err := tx.CreateInBatches(&fields, importBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		for i := range bookings {
+			b := &bookings[i]
+			if b.Field != nil {
+				b.FieldID = b.Field.ID
+				continue
+			}
+			if b.Status == domain.BookingStatusCancelled {
+				continue
+			}
+			var count int64
+			if err := othersHolds(tx, b.FieldID, b.UserID, b.StartTime, b.EndTime).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				held = b
+				return errSlotHeld
+			}
+		}
+		if len(bookings) > 0 {
+			return tx.Omit(clause.Associations).CreateInBatches(&bookings, importBatchSize).Error
+		}
+		return nil
+	})
+	if errors.Is(err, errSlotHeld) {
+		return domain.NewConflictError(fmt.Sprintf("field %d is now held for a customer at %s; nothing was imported",
+			held.FieldID, held.StartTime.Format("2006-01-02 15:04 MST")))
+	}
+	if errors.Is(err, errFieldDeleted) {
+		for i := range bookings {
+			if b := &bookings[i]; b.Field == nil && b.FieldID == deleted {
+				return domain.NewNotFoundError(fmt.Sprintf("field %d, booked at %s, was deleted since the import was checked; nothing was imported",
+					deleted, b.StartTime.Format("2006-01-02 15:04 MST")))
+			}
+		}
+	}
+	err = translateError(err, "booking")
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.NewConflictError("a booking was made since the import was checked and now overlaps it; nothing was imported")
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+func TestImportRepository_RefusesAFieldDeletedSinceValidation(t *testing.T) {
+	// A dry run finds no rows, so field 3 looks deleted once locked.
+	db, statements := dryRunDB(t)
+	repo := NewImportRepository(db)
+	start := time.Date(2025, 5, 20, 11, 0, 0, 0, time.UTC)
+
+	err := repo.Import(context.Background(), nil, []domain.Booking{
+		{FieldID: 3, UserID: 7, StartTime: start, EndTime: start.Add(time.Hour), Status: domain.BookingStatusPaid},
+	})
+	if !errors.Is(err, domain.ErrNotFound) || !strings.Contains(err.Error(), "field 3") {
+		t.Fatalf("expected field 3 not found, got %v", err)
+	}
+	if len(*statements) != 2 || !strings.Contains((*statements)[0], "pg_advisory_xact_lock") ||
+		!strings.Contains((*statements)[1], `FROM "fields" WHERE id = 3`) {
+		t.Fatalf("expected the field checked under its lock and nothing inserted, got %q", *statements)
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) port.NotificationPreferenceRepository {
+	return &NotificationPreferenceRepositoryDB{db: db}
+}
+
+func (r *NotificationPreferenceRepositoryDB) Get(ctx context.Context, userID uint) (*domain.NotificationPreference, error) {
+	var pref domain.NotificationPreference
+	err := r.db.WithContext(ctx).First(&pref, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, translateError(err, "notification preference")
+	}
+	return &pref, nil
+}
+
+func (r *NotificationPreferenceRepositoryDB) Save(ctx context.Context, pref *domain.NotificationPreference) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"language", "opt_out_non_transactional", "updated_at"}),
+	}).Create(pref).Error
+	return translateError(err, "notification preference")
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) port.OutboxRepository {
+	return &OutboxRepositoryDB{db: db}
+}
+
+// appendBookingEvents writes one event per booking on tx, so the events
+// commit or roll back together with the booking change.
+func appendBookingEvents(tx *gorm.DB, eventType, reason string, bookings ...domain.Booking) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	now := time.Now()
+	events := make([]domain.OutboxEvent, len(bookings))
+	for i := range bookings {
+		event, err := domain.NewBookingEvent(eventType, &bookings[i], reason, now)
+		if err != nil {
+			return err
+		}
+		events[i] = event
+	}
+	return tx.Create(&events).Error
+}
+
+func (r *OutboxRepositoryDB) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	// Postgres keeps microseconds; truncating keeps the lease token returned
+	// here equal to the stored one.
+	leaseUntil = leaseUntil.Truncate(time.Microsecond)
+
+	var events []domain.OutboxEvent
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE outbox_events SET locked_until = ?
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until <= ?)
+			ORDER BY next_attempt_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		leaseUntil, domain.OutboxStatusPending, now, now, limit,
+	).Scan(&events).Error
+	if err != nil || len(events) == 0 {
+		return events, translateError(err, "outbox event")
+	}
+
+	ids := make([]uint, len(events))
+	byID := make(map[uint]*domain.OutboxEvent, len(events))
+	for i := range events {
+		ids[i] = events[i].ID
+		byID[events[i].ID] = &events[i]
+	}
+	var deliveries []domain.OutboxDelivery
+	if err := r.db.WithContext(ctx).Where("event_id IN ?", ids).Find(&deliveries).Error; err != nil {
+		return nil, translateError(err, "outbox event")
+	}
+	for _, delivery := range deliveries {
+		event := byID[delivery.EventID]
+		event.DeliveredTo = append(event.DeliveredTo, delivery.Subscriber)
+	}
+	return events, nil
+}
+
+// MarkSubscriberDelivered ignores a delivery already recorded, by an
+// instance that took the event over meanwhile.
+func (r *OutboxRepositoryDB) MarkSubscriberDelivered(ctx context.Context, event *domain.OutboxEvent, subscriber string, at time.Time) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.OutboxDelivery{EventID: event.ID, Subscriber: subscriber, DeliveredAt: at}).Error
+	return translateError(err, "outbox event")
+}
+
+func (r *OutboxRepositoryDB) MarkDelivered(ctx context.Context, event *domain.OutboxEvent, at time.Time) error {
+	return r.release(ctx, event, map[string]interface{}{
+		"status":       domain.OutboxStatusDelivered,
+		"attempts":     event.Attempts,
+		"delivered_at": at,
+		"last_error":   "",
+	})
+}
+
+func (r *OutboxRepositoryDB) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	return r.release(ctx, event, map[string]interface{}{
+		"status":          event.Status,
+		"attempts":        event.Attempts,
+		"next_attempt_at": event.NextAttemptAt,
+		"last_error":      event.LastError,
+	})
+}
+
+// release applies updates and drops the lease, provided the lease recorded
+// on event is still the current one. A dispatcher whose lease expired while
+// a handler was slow must not overwrite the outcome of the instance that
+// took the event over.
+func (r *OutboxRepositoryDB) release(ctx context.Context, event *domain.OutboxEvent, updates map[string]interface{}) error {
+	updates["locked_until"] = nil
+	result := r.db.WithContext(ctx).Model(&domain.OutboxEvent{}).
+		Where("id = ? AND locked_until = ?", event.ID, event.LockedUntil).
+		Updates(updates)
+	if result.Error != nil {
+		return translateError(result.Error, "outbox event")
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewConflictError("outbox event lease was lost")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+func TestOutboxRepository_MarkSubscriberDeliveredOnce(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewOutboxRepository(db)
+	at := time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC)
+
+	if err := repo.MarkSubscriberDelivered(context.Background(), &domain.OutboxEvent{ID: 9}, "notifications", at); err != nil {
+		t.Fatalf("mark: %v", err)
+	}
+	if len(*statements) != 1 {
+		t.Fatalf("expected one statement, got %q", *statements)
+	}
+	// A delivery recorded by an instance that took the event over is kept.
+	want := `INSERT INTO "outbox_deliveries" ("event_id","subscriber","delivered_at") ` +
+		`VALUES (9,'notifications','2025-06-02 03:00:00') ON CONFLICT DO NOTHING`
+	if got := (*statements)[0]; !strings.HasPrefix(got, want) {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+)
+
+// Report SQL fragments over bookings b joined to their field f.
+const (
+	// localStart is when a booking starts on its field's wall clock.
+	localStart = "(b.start_time AT TIME ZONE f.time_zone)"
+	// bookedHours and bookedRevenue total the bookings' length and the
+	// amount they were paid.
+	bookedHours   = "COALESCE(SUM(EXTRACT(EPOCH FROM b.end_time - b.start_time)) / 3600, 0)::float8"
+	bookedRevenue = "COALESCE(SUM(COALESCE(b.amount_paid, " + bookingPrice + ")), 0)::bigint"
+	// bookingPrice is what a booking costs at its field's current hourly
+	// price, charged per whole minute as booking emails show it.
+	bookingPrice = "f.price_per_hour * (EXTRACT(EPOCH FROM b.end_time - b.start_time)::bigint / 60) / 60"
+)
+
+// confirmedStatuses are the bookings reports count as taking place.
+var confirmedStatuses = []string{domain.BookingStatusPaid, domain.BookingStatusNoShow}
+
+// Time zones are at most 14 hours either side of UTC, so a booking
+// starting on a local day starts within these margins of that UTC day.
+const (
+	zoneAhead  = 14 * time.Hour
+	zoneBehind = 12 * time.Hour
+)
+
+type ReportRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) port.ReportRepository {
+	return &ReportRepositoryDB{db: db}
+}
+
+// inRange selects the bookings starting in rng. The UTC bounds only let
+// the start time index narrow the scan; the local date decides.
+func (r *ReportRepositoryDB) inRange(ctx context.Context, rng port.ReportRange) *gorm.DB {
+	q := r.db.WithContext(ctx).Table("bookings b").
+		Joins("JOIN fields f ON f.id = b.field_id").
+		Where("b.deleted_at IS NULL").
+		Where("b.start_time >= ? AND b.start_time < ?", rng.From.Add(-zoneAhead), rng.To.AddDate(0, 0, 1).Add(zoneBehind)).
+		Where(localStart+"::date BETWEEN ? AND ?", rng.From.Format("2006-01-02"), rng.To.Format("2006-01-02"))
+	if rng.FieldID != 0 {
+		q = q.Where("b.field_id = ?", rng.FieldID)
+	}
+	return q
+}
+
+func (r *ReportRepositoryDB) Revenue(ctx context.Context, rng port.ReportRange, period, group string) ([]domain.RevenueRow, error) {
+	columns := "to_char(date_trunc(?, " + localStart + "), 'YYYY-MM-DD') AS period_start"
+	groupBy := "period_start"
+	switch group {
+	case domain.ReportGroupField:
+		columns += ", f.id AS field_id, f.name AS field_name"
+		groupBy += ", f.id, f.name"
+	case domain.ReportGroupVenue:
+		columns += ", f.location AS venue"
+		groupBy += ", f.location"
+	}
+	columns += ", COUNT(*) AS bookings, " + bookedHours + " AS hours, " + bookedRevenue + " AS revenue"
+
+	var rows []domain.RevenueRow
+	err := r.inRange(ctx, rng).
+		Select(columns, period).
+		Where("b.status IN ?", confirmedStatuses).
+		Group(groupBy).
+		Order(groupBy).
+		Scan(&rows).Error
+	return rows, translateError(err, "report")
+}
+
+func (r *ReportRepositoryDB) BookedHours(ctx context.Context, rng port.ReportRange) ([]port.FieldHours, error) {
+	var rows []port.FieldHours
+	err := r.inRange(ctx, rng).
+		Select("b.field_id, "+bookedHours+" AS hours").
+		Where("b.status IN ?", confirmedStatuses).
+		Group("b.field_id").
+		Scan(&rows).Error
+	return rows, translateError(err, "report")
+}
+
+func (r *ReportRepositoryDB) Heatmap(ctx context.Context, rng port.ReportRange) ([]domain.HeatmapCell, error) {
+	var rows []domain.HeatmapCell
+	err := r.inRange(ctx, rng).
+		Select("EXTRACT(ISODOW FROM "+localStart+")::int AS weekday, EXTRACT(HOUR FROM "+localStart+")::int AS hour, COUNT(*) AS bookings").
+		Where("b.status IN ?", confirmedStatuses).
+		Group("1, 2").
+		Order("1, 2").
+		Scan(&rows).Error
+	return rows, translateError(err, "report")
+}
+
+func (r *ReportRepositoryDB) Outcomes(ctx context.Context, rng port.ReportRange) ([]domain.CancellationRow, error) {
+	var rows []domain.CancellationRow
+	err := r.inRange(ctx, rng).
+		Select(`f.id AS field_id, f.name AS field_name, COUNT(*) AS bookings,
+			COUNT(*) FILTER (WHERE b.status = ?) AS paid,
+			COUNT(*) FILTER (WHERE b.status = ?) AS cancelled,
+			COUNT(*) FILTER (WHERE b.status = ?) AS expired,
+			COUNT(*) FILTER (WHERE b.status = ?) AS no_shows`,
+			domain.BookingStatusPaid, domain.BookingStatusCancelled, domain.BookingStatusExpired, domain.BookingStatusNoShow).
+		Group("f.id, f.name").
+		Order("f.name, f.id").
+		Scan(&rows).Error
+	return rows, translateError(err, "report")
+}
+
+func (r *ReportRepositoryDB) TopCustomers(ctx context.Context, rng port.ReportRange, limit int) ([]domain.CustomerRow, error) {
+	var rows []domain.CustomerRow
+	err := r.inRange(ctx, rng).
+		Joins("JOIN users u ON u.id = b.user_id").
+		Select("u.id AS user_id, u.name, COALESCE(u.email, '') AS email, COUNT(*) AS bookings, "+bookedHours+" AS hours, "+bookedRevenue+" AS revenue").
+		Where("b.status IN ?", confirmedStatuses).
+		Group("u.id, u.name, u.email").
+		Order("revenue DESC, bookings DESC, u.id").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, translateError(err, "report")
+}
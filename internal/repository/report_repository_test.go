@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+func TestReportRepository_RevenueGroupsByPeriodAndGroup(t *testing.T) {
+	groups := map[string]string{
+		domain.ReportGroupAll:   `GROUP BY "period_start" ORDER BY`,
+		domain.ReportGroupField: "GROUP BY period_start, f.id, f.name ORDER BY",
+		domain.ReportGroupVenue: "GROUP BY period_start, f.location ORDER BY",
+	}
+	rng := port.ReportRange{From: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)}
+	for _, period := range []string{domain.ReportPeriodDay, domain.ReportPeriodWeek, domain.ReportPeriodMonth} {
+		for group, want := range groups {
+			db, statements := dryRunDB(t)
+			_, _ = NewReportRepository(db).Revenue(context.Background(), rng, period, group)
+			if len(*statements) != 1 {
+				t.Fatalf("%s/%s: expected one statement, got %q", period, group, *statements)
+			}
+			sql := (*statements)[0]
+			if !strings.Contains(sql, "date_trunc('"+period+"', ") || !strings.Contains(sql, want) {
+				t.Fatalf("%s/%s: expected %q in\n%s", period, group, want, sql)
+			}
+		}
+	}
+}
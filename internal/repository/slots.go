@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+// slotLockClass is the first key of the advisory locks taken by lockField,
+// keeping them apart from other uses of two-key advisory locks.
+const slotLockClass = 1
+
+// lockField serialises bookings and holds on one field until tx ends, so a
+// conflict check and the insert that follows it cannot interleave with
+// another transaction's. The bookings_no_overlap constraint only covers
+// bookings; holds rely on this lock.
+func lockField(tx *gorm.DB, fieldID uint) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", slotLockClass, int32(fieldID)).Error
+}
+
+// checkFieldLive returns errFieldDeleted if fieldID has been deleted.
+// Deleting a field takes its lock, so under lockField a field deleted while
+// the caller waited for the lock is seen.
+func checkFieldLive(tx *gorm.DB, fieldID uint) error {
+	var fields int64
+	if err := tx.Model(&domain.Field{}).Where("id = ?", fieldID).Count(&fields).Error; err != nil {
+		return err
+	}
+	if fields == 0 {
+		return errFieldDeleted
+	}
+	return nil
+}
+
+// occupyingStatuses are the statuses of bookings that occupy their slot, as
+// in the bookings_no_overlap constraint. A no-show's slot stays paid for.
+var occupyingStatuses = []string{domain.BookingStatusPending, domain.BookingStatusPaid, domain.BookingStatusNoShow}
+
+// activeBookings are the bookings on fieldID overlapping start-end that
+// still occupy their slot.
+func activeBookings(tx *gorm.DB, fieldID uint, start, end time.Time) *gorm.DB {
+	return tx.Model(&domain.Booking{}).
+		Where("field_id = ?", fieldID).
+		Where("status IN ?", occupyingStatuses).
+		Where("start_time < ? AND end_time > ?", end, start)
+}
+
+// unexpiredHolds are the holds on fieldID overlapping start-end that have
+// not run out yet.
+func unexpiredHolds(tx *gorm.DB, fieldID uint, start, end time.Time) *gorm.DB {
+	return tx.Model(&domain.SlotHold{}).
+		Where("field_id = ?", fieldID).
+		Where("expires_at > now()").
+		Where("start_time < ? AND end_time > ?", end, start)
+}
+
+// othersHolds are the unexpired holds on fieldID overlapping start-end that
+// belong to anyone but userID.
+func othersHolds(tx *gorm.DB, fieldID, userID uint, start, end time.Time) *gorm.DB {
+	return unexpiredHolds(tx, fieldID, start, end).Where("user_id <> ?", userID)
+}
+
+// checkoutHolds excludes the holds of waitlist offers, which come and go
+// with their entries.
+func checkoutHolds(tx *gorm.DB) *gorm.DB {
+	return tx.Where("NOT EXISTS (SELECT 1 FROM waitlist_entries w WHERE w.hold_id = slot_holds.id)")
+}
+
+// slotTaken reports whether start-end on fieldID is booked, or held for
+// someone other than userID.
+func slotTaken(tx *gorm.DB, fieldID, userID uint, start, end time.Time) (bool, error) {
+	var taken bool
+	err := tx.Raw("SELECT EXISTS (?) OR EXISTS (?)",
+		activeBookings(tx.Session(&gorm.Session{NewDB: true}), fieldID, start, end).Select("1"),
+		othersHolds(tx.Session(&gorm.Session{NewDB: true}), fieldID, userID, start, end).Select("1"),
+	).Scan(&taken).Error
+	return taken, err
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"context"
+	"errors"
+
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepositoryDB struct {
@@ -14,16 +18,50 @@ func NewUserRepository(db *gorm.DB) port.UserRepository {
 	return &UserRepositoryDB{db: db}
 }
 
-func (r *UserRepositoryDB) CreateUser(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepositoryDB) CreateUser(ctx context.Context, user *domain.User) error {
+	err := r.db.WithContext(ctx).Create(user).Error
+	if err != nil {
+		err = translateError(err, "user")
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.NewConflictError("email is already registered")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *UserRepositoryDB) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	var user domain.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, translateError(err, "user")
+	}
+	return &user, nil
 }
 
-func (r *UserRepositoryDB) GetByEmail(email string) (*domain.User, error) {
+func (r *UserRepositoryDB) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
 
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err, "user")
 	}
 	return &user, nil
 }
+
+// saveGuest inserts guest, or loads the guest already recorded with the
+// same phone number, keeping the name on file. Guests are stored without an
+// email or password.
+func saveGuest(tx *gorm.DB, guest *domain.User) error {
+	guest.Role = domain.RoleGuest
+	return tx.Omit("Email", "Password").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "phone"}},
+		// The partial uni_users_guest_phone index's predicate, verbatim: a
+		// bound parameter would keep Postgres from inferring the index.
+		TargetWhere: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "role = 'guest' AND deleted_at IS NULL"},
+		}},
+		// A no-op update, so the existing row is returned.
+		DoUpdates: clause.Assignments(map[string]interface{}{"phone": gorm.Expr("EXCLUDED.phone")}),
+	}, clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "name"}, {Name: "created_at"}, {Name: "updated_at"}}}).
+		Create(guest).Error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+func TestSaveGuest_KeepsTheNameOnFile(t *testing.T) {
+	db, statements := dryRunDB(t)
+	if err := saveGuest(db, &domain.User{Name: "Andi S.", Phone: "+6281234567890"}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if len(*statements) != 1 {
+		t.Fatalf("expected one statement, got %q", *statements)
+	}
+	sql := (*statements)[0]
+	for _, want := range []string{
+		`ON CONFLICT ("phone")`,
+		`WHERE role = 'guest' AND deleted_at IS NULL DO UPDATE SET "phone"=EXCLUDED.phone RETURNING "id","name"`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("expected %q in:\n%s", want, sql)
+		}
+	}
+	if strings.Contains(sql, `"name"=`) {
+		t.Fatalf("a known guest's name must not be overwritten:\n%s", sql)
+	}
+}
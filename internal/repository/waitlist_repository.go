@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var activeWaitlistStatuses = []string{domain.WaitlistStatusWaiting, domain.WaitlistStatusOffered}
+
+type WaitlistRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) port.WaitlistRepository {
+	return &WaitlistRepositoryDB{db: db}
+}
+
+func (r *WaitlistRepositoryDB) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
+	return translateError(r.db.WithContext(ctx).Create(entry).Error, "waitlist entry")
+}
+
+func (r *WaitlistRepositoryDB) GetByID(ctx context.Context, id uint) (*domain.WaitlistEntry, error) {
+	var entry domain.WaitlistEntry
+	if err := r.db.WithContext(ctx).First(&entry, id).Error; err != nil {
+		return nil, translateError(err, "waitlist entry")
+	}
+	return &entry, nil
+}
+
+func (r *WaitlistRepositoryDB) ListByUser(ctx context.Context, userID uint) ([]domain.WaitlistEntry, error) {
+	var entries []domain.WaitlistEntry
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&entries).Error
+	return entries, translateError(err, "waitlist entry")
+}
+
+func (r *WaitlistRepositoryDB) Cancel(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entry domain.WaitlistEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entry, id).Error; err != nil {
+			return err
+		}
+		if entry.Status != domain.WaitlistStatusWaiting && entry.Status != domain.WaitlistStatusOffered {
+			return nil
+		}
+		err := tx.Model(&domain.WaitlistEntry{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"status": domain.WaitlistStatusCancelled, "hold_id": nil, "hold_expires_at": nil}).Error
+		if err != nil || entry.Status != domain.WaitlistStatusOffered {
+			return err
+		}
+		if entry.HoldID != nil {
+			if err := tx.Delete(&domain.SlotHold{}, *entry.HoldID).Error; err != nil {
+				return err
+			}
+		}
+		return appendWaitlistEvents(tx, domain.EventWaitlistReleased, entry)
+	})
+	return translateError(err, "waitlist entry")
+}
+
+// OfferFreed holds the field lock while it checks each slot, so a booking
+// racing for the same slot either lands first and the entry keeps waiting,
+// or finds the slot held.
+func (r *WaitlistRepositoryDB) OfferFreed(ctx context.Context, fieldID uint, start, end, now, holdUntil time.Time) ([]domain.WaitlistEntry, error) {
+	holdUntil = holdUntil.Truncate(time.Microsecond)
+	var offered []domain.WaitlistEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		offered = nil
+		if err := lockField(tx, fieldID); err != nil {
+			return err
+		}
+		var field domain.Field
+		if err := tx.Select("id").First(&field, fieldID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// A removed field has nothing left to offer.
+				return nil
+			}
+			return err
+		}
+
+		var waiting []domain.WaitlistEntry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("field_id = ? AND status = ?", fieldID, domain.WaitlistStatusWaiting).
+			Where("start_time < ? AND end_time > ? AND start_time > ?", end, start, now).
+			Order("created_at, id").
+			Find(&waiting).Error
+		if err != nil {
+			return err
+		}
+		for _, entry := range waiting {
+			taken, err := slotTaken(tx, fieldID, entry.UserID, entry.StartTime, entry.EndTime)
+			if err != nil {
+				return err
+			}
+			if taken {
+				continue
+			}
+			hold := domain.SlotHold{FieldID: fieldID, UserID: entry.UserID, StartTime: entry.StartTime, EndTime: entry.EndTime, ExpiresAt: holdUntil}
+			if err := tx.Create(&hold).Error; err != nil {
+				return err
+			}
+			entry.Status, entry.HoldID, entry.HoldExpiresAt = domain.WaitlistStatusOffered, &hold.ID, &holdUntil
+			err = tx.Model(&entry).Select("status", "hold_id", "hold_expires_at", "updated_at").Updates(&entry).Error
+			if err != nil {
+				return err
+			}
+			if err := appendWaitlistEvents(tx, domain.EventWaitlistOffered, entry); err != nil {
+				return err
+			}
+			offered = append(offered, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err, "waitlist entry")
+	}
+	return offered, nil
+}
+
+// ExpireOffers skips entries locked by another instance, so each lapsed
+// offer is returned, and passed on, once.
+func (r *WaitlistRepositoryDB) ExpireOffers(ctx context.Context, now time.Time, limit int) ([]domain.WaitlistEntry, error) {
+	var lapsed []domain.WaitlistEntry
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND hold_expires_at <= ?", domain.WaitlistStatusOffered, now).
+			Order("hold_expires_at").Limit(limit).
+			Find(&lapsed).Error
+		if err != nil || len(lapsed) == 0 {
+			return err
+		}
+		ids := make([]uint, len(lapsed))
+		var holdIDs []uint
+		for i := range lapsed {
+			ids[i] = lapsed[i].ID
+			if lapsed[i].HoldID != nil {
+				holdIDs = append(holdIDs, *lapsed[i].HoldID)
+			}
+			lapsed[i].Status, lapsed[i].HoldID, lapsed[i].HoldExpiresAt = domain.WaitlistStatusLapsed, nil, nil
+		}
+		err = tx.Model(&domain.WaitlistEntry{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": domain.WaitlistStatusLapsed, "hold_id": nil, "hold_expires_at": nil}).Error
+		if err != nil {
+			return err
+		}
+		if len(holdIDs) > 0 {
+			if err := tx.Delete(&domain.SlotHold{}, holdIDs).Error; err != nil {
+				return err
+			}
+		}
+		return appendWaitlistEvents(tx, domain.EventWaitlistReleased, lapsed...)
+	})
+	if err != nil {
+		return nil, translateError(err, "waitlist entry")
+	}
+	return lapsed, nil
+}
+
+// appendWaitlistEvents writes one event per entry on tx.
+func appendWaitlistEvents(tx *gorm.DB, eventType string, entries ...domain.WaitlistEntry) error {
+	now := time.Now()
+	for i := range entries {
+		event, err := domain.NewWaitlistEvent(eventType, &entries[i], now)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeWaitlist cancels the open waitlist entries and holds of a field being
+// removed.
+func closeWaitlist(tx *gorm.DB, fieldID uint) error {
+	err := tx.Model(&domain.WaitlistEntry{}).
+		Where("field_id = ? AND status IN ?", fieldID, activeWaitlistStatuses).
+		Updates(map[string]interface{}{"status": domain.WaitlistStatusCancelled, "hold_id": nil, "hold_expires_at": nil}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("field_id = ?", fieldID).Delete(&domain.SlotHold{}).Error
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookDeliveryRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) port.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryDB{db: db}
+}
+
+func (r *WebhookDeliveryRepositoryDB) Enqueue(ctx context.Context, event *domain.OutboxEvent, body []byte) (int, error) {
+	eventTypes, err := json.Marshal([]string{event.EventType})
+	if err != nil {
+		return 0, err
+	}
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at)
+		SELECT id, ?, ?, ?, ?, now()
+		FROM webhook_subscriptions
+		WHERE active AND event_types @> ?::jsonb
+		ON CONFLICT ON CONSTRAINT webhook_deliveries_event_unique DO NOTHING`,
+		event.ID, event.EventType, string(body), domain.WebhookDeliveryPending, string(eventTypes),
+	)
+	return int(result.RowsAffected), translateError(result.Error, "webhook delivery")
+}
+
+func (r *WebhookDeliveryRepositoryDB) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	// Postgres keeps microseconds; truncating keeps the lease token returned
+	// here equal to the stored one.
+	leaseUntil = leaseUntil.Truncate(time.Microsecond)
+
+	var deliveries []domain.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`
+			UPDATE webhook_deliveries SET locked_until = ?
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN webhook_subscriptions s ON s.id = d.subscription_id
+				WHERE d.status = ? AND s.active AND d.next_attempt_at <= ?
+					AND (d.locked_until IS NULL OR d.locked_until <= ?)
+				ORDER BY d.next_attempt_at, d.id
+				LIMIT ?
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING *`,
+			leaseUntil, domain.WebhookDeliveryPending, now, now, limit,
+		).Scan(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.SubscriptionID)
+		}
+		var subs []domain.WebhookSubscription
+		if err := tx.Where("id IN ?", ids).Find(&subs).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*domain.WebhookSubscription, len(subs))
+		for i := range subs {
+			byID[subs[i].ID] = &subs[i]
+		}
+		for i := range deliveries {
+			deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+		}
+		return nil
+	})
+	return deliveries, translateError(err, "webhook delivery")
+}
+
+func (r *WebhookDeliveryRepositoryDB) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt, disableAfter int) (bool, error) {
+	disabled := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only the holder of the current lease may record an outcome; a
+		// sender whose lease ran out while the receiver was slow must not
+		// overwrite the instance that took the delivery over.
+		result := tx.Model(&domain.WebhookDelivery{}).
+			Where("id = ? AND locked_until = ?", delivery.ID, delivery.LockedUntil).
+			Updates(map[string]interface{}{
+				"status":          delivery.Status,
+				"attempts":        delivery.Attempts,
+				"next_attempt_at": delivery.NextAttemptAt,
+				"response_status": delivery.ResponseStatus,
+				"last_error":      delivery.LastError,
+				"delivered_at":    delivery.DeliveredAt,
+				"locked_until":    nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.NewConflictError("webhook delivery lease was lost")
+		}
+
+		attempt.DeliveryID = delivery.ID
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+
+		var sub domain.WebhookSubscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sub, delivery.SubscriptionID).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"consecutive_failures": 0}
+		if !attempt.Succeeded() {
+			sub.ConsecutiveFailures++
+			updates["consecutive_failures"] = sub.ConsecutiveFailures
+			if sub.Active && sub.ConsecutiveFailures >= disableAfter {
+				updates["active"] = false
+				updates["disabled_at"] = time.Now()
+				updates["disabled_reason"] = fmt.Sprintf("disabled after %d consecutive failed deliveries", sub.ConsecutiveFailures)
+				disabled = true
+			}
+		}
+		return tx.Model(&sub).Updates(updates).Error
+	})
+	return disabled, translateError(err, "webhook delivery")
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositoryDB struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) port.WebhookRepository {
+	return &WebhookRepositoryDB{db: db}
+}
+
+func (r *WebhookRepositoryDB) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	return translateError(r.db.WithContext(ctx).Create(sub).Error, "webhook")
+}
+
+func (r *WebhookRepositoryDB) GetAll(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	var subs []domain.WebhookSubscription
+	err := r.db.WithContext(ctx).Order("id").Find(&subs).Error
+	return subs, translateError(err, "webhook")
+}
+
+func (r *WebhookRepositoryDB) GetByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&sub, id).Error; err != nil {
+		return nil, translateError(err, "webhook")
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepositoryDB) Update(ctx context.Context, sub *domain.WebhookSubscription, columns ...string) error {
+	result := r.db.WithContext(ctx).Model(sub).Select(append(columns, "updated_at")).Updates(sub)
+	if result.Error != nil {
+		return translateError(result.Error, "webhook")
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("webhook not found")
+	}
+	return nil
+}
+
+func (r *WebhookRepositoryDB) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return translateError(result.Error, "webhook")
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFoundError("webhook not found")
+	}
+	return nil
+}
+
+func (r *WebhookRepositoryDB) ListDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, translateError(err, "webhook delivery")
+}
+
+func (r *WebhookRepositoryDB) GetDelivery(ctx context.Context, subscriptionID, deliveryID uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("subscription_id = ?", subscriptionID).
+		First(&delivery, deliveryID).Error
+	if err != nil {
+		return nil, translateError(err, "webhook delivery")
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookRepositoryDB) Requeue(ctx context.Context, subscriptionID, deliveryID uint, at time.Time) (*domain.WebhookDelivery, error) {
+	result := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Where("id = ? AND subscription_id = ?", deliveryID, subscriptionID).
+		Updates(map[string]interface{}{
+			"status":          domain.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": at,
+			"locked_until":    nil,
+		})
+	if result.Error != nil {
+		return nil, translateError(result.Error, "webhook delivery")
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.NewNotFoundError("webhook delivery not found")
+	}
+	return r.GetDelivery(ctx, subscriptionID, deliveryID)
+}
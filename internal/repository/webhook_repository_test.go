@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+func TestWebhookRepository_UpdateSavesOnlyTheNamedColumns(t *testing.T) {
+	db, statements := dryRunDB(t)
+	repo := NewWebhookRepository(db)
+
+	sub := &domain.WebhookSubscription{ID: 4, URL: "https://b.example", EventTypes: []string{domain.EventBookingCreated}}
+	_ = repo.Update(context.Background(), sub, "url", "event_types", "description")
+	if len(*statements) != 1 {
+		t.Fatalf("expected one statement, got %q", *statements)
+	}
+	sql := (*statements)[0]
+	if !strings.Contains(sql, `SET "url"='https://b.example',"event_types"='["booking.created"]',"description"='',"updated_at"=`) ||
+		!strings.Contains(sql, `WHERE "id" = 4`) {
+		t.Fatalf("unexpected update:\n%s", sql)
+	}
+	for _, column := range []string{"active", "secret", "consecutive_failures", "disabled_at"} {
+		if strings.Contains(sql, `"`+column+`"`) {
+			t.Fatalf("%s must be left as it is:\n%s", column, sql)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+const expireBatchSize = 100
+
+// BookingExpirer releases the slots of pending bookings that were not paid
+// within the payment window.
+type BookingExpirer struct {
+	repo    port.BookingRepository
+	metrics port.BookingMetrics
+	window  time.Duration
+	clock   port.Clock
+}
+
+func NewBookingExpirer(repo port.BookingRepository, metrics port.BookingMetrics, window time.Duration, clock port.Clock) *BookingExpirer {
+	return &BookingExpirer{repo: repo, metrics: metrics, window: window, clock: clock}
+}
+
+// ExpireOnce expires every overdue pending booking and returns how many.
+func (e *BookingExpirer) ExpireOnce(ctx context.Context) (int, error) {
+	cutoff := e.clock.Now().Add(-e.window)
+	total := 0
+	for {
+		expired, err := e.repo.ExpirePending(ctx, cutoff, expireBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for i := range expired {
+			e.metrics.BookingExpired(ctx)
+			logging.FromContext(ctx).Info("booking expired", "booking_id", expired[i].ID, "field_id", expired[i].FieldID)
+		}
+		total += len(expired)
+		if len(expired) < expireBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Run expires overdue bookings every interval until ctx is cancelled.
+func (e *BookingExpirer) Run(ctx context.Context, interval time.Duration, beat func()) {
+	for {
+		beat()
+		if _, err := e.ExpireOnce(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("expiring bookings failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
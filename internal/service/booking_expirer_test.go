@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+func TestBookingExpirer_ExpireOnce(t *testing.T) {
+	repo := &mockBookingRepo{}
+	now := time.Now()
+	stale := &domain.Booking{Status: domain.BookingStatusPending}
+	stale.ID, stale.CreatedAt = 1, now.Add(-20*time.Minute)
+	fresh := &domain.Booking{Status: domain.BookingStatusPending}
+	fresh.ID, fresh.CreatedAt = 2, now.Add(-5*time.Minute)
+	paid := &domain.Booking{Status: domain.BookingStatusPaid}
+	paid.ID, paid.CreatedAt = 3, now.Add(-time.Hour)
+	repo.byID = map[uint]*domain.Booking{1: stale, 2: fresh, 3: paid}
+
+	metrics := &countingMetrics{}
+	expirer := NewBookingExpirer(repo, metrics, 15*time.Minute, fixedClock(now))
+
+	n, err := expirer.ExpireOnce(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 expired, got %d err=%v", n, err)
+	}
+	if stale.Status != domain.BookingStatusExpired || fresh.Status != domain.BookingStatusPending || paid.Status != domain.BookingStatusPaid {
+		t.Fatalf("unexpected statuses: %s %s %s", stale.Status, fresh.Status, paid.Status)
+	}
+	if metrics.expired != 1 {
+		t.Fatalf("expected 1 expired metric, got %d", metrics.expired)
+	}
+}
+
+func TestBookingExpirer_ExpiresEveryBatch(t *testing.T) {
+	now := time.Now()
+	repo := &mockBookingRepo{byID: map[uint]*domain.Booking{}}
+	for id := uint(1); id <= expireBatchSize*2+1; id++ {
+		b := &domain.Booking{Status: domain.BookingStatusPending}
+		b.ID, b.CreatedAt = id, now.Add(-time.Hour)
+		repo.byID[id] = b
+	}
+
+	metrics := &countingMetrics{}
+	expirer := NewBookingExpirer(repo, metrics, 15*time.Minute, fixedClock(now))
+	if n, err := expirer.ExpireOnce(context.Background()); err != nil || n != expireBatchSize*2+1 || metrics.expired != n {
+		t.Fatalf("expected every booking expired over three batches, got %d (%d counted), %v", n, metrics.expired, err)
+	}
+}
+
+type failingExpiryRepo struct{ mockBookingRepo }
+
+func (f *failingExpiryRepo) ExpirePending(ctx context.Context, cutoff time.Time, limit int) ([]domain.Booking, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestBookingExpirer_ReportsFailures(t *testing.T) {
+	expirer := NewBookingExpirer(&failingExpiryRepo{}, &countingMetrics{}, 15*time.Minute, fixedClock(testNow))
+	if n, err := expirer.ExpireOnce(context.Background()); err == nil || n != 0 {
+		t.Fatalf("expected the failure to be returned, got %d, %v", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	beats := 0
+	expirer.Run(ctx, time.Hour, func() {
+		beats++
+		cancel()
+	})
+	if beats != 1 {
+		t.Fatalf("expected one beat before stopping, got %d", beats)
+	}
+}
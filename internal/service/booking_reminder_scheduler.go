@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+const reminderBatchSize = 100
+
+// BookingReminderScheduler queues a reminder for paid bookings at each
+// offset before they start. The reminders themselves are sent by the outbox
+// subscriber for booking.reminder_due events.
+type BookingReminderScheduler struct {
+	repo port.BookingReminderRepository
+	// offsets are sorted longest first.
+	offsets []time.Duration
+	now     func() time.Time
+}
+
+func NewBookingReminderScheduler(repo port.BookingReminderRepository, offsets []time.Duration) *BookingReminderScheduler {
+	sorted := append([]time.Duration(nil), offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+	return &BookingReminderScheduler{repo: repo, offsets: sorted, now: time.Now}
+}
+
+// ScheduleOnce queues every reminder that is due and returns how many. A
+// booking is only given the reminder for the shortest offset it has already
+// reached, so one paid two hours before it starts is not also sent the
+// 24-hour reminder.
+func (s *BookingReminderScheduler) ScheduleOnce(ctx context.Context) (int, error) {
+	now := s.now()
+	total := 0
+	for i, offset := range s.offsets {
+		after := now
+		if i+1 < len(s.offsets) {
+			after = now.Add(s.offsets[i+1])
+		}
+		for {
+			due, err := s.repo.ScheduleDue(ctx, offset, after, now.Add(offset), reminderBatchSize)
+			if err != nil {
+				return total, err
+			}
+			for j := range due {
+				logging.FromContext(ctx).Info("booking reminder scheduled", "booking_id", due[j].ID, "offset", offset.String())
+			}
+			total += len(due)
+			if len(due) < reminderBatchSize {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+// Run schedules due reminders every interval until ctx is cancelled.
+func (s *BookingReminderScheduler) Run(ctx context.Context, interval time.Duration, beat func()) {
+	for {
+		beat()
+		if _, err := s.ScheduleOnce(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("scheduling booking reminders failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// memoryReminderRepo mimics the booking_reminders primary key: a reminder is
+// returned by at most one ScheduleDue call.
+type memoryReminderRepo struct {
+	mu        sync.Mutex
+	bookings  []*domain.Booking
+	scheduled map[uint][]time.Duration
+}
+
+func (m *memoryReminderRepo) ScheduleDue(ctx context.Context, offset time.Duration, after, until time.Time, limit int) ([]domain.Booking, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []domain.Booking
+	for _, b := range m.bookings {
+		if b.Status != domain.BookingStatusPaid || !b.StartTime.After(after) || b.StartTime.After(until) || m.has(b.ID, offset) {
+			continue
+		}
+		if len(due) == limit {
+			break
+		}
+		m.scheduled[b.ID] = append(m.scheduled[b.ID], offset)
+		due = append(due, *b)
+	}
+	return due, nil
+}
+
+func (m *memoryReminderRepo) has(id uint, offset time.Duration) bool {
+	for _, o := range m.scheduled[id] {
+		if o == offset {
+			return true
+		}
+	}
+	return false
+}
+
+func paidBookingAt(id uint, start time.Time, status string) *domain.Booking {
+	b := &domain.Booking{StartTime: start, EndTime: start.Add(time.Hour), Status: status}
+	b.ID = id
+	return b
+}
+
+func TestBookingReminderScheduler_SendsEachOffsetOnce(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	repo := &memoryReminderRepo{
+		scheduled: map[uint][]time.Duration{},
+		bookings: []*domain.Booking{
+			paidBookingAt(1, now.Add(23*time.Hour), domain.BookingStatusPaid),
+			paidBookingAt(2, now.Add(90*time.Minute), domain.BookingStatusPaid),
+			paidBookingAt(3, now.Add(40*time.Hour), domain.BookingStatusPaid),
+			paidBookingAt(4, now.Add(3*time.Hour), domain.BookingStatusCancelled),
+			paidBookingAt(5, now.Add(-time.Minute), domain.BookingStatusPaid),
+		},
+	}
+	clock := now
+	scheduler := NewBookingReminderScheduler(repo, []time.Duration{2 * time.Hour, 24 * time.Hour})
+	scheduler.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	n, err := scheduler.ScheduleOnce(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 reminders, got %d err=%v", n, err)
+	}
+	// booking 2 is already inside two hours, so it only gets that reminder
+	if got := repo.scheduled[1]; len(got) != 1 || got[0] != 24*time.Hour {
+		t.Fatalf("booking 1: %v", got)
+	}
+	if got := repo.scheduled[2]; len(got) != 1 || got[0] != 2*time.Hour {
+		t.Fatalf("booking 2: %v", got)
+	}
+
+	// a second instance at the same moment finds nothing left to do
+	other := NewBookingReminderScheduler(repo, []time.Duration{24 * time.Hour, 2 * time.Hour})
+	other.now = scheduler.now
+	if n, _ := other.ScheduleOnce(ctx); n != 0 {
+		t.Fatalf("expected no duplicates, got %d", n)
+	}
+
+	// 22 hours later booking 1 reaches its second reminder and booking 3 its first
+	clock = now.Add(22 * time.Hour)
+	if n, _ := scheduler.ScheduleOnce(ctx); n != 2 {
+		t.Fatalf("expected 2 reminders, got %d", n)
+	}
+	if got := repo.scheduled[1]; len(got) != 2 {
+		t.Fatalf("booking 1: %v", got)
+	}
+	if got := repo.scheduled[3]; len(got) != 1 || got[0] != 24*time.Hour {
+		t.Fatalf("booking 3: %v", got)
+	}
+	if len(repo.scheduled[4]) != 0 || len(repo.scheduled[5]) != 0 {
+		t.Fatalf("cancelled or started bookings must not be reminded: %v", repo.scheduled)
+	}
+}
+
+func TestBookingReminderScheduler_Batches(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	repo := &memoryReminderRepo{scheduled: map[uint][]time.Duration{}}
+	for i := 1; i <= reminderBatchSize+5; i++ {
+		repo.bookings = append(repo.bookings, paidBookingAt(uint(i), now.Add(time.Hour), domain.BookingStatusPaid))
+	}
+	scheduler := NewBookingReminderScheduler(repo, []time.Duration{2 * time.Hour})
+	scheduler.now = func() time.Time { return now }
+
+	if n, err := scheduler.ScheduleOnce(context.Background()); err != nil || n != reminderBatchSize+5 {
+		t.Fatalf("expected every booking reminded, got %d err=%v", n, err)
+	}
+}
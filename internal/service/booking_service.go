@@ -1,57 +1,248 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/HIUNCY/sagara-booking-api/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type BookingServiceImpl struct {
-	repo port.BookingRepository
+	repo      port.BookingRepository
+	fieldRepo port.FieldRepository
+	clock     port.Clock
 }
 
-func NewBookingService(repo port.BookingRepository) port.BookingService {
-	return &BookingServiceImpl{repo: repo}
+func NewBookingService(repo port.BookingRepository, fieldRepo port.FieldRepository, clock port.Clock) port.BookingService {
+	return &BookingServiceImpl{repo: repo, fieldRepo: fieldRepo, clock: clock}
 }
 
-func (s *BookingServiceImpl) CreateBooking(userID uint, req *port.BookingRequest) (*domain.Booking, error) {
-	if req.EndTime.Before(req.StartTime) {
-		return nil, errors.New("start time must be before end time")
+func (s *BookingServiceImpl) CreateBooking(ctx context.Context, userID uint, req *port.BookingRequest) (_ *domain.Booking, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookingService.CreateBooking", trace.WithAttributes(
+		attribute.Int("booking.user_id", int(userID)),
+		attribute.Int("booking.field_id", int(req.FieldID)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	start, end, err := checkSlot(ctx, s.fieldRepo, s.clock.Now(), req.FieldID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
 	}
 
-	isBooked, err := s.repo.CheckAvailability(req.FieldID, req.StartTime, req.EndTime)
+	isBooked, err := s.repo.CheckAvailability(ctx, req.FieldID, userID, start, end)
 	if err != nil {
 		return nil, err
 	}
 	if isBooked {
-		return nil, errors.New("field is already booked at this time")
+		return nil, domain.NewConflictError("field is already booked or held at this time")
 	}
 
+	span.AddEvent("slot available")
+
 	booking := &domain.Booking{
 		UserID:    userID,
 		FieldID:   req.FieldID,
-		StartTime: req.StartTime,
-		EndTime:   req.EndTime,
-		Status:    "pending",
+		StartTime: start,
+		EndTime:   end,
+		Status:    domain.BookingStatusPending,
 	}
 
-	err = s.repo.Create(booking)
+	err = s.repo.Create(ctx, booking)
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int("booking.id", int(booking.ID)))
+	logging.FromContext(ctx).Info("booking created",
+		"booking_id", booking.ID, "field_id", booking.FieldID, "user_id", userID)
 
 	return booking, nil
 }
 
-func (s *BookingServiceImpl) GetAllBookings() ([]domain.Booking, error) {
-	return s.repo.GetAll()
+// checkSlot validates a slot a customer asks for, to book, hold or wait
+// for, and returns it in UTC: clients may send any offset, but slots are
+// stored and compared in UTC.
+func checkSlot(ctx context.Context, fieldRepo port.FieldRepository, now time.Time, fieldID uint, start, end time.Time) (time.Time, time.Time, error) {
+	if fieldID == 0 {
+		return start, end, domain.NewValidationError("field_id is required")
+	}
+	start, end = start.UTC(), end.UTC()
+	if !end.After(start) {
+		return start, end, domain.NewValidationError("start time must be before end time")
+	}
+	if !start.After(now) {
+		return start, end, domain.NewValidationError("start time must be in the future")
+	}
+
+	// Deleted fields are excluded by the lookup, so this also rejects them.
+	field, err := fieldRepo.GetByID(ctx, fieldID)
+	if err != nil {
+		return start, end, err
+	}
+	return start, end, checkOpeningHours(field, start, end)
+}
+
+// checkOpeningHours requires the booking to fall within the field's opening
+// hours on a single local day, which also keeps it from crossing midnight.
+func checkOpeningHours(field *domain.Field, start, end time.Time) error {
+	open, close, err := field.OpeningHours(start)
+	if err != nil {
+		return fmt.Errorf("field %d has invalid opening hours: %w", field.ID, err)
+	}
+	if start.Before(open) || end.After(close) {
+		openTime, closeTime := field.Hours()
+		return domain.NewValidationError(fmt.Sprintf("the field is open %s-%s %s; a booking must fit within one day's opening hours",
+			openTime, closeTime, open.Location()))
+	}
+	return nil
+}
+
+func (s *BookingServiceImpl) GetAllBookings(ctx context.Context, q *port.BookingQuery) ([]domain.Booking, error) {
+	filter, err := bookingFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, filter)
+}
+
+func (s *BookingServiceImpl) ExportBookings(ctx context.Context, q *port.BookingQuery) (port.BookingRows, error) {
+	filter, err := bookingFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Export(ctx, filter)
+}
+
+// bookingStatuses are the statuses a booking can be filtered by.
+var bookingStatuses = map[string]bool{
+	domain.BookingStatusPending:   true,
+	domain.BookingStatusPaid:      true,
+	domain.BookingStatusCancelled: true,
+	domain.BookingStatusExpired:   true,
+	domain.BookingStatusNoShow:    true,
+}
+
+func bookingFilter(q *port.BookingQuery) (port.BookingFilter, error) {
+	filter := port.BookingFilter{Status: q.Status, FieldID: q.FieldID, UserID: q.UserID}
+	if q.Status != "" && !bookingStatuses[q.Status] {
+		return filter, domain.NewValidationError("status must be pending, paid, cancelled, expired or no_show")
+	}
+	var err error
+	if q.From != "" {
+		if filter.From, err = time.Parse("2006-01-02", q.From); err != nil {
+			return filter, domain.NewValidationError("from must be in YYYY-MM-DD form")
+		}
+	}
+	if q.To != "" {
+		if filter.To, err = time.Parse("2006-01-02", q.To); err != nil {
+			return filter, domain.NewValidationError("to must be in YYYY-MM-DD form")
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return filter, domain.NewValidationError("from must not be after to")
+	}
+	return filter, nil
+}
+
+func (s *BookingServiceImpl) GetBookingByID(ctx context.Context, id uint) (*domain.Booking, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *BookingServiceImpl) GetAvailability(ctx context.Context, fieldID uint, date string) (*domain.Availability, error) {
+	field, err := s.fieldRepo.GetByID(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := field.Zone()
+	if err != nil {
+		return nil, fmt.Errorf("field %d has an invalid time zone: %w", field.ID, err)
+	}
+	day, _ := domain.LocalDay(s.clock.Now(), loc)
+	if date != "" {
+		if day, err = time.ParseInLocation("2006-01-02", date, loc); err != nil {
+			return nil, domain.NewValidationError("date must be in YYYY-MM-DD form")
+		}
+	}
+	open, close, err := field.OpeningHours(day)
+	if err != nil {
+		return nil, fmt.Errorf("field %d has invalid opening hours: %w", field.ID, err)
+	}
+
+	busy, err := s.repo.ListBusy(ctx, fieldID, open, close)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Availability{
+		FieldID:  fieldID,
+		Date:     day.Format("2006-01-02"),
+		TimeZone: loc.String(),
+		OpensAt:  open.UTC(),
+		ClosesAt: close.UTC(),
+		Busy:     busy,
+		Free:     domain.FreeRanges(open.UTC(), close.UTC(), busy),
+	}, nil
 }
 
-func (s *BookingServiceImpl) GetBookingByID(id uint) (*domain.Booking, error) {
-	return s.repo.GetByID(id)
+// maxPaymentReference is the longest payment reference stored.
+const maxPaymentReference = 100
+
+func (s *BookingServiceImpl) PayBooking(ctx context.Context, bookingID uint, reference string) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "BookingService.PayBooking", trace.WithAttributes(
+		attribute.Int("booking.id", int(bookingID)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	reference = strings.TrimSpace(reference)
+	if len(reference) > maxPaymentReference {
+		return domain.NewValidationError(fmt.Sprintf("payment_reference must be at most %d characters", maxPaymentReference))
+	}
+
+	booking, err := s.repo.GetByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	switch booking.Status {
+	case domain.BookingStatusPaid, domain.BookingStatusNoShow:
+		// Paying twice is harmless and must not publish a second event.
+		return nil
+	case domain.BookingStatusCancelled, domain.BookingStatusExpired:
+		return domain.NewConflictError("booking is " + booking.Status + " and can no longer be paid")
+	}
+
+	if reference == "" {
+		// The mock gateway issues its own reference, as a real one would.
+		reference = fmt.Sprintf("PAY-%d-%s", bookingID, s.clock.Now().UTC().Format("20060102150405"))
+	}
+	if err := s.repo.MarkPaid(ctx, bookingID, reference); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("booking paid", "booking_id", bookingID, "payment_reference", reference)
+	return nil
 }
 
-func (s *BookingServiceImpl) PayBooking(bookingID uint) error {
-	return s.repo.UpdateStatus(bookingID, "paid")
+func (s *BookingServiceImpl) MarkNoShow(ctx context.Context, bookingID uint) error {
+	booking, err := s.repo.GetByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case booking.Status == domain.BookingStatusNoShow:
+		return nil
+	case booking.Status != domain.BookingStatusPaid:
+		return domain.NewConflictError("booking is " + booking.Status + "; only paid bookings can be marked as a no-show")
+	case booking.StartTime.After(s.clock.Now()):
+		return domain.NewConflictError("booking has not started yet")
+	}
+
+	if err := s.repo.UpdateStatus(ctx, bookingID, domain.BookingStatusPaid, domain.BookingStatusNoShow); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("booking marked as no-show", "booking_id", bookingID)
+	return nil
 }
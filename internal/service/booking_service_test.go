@@ -1,12 +1,19 @@
 package service
 
 import (
+    "context"
     "errors"
+    "strings"
     "testing"
     "time"
 
     "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
     "github.com/HIUNCY/sagara-booking-api/internal/core/port"
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type mockBookingRepo struct {
@@ -15,12 +22,31 @@ type mockBookingRepo struct {
     availErr error
     byID map[uint]*domain.Booking
     updateErr error
+    filter port.BookingFilter
+    // beforeUpdate runs as a status change starts, to change the booking
+    // behind the caller's back.
+    beforeUpdate func()
+    // guests are the guests saved with bookings, by phone number.
+    guests map[string]*domain.User
 }
 
-func (m *mockBookingRepo) Create(b *domain.Booking) error {
+func (m *mockBookingRepo) Create(ctx context.Context, b *domain.Booking) error {
     if m.created == nil {
         m.created = []*domain.Booking{}
     }
+    if guest := b.User; guest != nil && guest.ID == 0 && guest.Role == domain.RoleGuest {
+        if m.guests == nil {
+            m.guests = map[string]*domain.User{}
+        }
+        if saved, ok := m.guests[guest.Phone]; ok {
+            *guest = *saved
+        } else {
+            guest.ID = uint(100 + len(m.guests))
+            saved := *guest
+            m.guests[guest.Phone] = &saved
+        }
+        b.UserID = guest.ID
+    }
     b.ID = uint(len(m.created) + 1)
     m.created = append(m.created, b)
     if m.byID == nil {
@@ -30,7 +56,7 @@ func (m *mockBookingRepo) Create(b *domain.Booking) error {
     return nil
 }
 
-func (m *mockBookingRepo) CheckAvailability(fieldID uint, start, end time.Time) (bool, error) {
+func (m *mockBookingRepo) CheckAvailability(ctx context.Context, fieldID, userID uint, start, end time.Time) (bool, error) {
     if m.availErr != nil {
         return false, m.availErr
     }
@@ -42,25 +68,69 @@ func (m *mockBookingRepo) CheckAvailability(fieldID uint, start, end time.Time)
     return false, nil
 }
 
-func (m *mockBookingRepo) GetByID(id uint) (*domain.Booking, error) {
+func (m *mockBookingRepo) ListBusy(ctx context.Context, fieldID uint, from, to time.Time) ([]domain.BusySlot, error) {
+    var busy []domain.BusySlot
+    for _, b := range m.byID {
+        if b.FieldID == fieldID && b.StartTime.Before(to) && b.EndTime.After(from) &&
+            (b.Status == domain.BookingStatusPending || b.Status == domain.BookingStatusPaid || b.Status == domain.BookingStatusNoShow) {
+            busy = append(busy, domain.BusySlot{TimeRange: domain.TimeRange{StartTime: b.StartTime, EndTime: b.EndTime}, Kind: domain.BusyBooking})
+        }
+    }
+    return busy, nil
+}
+
+func (m *mockBookingRepo) GetByID(ctx context.Context, id uint) (*domain.Booking, error) {
     if b, ok := m.byID[id]; ok {
         return b, nil
     }
-    return nil, errors.New("not found")
+    return nil, domain.NewNotFoundError("not found")
 }
 
-func (m *mockBookingRepo) UpdateStatus(id uint, status string) error {
+func (m *mockBookingRepo) UpdateStatus(ctx context.Context, id uint, from, to string) error {
     if m.updateErr != nil {
         return m.updateErr
     }
-    if b, ok := m.byID[id]; ok {
-        b.Status = status
-        return nil
+    if m.beforeUpdate != nil {
+        m.beforeUpdate()
+    }
+    b, ok := m.byID[id]
+    if !ok {
+        return domain.NewNotFoundError("not found")
+    }
+    switch b.Status {
+    case to:
+    case from:
+        b.Status = to
+    default:
+        return domain.NewConflictError("booking is now " + b.Status)
+    }
+    return nil
+}
+
+func (m *mockBookingRepo) MarkPaid(ctx context.Context, id uint, reference string) error {
+    if err := m.UpdateStatus(ctx, id, domain.BookingStatusPending, domain.BookingStatusPaid); err != nil {
+        return err
+    }
+    m.byID[id].PaymentReference = reference
+    return nil
+}
+
+func (m *mockBookingRepo) ExpirePending(ctx context.Context, cutoff time.Time, limit int) ([]domain.Booking, error) {
+    var expired []domain.Booking
+    for _, b := range m.byID {
+        if len(expired) == limit {
+            break
+        }
+        if b.Status == domain.BookingStatusPending && b.CreatedAt.Before(cutoff) {
+            b.Status = domain.BookingStatusExpired
+            expired = append(expired, *b)
+        }
     }
-    return errors.New("not found")
+    return expired, nil
 }
 
-func (m *mockBookingRepo) GetAll() ([]domain.Booking, error) {
+func (m *mockBookingRepo) GetAll(ctx context.Context, filter port.BookingFilter) ([]domain.Booking, error) {
+    m.filter = filter
     res := make([]domain.Booking, 0, len(m.byID))
     for _, b := range m.byID {
         res = append(res, *b)
@@ -68,26 +138,61 @@ func (m *mockBookingRepo) GetAll() ([]domain.Booking, error) {
     return res, nil
 }
 
+func (m *mockBookingRepo) Export(ctx context.Context, filter port.BookingFilter) (port.BookingRows, error) {
+    m.filter = filter
+    return nil, nil
+}
+
+// testNow is a Monday morning in Jakarta.
+var testNow = time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC)
+
+func fixedClock(t time.Time) port.Clock {
+    return port.ClockFunc(func() time.Time { return t })
+}
+
+func newFieldRepoWith(ids ...uint) *mockFieldRepo {
+    repo := &mockFieldRepo{byID: map[uint]*domain.Field{}}
+    for _, id := range ids {
+        f := &domain.Field{Name: "F"}
+        f.ID = id
+        repo.byID[id] = f
+    }
+    return repo
+}
+
 func TestBookingService_CreateBooking_ValidationsAndSuccess(t *testing.T) {
     repo := &mockBookingRepo{avail: map[uint]bool{1: false}}
-    svc := NewBookingService(repo)
-    start := time.Now().Add(time.Hour)
+    svc := NewBookingService(repo, newFieldRepoWith(1), fixedClock(testNow))
+    start := testNow.Add(time.Hour)
     end := start.Add(time.Hour)
 
     // invalid time
-    if _, err := svc.CreateBooking(10, &port.BookingRequest{FieldID: 1, StartTime: end, EndTime: start}); err == nil {
+    if _, err := svc.CreateBooking(context.Background(), 10, &port.BookingRequest{FieldID: 1, StartTime: end, EndTime: start}); err == nil {
         t.Fatalf("expected error for end before start")
     }
 
+    // missing field id
+    if _, err := svc.CreateBooking(context.Background(), 10, &port.BookingRequest{StartTime: start, EndTime: end}); err == nil {
+        t.Fatalf("expected error for missing field id")
+    }
+
+    // unknown or deleted field
+    if _, err := svc.CreateBooking(context.Background(), 10, &port.BookingRequest{FieldID: 2, StartTime: start, EndTime: end}); err == nil {
+        t.Fatalf("expected error for unknown field")
+    }
+    if len(repo.created) != 0 {
+        t.Fatalf("no booking should be created for an unknown field")
+    }
+
     // overlap
     repo.avail[1] = true
-    if _, err := svc.CreateBooking(10, &port.BookingRequest{FieldID: 1, StartTime: start, EndTime: end}); err == nil {
+    if _, err := svc.CreateBooking(context.Background(), 10, &port.BookingRequest{FieldID: 1, StartTime: start, EndTime: end}); err == nil {
         t.Fatalf("expected overlap error")
     }
 
     // success
     repo.avail[1] = false
-    b, err := svc.CreateBooking(10, &port.BookingRequest{FieldID: 1, StartTime: start, EndTime: end});
+    b, err := svc.CreateBooking(context.Background(), 10, &port.BookingRequest{FieldID: 1, StartTime: start, EndTime: end});
     if err != nil || b == nil {
         t.Fatalf("expected booking created, got err=%v", err)
     }
@@ -98,23 +203,178 @@ func TestBookingService_CreateBooking_ValidationsAndSuccess(t *testing.T) {
 
 func TestBookingService_GetAndPay(t *testing.T) {
     repo := &mockBookingRepo{}
-    svc := NewBookingService(repo)
-    start := time.Now().Add(time.Hour)
+    svc := NewBookingService(repo, newFieldRepoWith(3), fixedClock(testNow))
+    start := testNow.Add(time.Hour)
     end := start.Add(time.Hour)
 
-    b, _ := svc.CreateBooking(2, &port.BookingRequest{FieldID: 3, StartTime: start, EndTime: end})
-    list, _ := svc.GetAllBookings()
+    b, _ := svc.CreateBooking(context.Background(), 2, &port.BookingRequest{FieldID: 3, StartTime: start, EndTime: end})
+    list, _ := svc.GetAllBookings(context.Background(), &port.BookingQuery{})
     if len(list) != 1 {
         t.Fatalf("expected 1 booking, got %d", len(list))
     }
-    got, _ := svc.GetBookingByID(b.ID)
+    got, _ := svc.GetBookingByID(context.Background(), b.ID)
     if got.ID != b.ID {
         t.Fatalf("expected same booking id")
     }
-    if err := svc.PayBooking(b.ID); err != nil {
+    if err := svc.PayBooking(context.Background(), b.ID, ""); err != nil {
         t.Fatalf("pay error: %v", err)
     }
-    if b.Status != "paid" {
-        t.Fatalf("expected status paid, got %s", b.Status)
+    if b.Status != "paid" || b.PaymentReference != "PAY-1-20250602030000" {
+        t.Fatalf("expected status paid with a generated reference, got %s / %q", b.Status, b.PaymentReference)
+    }
+
+    // paying again is a no-op
+    repo.updateErr = errors.New("should not be called")
+    if err := svc.PayBooking(context.Background(), b.ID, ""); err != nil {
+        t.Fatalf("second pay error: %v", err)
+    }
+    repo.updateErr = nil
+
+    // an expired booking can no longer be paid
+    b2, _ := svc.CreateBooking(context.Background(), 2, &port.BookingRequest{FieldID: 3, StartTime: start, EndTime: end})
+    b2.Status = domain.BookingStatusExpired
+    if err := svc.PayBooking(context.Background(), b2.ID, ""); !errors.Is(err, domain.ErrConflict) {
+        t.Fatalf("expected conflict for expired booking, got %v", err)
+    }
+    if err := svc.PayBooking(context.Background(), 99, ""); !errors.Is(err, domain.ErrNotFound) {
+        t.Fatalf("expected not found, got %v", err)
+    }
+}
+
+func TestBookingService_PayBookingExpiredMeanwhile(t *testing.T) {
+    repo := &mockBookingRepo{byID: map[uint]*domain.Booking{1: {Status: domain.BookingStatusPending}}}
+    svc := NewBookingService(repo, newFieldRepoWith(1), fixedClock(testNow))
+
+    // The expirer gets to the booking between the service's check and the
+    // payment.
+    repo.beforeUpdate = func() { repo.byID[1].Status = domain.BookingStatusExpired }
+    if err := svc.PayBooking(context.Background(), 1, "TRF-1"); !errors.Is(err, domain.ErrConflict) {
+        t.Fatalf("expected a conflict, got %v", err)
+    }
+    if b := repo.byID[1]; b.Status != domain.BookingStatusExpired || b.PaymentReference != "" {
+        t.Fatalf("an expired booking must stay unpaid, got %+v", b)
+    }
+}
+
+func TestBookingService_PaymentReference(t *testing.T) {
+    repo := &mockBookingRepo{byID: map[uint]*domain.Booking{1: {Status: domain.BookingStatusPending}}}
+    svc := NewBookingService(repo, newFieldRepoWith(1), fixedClock(testNow))
+
+    if err := svc.PayBooking(context.Background(), 1, strings.Repeat("x", 101)); !errors.Is(err, domain.ErrValidation) {
+        t.Fatalf("expected a validation error, got %v", err)
+    }
+    if err := svc.PayBooking(context.Background(), 1, "  TRF-123 "); err != nil || repo.byID[1].PaymentReference != "TRF-123" {
+        t.Fatalf("expected the given reference, got %q (%v)", repo.byID[1].PaymentReference, err)
+    }
+}
+
+func TestBookingService_BookingFilter(t *testing.T) {
+    repo := &mockBookingRepo{}
+    svc := NewBookingService(repo, newFieldRepoWith(1), fixedClock(testNow))
+    ctx := context.Background()
+
+    q := &port.BookingQuery{Status: "paid", FieldID: 1, UserID: 2, From: "2025-06-01", To: "2025-06-30"}
+    if _, err := svc.ExportBookings(ctx, q); err != nil {
+        t.Fatalf("export: %v", err)
+    }
+    want := port.BookingFilter{Status: "paid", FieldID: 1, UserID: 2,
+        From: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)}
+    if repo.filter != want {
+        t.Fatalf("expected %+v, got %+v", want, repo.filter)
+    }
+
+    for _, q := range []port.BookingQuery{
+        {Status: "refunded"},
+        {From: "June"},
+        {To: "2025-06-31"},
+        {From: "2025-06-02", To: "2025-06-01"},
+    } {
+        if _, err := svc.GetAllBookings(ctx, &q); !errors.Is(err, domain.ErrValidation) {
+            t.Fatalf("%+v: expected a validation error, got %v", q, err)
+        }
+    }
+}
+
+func TestBookingService_MarkNoShow(t *testing.T) {
+    ctx := context.Background()
+    repo := &mockBookingRepo{byID: map[uint]*domain.Booking{
+        1: {Status: domain.BookingStatusPaid, StartTime: testNow.Add(-time.Hour)},
+        2: {Status: domain.BookingStatusPaid, StartTime: testNow.Add(time.Hour)},
+        3: {Status: domain.BookingStatusCancelled, StartTime: testNow.Add(-time.Hour)},
+    }}
+    svc := NewBookingService(repo, newFieldRepoWith(1), fixedClock(testNow))
+
+    if err := svc.MarkNoShow(ctx, 1); err != nil || repo.byID[1].Status != domain.BookingStatusNoShow {
+        t.Fatalf("expected a no-show, got %v / %s", err, repo.byID[1].Status)
+    }
+    repo.updateErr = errors.New("should not be called")
+    if err := svc.MarkNoShow(ctx, 1); err != nil {
+        t.Fatalf("marking again should be a no-op, got %v", err)
+    }
+    if err := svc.PayBooking(ctx, 1, ""); err != nil || repo.byID[1].Status != domain.BookingStatusNoShow {
+        t.Fatalf("paying a no-show should be a no-op, got %v", err)
+    }
+    for _, id := range []uint{2, 3} {
+        if err := svc.MarkNoShow(ctx, id); !errors.Is(err, domain.ErrConflict) {
+            t.Fatalf("booking %d: expected conflict, got %v", id, err)
+        }
+    }
+    if err := svc.MarkNoShow(ctx, 99); !errors.Is(err, domain.ErrNotFound) {
+        t.Fatalf("expected not found, got %v", err)
+    }
+}
+
+type countingMetrics struct {
+    expired int
+}
+
+func (m *countingMetrics) BookingCreated(ctx context.Context)   {}
+func (m *countingMetrics) BookingConflict(ctx context.Context)  {}
+func (m *countingMetrics) PaymentSucceeded(ctx context.Context) {}
+func (m *countingMetrics) PaymentFailed(ctx context.Context)    {}
+func (m *countingMetrics) BookingExpired(ctx context.Context)   { m.expired++ }
+
+func TestBookingService_Spans(t *testing.T) {
+    exporter := tracetest.NewInMemoryExporter()
+    prev := otel.GetTracerProvider()
+    otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+    t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+    repo := &mockBookingRepo{avail: map[uint]bool{2: true}}
+    svc := NewBookingService(repo, newFieldRepoWith(1, 2), fixedClock(testNow))
+    start := testNow.Add(time.Hour)
+    end := start.Add(time.Hour)
+
+    b, _ := svc.CreateBooking(context.Background(), 5, &port.BookingRequest{FieldID: 1, StartTime: start, EndTime: end})
+    _, _ = svc.CreateBooking(context.Background(), 5, &port.BookingRequest{FieldID: 2, StartTime: start, EndTime: end})
+    repo.updateErr = errors.New("connection reset")
+    _ = svc.PayBooking(context.Background(), b.ID, "")
+
+    spans := exporter.GetSpans()
+    if len(spans) != 3 {
+        t.Fatalf("expected 3 spans, got %d", len(spans))
+    }
+
+    created := spans[0]
+    if created.Name != "BookingService.CreateBooking" || created.Status.Code == codes.Error {
+        t.Fatalf("unexpected create span: %s %v", created.Name, created.Status)
+    }
+    attrs := map[attribute.Key]attribute.Value{}
+    for _, kv := range created.Attributes {
+        attrs[kv.Key] = kv.Value
+    }
+    if attrs["booking.id"].AsInt64() != int64(b.ID) || attrs["booking.field_id"].AsInt64() != 1 || attrs["booking.user_id"].AsInt64() != 5 {
+        t.Fatalf("unexpected attributes: %v", created.Attributes)
+    }
+
+    // A conflict is an expected outcome: recorded, but not a failed span.
+    conflict := spans[1]
+    if conflict.Status.Code == codes.Error || len(conflict.Events) == 0 {
+        t.Fatalf("conflict should be recorded without error status: %v %v", conflict.Status, conflict.Events)
+    }
+
+    paid := spans[2]
+    if paid.Name != "BookingService.PayBooking" || paid.Status.Code != codes.Error {
+        t.Fatalf("expected failed PayBooking span, got %s %v", paid.Name, paid.Status)
     }
 }
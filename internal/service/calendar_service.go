@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// calendarHistory is how far back feeds go; later bookings of any status
+// are included, so calendars see cancellations.
+const calendarHistory = 90 * 24 * time.Hour
+
+type CalendarServiceImpl struct {
+	repo        port.CalendarRepository
+	bookingRepo port.BookingRepository
+	fieldRepo   port.FieldRepository
+	userRepo    port.UserRepository
+	clock       port.Clock
+}
+
+func NewCalendarService(repo port.CalendarRepository, bookingRepo port.BookingRepository, fieldRepo port.FieldRepository,
+	userRepo port.UserRepository, clock port.Clock) port.CalendarService {
+	return &CalendarServiceImpl{repo: repo, bookingRepo: bookingRepo, fieldRepo: fieldRepo, userRepo: userRepo, clock: clock}
+}
+
+func checkFeedOwner(owner port.CalendarFeedOwner) error {
+	if (owner.UserID == 0) == (owner.FieldID == 0) {
+		return fmt.Errorf("calendar feed owner must be a user or a field, got %+v", owner)
+	}
+	return nil
+}
+
+func (s *CalendarServiceImpl) CreateFeed(ctx context.Context, owner port.CalendarFeedOwner) (string, error) {
+	if err := checkFeedOwner(owner); err != nil {
+		return "", err
+	}
+	if owner.FieldID != 0 {
+		if _, err := s.fieldRepo.GetByID(ctx, owner.FieldID); err != nil {
+			return "", err
+		}
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := s.repo.SaveFeed(ctx, owner, hashFeedToken(token)); err != nil {
+		return "", err
+	}
+	logging.FromContext(ctx).Info("calendar feed issued", "user_id", owner.UserID, "field_id", owner.FieldID)
+	return token, nil
+}
+
+func (s *CalendarServiceImpl) DeleteFeed(ctx context.Context, owner port.CalendarFeedOwner) error {
+	if err := checkFeedOwner(owner); err != nil {
+		return err
+	}
+	return s.repo.DeleteFeed(ctx, owner)
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *CalendarServiceImpl) Feed(ctx context.Context, token string) (*domain.Calendar, error) {
+	feed, err := s.repo.GetFeedByTokenHash(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	owner := port.CalendarFeedOwner{}
+	calendar := &domain.Calendar{}
+	if feed.FieldID != nil {
+		// A deleted field's feed ends with it.
+		field, err := s.fieldRepo.GetByID(ctx, *feed.FieldID)
+		if err != nil {
+			return nil, err
+		}
+		owner.FieldID = field.ID
+		calendar.Name, calendar.ForField = field.Name, true
+	} else {
+		user, err := s.userRepo.GetByID(ctx, *feed.UserID)
+		if err != nil {
+			return nil, err
+		}
+		owner.UserID = user.ID
+		calendar.Name = "Bookings of " + user.Name
+	}
+
+	calendar.Bookings, err = s.repo.ListBookings(ctx, owner, s.clock.Now().Add(-calendarHistory))
+	if err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+func (s *CalendarServiceImpl) BookingCalendar(ctx context.Context, bookingID, userID uint, isAdmin bool) (*domain.Calendar, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && booking.UserID != userID {
+		// Other customers' bookings are not acknowledged to exist.
+		return nil, domain.NewNotFoundError("booking not found")
+	}
+	return &domain.Calendar{Name: fmt.Sprintf("Booking #%d", booking.ID), Bookings: []domain.Booking{*booking}}, nil
+}
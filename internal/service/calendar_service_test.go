@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+type mockCalendarRepo struct {
+	feeds map[string]port.CalendarFeedOwner
+	owner port.CalendarFeedOwner
+	since time.Time
+}
+
+func (m *mockCalendarRepo) SaveFeed(ctx context.Context, owner port.CalendarFeedOwner, tokenHash string) error {
+	m.DeleteFeed(ctx, owner)
+	m.feeds[tokenHash] = owner
+	return nil
+}
+
+func (m *mockCalendarRepo) DeleteFeed(ctx context.Context, owner port.CalendarFeedOwner) error {
+	for hash, o := range m.feeds {
+		if o == owner {
+			delete(m.feeds, hash)
+		}
+	}
+	return nil
+}
+
+func (m *mockCalendarRepo) GetFeedByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	owner, ok := m.feeds[tokenHash]
+	if !ok {
+		return nil, domain.NewNotFoundError("calendar feed not found")
+	}
+	feed := &domain.CalendarFeed{TokenHash: tokenHash}
+	if owner.FieldID != 0 {
+		feed.FieldID = &owner.FieldID
+	} else {
+		feed.UserID = &owner.UserID
+	}
+	return feed, nil
+}
+
+func (m *mockCalendarRepo) ListBookings(ctx context.Context, owner port.CalendarFeedOwner, since time.Time) ([]domain.Booking, error) {
+	m.owner, m.since = owner, since
+	return []domain.Booking{{FieldID: 1, UserID: 7}}, nil
+}
+
+func newTestCalendar(repo *mockCalendarRepo, bookings *mockBookingRepo) port.CalendarService {
+	return NewCalendarService(repo, bookings, courtARepo(), budiRepo(), fixedClock(testNow))
+}
+
+func TestCalendarService_Feeds(t *testing.T) {
+	repo := &mockCalendarRepo{feeds: map[string]port.CalendarFeedOwner{}}
+	svc := newTestCalendar(repo, &mockBookingRepo{})
+	ctx := context.Background()
+
+	first, err := svc.CreateFeed(ctx, port.CalendarFeedOwner{UserID: 7})
+	if err != nil || len(first) != 64 {
+		t.Fatalf("unexpected token %q, %v", first, err)
+	}
+	if _, ok := repo.feeds[first]; ok {
+		t.Fatalf("the token itself must not be stored")
+	}
+	second, _ := svc.CreateFeed(ctx, port.CalendarFeedOwner{UserID: 7})
+	if _, err := svc.Feed(ctx, first); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("a replaced token must stop working, got %v", err)
+	}
+
+	calendar, err := svc.Feed(ctx, second)
+	if err != nil || calendar.Name != "Bookings of Budi" || calendar.ForField || len(calendar.Bookings) != 1 {
+		t.Fatalf("unexpected calendar %+v, %v", calendar, err)
+	}
+	if repo.owner != (port.CalendarFeedOwner{UserID: 7}) || !repo.since.Equal(testNow.Add(-calendarHistory)) {
+		t.Fatalf("unexpected listing for %+v since %v", repo.owner, repo.since)
+	}
+
+	fieldToken, _ := svc.CreateFeed(ctx, port.CalendarFeedOwner{FieldID: 1})
+	if calendar, err := svc.Feed(ctx, fieldToken); err != nil || calendar.Name != "Court A" || !calendar.ForField {
+		t.Fatalf("unexpected field calendar %+v, %v", calendar, err)
+	}
+	if _, err := svc.CreateFeed(ctx, port.CalendarFeedOwner{FieldID: 9}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found for a missing field, got %v", err)
+	}
+
+	if err := svc.DeleteFeed(ctx, port.CalendarFeedOwner{FieldID: 1}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := svc.Feed(ctx, fieldToken); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("a revoked token must stop working, got %v", err)
+	}
+}
+
+func TestCalendarService_BookingCalendar(t *testing.T) {
+	bookings := &mockBookingRepo{}
+	bookings.Create(context.Background(), &domain.Booking{UserID: 7, FieldID: 1})
+	svc := newTestCalendar(&mockCalendarRepo{}, bookings)
+
+	if calendar, err := svc.BookingCalendar(context.Background(), 1, 7, false); err != nil || len(calendar.Bookings) != 1 {
+		t.Fatalf("the customer should get their booking, got %+v, %v", calendar, err)
+	}
+	if _, err := svc.BookingCalendar(context.Background(), 1, 8, false); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("another customer must not, got %v", err)
+	}
+	if _, err := svc.BookingCalendar(context.Background(), 1, 8, true); err != nil {
+		t.Fatalf("an admin should, got %v", err)
+	}
+}
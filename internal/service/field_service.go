@@ -1,37 +1,102 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
 )
 
 type FieldServiceImpl struct {
-	repo port.FieldRepository
+	repo  port.FieldRepository
+	clock port.Clock
+	// defaultTimeZone is given to fields created without a time zone.
+	defaultTimeZone string
+}
+
+func NewFieldService(repo port.FieldRepository, clock port.Clock, defaultTimeZone string) port.FieldService {
+	return &FieldServiceImpl{repo: repo, clock: clock, defaultTimeZone: defaultTimeZone}
+}
+
+func validateFieldRequest(req *port.CreateFieldRequest) error {
+	if req.Name == "" {
+		return domain.NewValidationError("field name is required")
+	}
+	if req.PricePerHour <= 0 {
+		return domain.NewValidationError("price per hour must be greater than zero")
+	}
+	return nil
+}
+
+// validateSchedule checks the time zone and opening hours of field.
+func validateSchedule(field *domain.Field) error {
+	if _, err := time.LoadLocation(field.TimeZone); err != nil || field.TimeZone == "" {
+		return domain.NewValidationError(fmt.Sprintf("time_zone %q is not an IANA time zone such as Asia/Jakarta", field.TimeZone))
+	}
+	oh, om, err := domain.ParseClockTime(field.OpenTime)
+	if err != nil || oh == 24 {
+		return domain.NewValidationError("open_time must be a time of day in HH:MM form")
+	}
+	ch, cm, err := domain.ParseClockTime(field.CloseTime)
+	if err != nil {
+		return domain.NewValidationError("close_time must be a time of day in HH:MM form, or 24:00")
+	}
+	if ch*60+cm <= oh*60+om {
+		return domain.NewValidationError("close_time must be after open_time; fields cannot stay open past midnight")
+	}
+	return nil
 }
 
-func NewFieldService(repo port.FieldRepository) port.FieldService {
-	return &FieldServiceImpl{repo: repo}
+// applySchedule copies the schedule settings that were given onto field.
+func applySchedule(field *domain.Field, req *port.CreateFieldRequest) {
+	if req.TimeZone != "" {
+		field.TimeZone = req.TimeZone
+	}
+	if req.OpenTime != "" {
+		field.OpenTime = req.OpenTime
+	}
+	if req.CloseTime != "" {
+		field.CloseTime = req.CloseTime
+	}
 }
 
-func (s *FieldServiceImpl) CreateField(req *port.CreateFieldRequest) error {
+func (s *FieldServiceImpl) CreateField(ctx context.Context, req *port.CreateFieldRequest) error {
+	if err := validateFieldRequest(req); err != nil {
+		return err
+	}
+
 	field := &domain.Field{
 		Name:         req.Name,
 		PricePerHour: req.PricePerHour,
 		Location:     req.Location,
+		TimeZone:     s.defaultTimeZone,
+		OpenTime:     domain.DefaultOpenTime,
+		CloseTime:    domain.DefaultCloseTime,
 	}
-	return s.repo.Create(field)
+	applySchedule(field, req)
+	if err := validateSchedule(field); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, field)
 }
 
-func (s *FieldServiceImpl) GetAllFields() ([]domain.Field, error) {
-	return s.repo.GetAll()
+func (s *FieldServiceImpl) GetAllFields(ctx context.Context) ([]domain.Field, error) {
+	return s.repo.GetAll(ctx)
 }
 
-func (s *FieldServiceImpl) GetFieldByID(id uint) (*domain.Field, error) {
-	return s.repo.GetByID(id)
+func (s *FieldServiceImpl) GetFieldByID(ctx context.Context, id uint) (*domain.Field, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *FieldServiceImpl) UpdateField(id uint, req *port.CreateFieldRequest) error {
-	field, err := s.repo.GetByID(id)
+func (s *FieldServiceImpl) UpdateField(ctx context.Context, id uint, req *port.CreateFieldRequest) error {
+	if err := validateFieldRequest(req); err != nil {
+		return err
+	}
+
+	field, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -39,10 +104,48 @@ func (s *FieldServiceImpl) UpdateField(id uint, req *port.CreateFieldRequest) er
 	field.Name = req.Name
 	field.PricePerHour = req.PricePerHour
 	field.Location = req.Location
+	applySchedule(field, req)
+	if field.TimeZone == "" {
+		field.TimeZone = s.defaultTimeZone
+	}
+	field.OpenTime, field.CloseTime = field.Hours()
+	if err := validateSchedule(field); err != nil {
+		return err
+	}
 
-	return s.repo.Update(field)
+	return s.repo.Update(ctx, field)
 }
 
-func (s *FieldServiceImpl) DeleteField(id uint) error {
-	return s.repo.Delete(id)
+func (s *FieldServiceImpl) DeleteField(ctx context.Context, id uint, policy string) error {
+	if policy == "" {
+		policy = port.FieldDeletePolicyBlock
+	}
+	if policy != port.FieldDeletePolicyBlock && policy != port.FieldDeletePolicyCascade {
+		return domain.NewValidationError("policy must be either 'block' or 'cascade'")
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+	if policy == port.FieldDeletePolicyBlock {
+		count, err := s.repo.DeleteIfUnbooked(ctx, id, now)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.NewConflictError(fmt.Sprintf("field has %d upcoming booking(s); use policy=cascade to cancel them", count))
+		}
+		return nil
+	}
+
+	cancelled, err := s.repo.DeleteAndCancelBookings(ctx, id, now)
+	if err != nil {
+		return err
+	}
+	// Customers are notified from the booking.cancelled events recorded
+	// in the same transaction.
+	logging.FromContext(ctx).Info("field deleted", "field_id", id, "cancelled_bookings", len(cancelled))
+	return nil
 }
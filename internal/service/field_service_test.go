@@ -1,8 +1,10 @@
 package service
 
 import (
+    "context"
     "errors"
     "testing"
+    "time"
 
     "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
     "github.com/HIUNCY/sagara-booking-api/internal/core/port"
@@ -10,12 +12,15 @@ import (
 
 type mockFieldRepo struct {
     byID map[uint]*domain.Field
+    upcoming map[uint][]domain.Booking
     createErr error
     updateErr error
     deleteErr error
+    // deletedAfter is the cutoff of the last delete.
+    deletedAfter time.Time
 }
 
-func (m *mockFieldRepo) Create(f *domain.Field) error {
+func (m *mockFieldRepo) Create(ctx context.Context, f *domain.Field) error {
     if m.createErr != nil {
         return m.createErr
     }
@@ -27,7 +32,7 @@ func (m *mockFieldRepo) Create(f *domain.Field) error {
     return nil
 }
 
-func (m *mockFieldRepo) GetAll() ([]domain.Field, error) {
+func (m *mockFieldRepo) GetAll(ctx context.Context) ([]domain.Field, error) {
     res := make([]domain.Field, 0, len(m.byID))
     for _, f := range m.byID {
         res = append(res, *f)
@@ -35,67 +40,141 @@ func (m *mockFieldRepo) GetAll() ([]domain.Field, error) {
     return res, nil
 }
 
-func (m *mockFieldRepo) GetByID(id uint) (*domain.Field, error) {
+func (m *mockFieldRepo) GetByID(ctx context.Context, id uint) (*domain.Field, error) {
     if f, ok := m.byID[id]; ok {
         return f, nil
     }
-    return nil, errors.New("not found")
+    return nil, domain.NewNotFoundError("not found")
 }
 
-func (m *mockFieldRepo) Update(f *domain.Field) error {
+func (m *mockFieldRepo) GetByIDWithDeleted(ctx context.Context, id uint) (*domain.Field, error) {
+    return m.GetByID(ctx, id)
+}
+
+func (m *mockFieldRepo) Update(ctx context.Context, f *domain.Field) error {
     if m.updateErr != nil {
         return m.updateErr
     }
     if _, ok := m.byID[f.ID]; !ok {
-        return errors.New("not found")
+        return domain.NewNotFoundError("not found")
     }
     m.byID[f.ID] = f
     return nil
 }
 
-func (m *mockFieldRepo) Delete(id uint) error {
+func (m *mockFieldRepo) Delete(ctx context.Context, id uint) error {
     if m.deleteErr != nil {
         return m.deleteErr
     }
     if _, ok := m.byID[id]; !ok {
-        return errors.New("not found")
+        return domain.NewNotFoundError("not found")
     }
     delete(m.byID, id)
     return nil
 }
 
+func (m *mockFieldRepo) DeleteIfUnbooked(ctx context.Context, id uint, after time.Time) (int64, error) {
+    m.deletedAfter = after
+    if n := len(m.upcoming[id]); n > 0 {
+        return int64(n), nil
+    }
+    return 0, m.Delete(ctx, id)
+}
+
+func (m *mockFieldRepo) DeleteAndCancelBookings(ctx context.Context, id uint, after time.Time) ([]domain.Booking, error) {
+    m.deletedAfter = after
+    cancelled := m.upcoming[id]
+    for i := range cancelled {
+        cancelled[i].Status = domain.BookingStatusCancelled
+    }
+    delete(m.upcoming, id)
+    return cancelled, m.Delete(ctx, id)
+}
+
 func TestFieldService_CRUD(t *testing.T) {
     repo := &mockFieldRepo{}
-    svc := NewFieldService(repo)
+    svc := NewFieldService(repo, fixedClock(testNow), "Asia/Jakarta")
 
     // create
-    if err := svc.CreateField(&port.CreateFieldRequest{Name: "A", PricePerHour: 10, Location: "L"}); err != nil {
+    if err := svc.CreateField(context.Background(), &port.CreateFieldRequest{Name: "A", PricePerHour: 10, Location: "L"}); err != nil {
         t.Fatalf("create error: %v", err)
     }
-    all, _ := svc.GetAllFields()
+    all, _ := svc.GetAllFields(context.Background())
     if len(all) != 1 {
         t.Fatalf("expected 1, got %d", len(all))
     }
-    f, _ := svc.GetFieldByID(all[0].ID)
+    f, _ := svc.GetFieldByID(context.Background(), all[0].ID)
     if f.Name != "A" {
         t.Fatalf("unexpected field: %+v", f)
     }
 
     // update
-    if err := svc.UpdateField(f.ID, &port.CreateFieldRequest{Name: "B", PricePerHour: 20, Location: "X"}); err != nil {
+    if err := svc.UpdateField(context.Background(), f.ID, &port.CreateFieldRequest{Name: "B", PricePerHour: 20, Location: "X"}); err != nil {
         t.Fatalf("update error: %v", err)
     }
-    f2, _ := svc.GetFieldByID(f.ID)
+    f2, _ := svc.GetFieldByID(context.Background(), f.ID)
     if f2.Name != "B" || f2.PricePerHour != 20 || f2.Location != "X" {
         t.Fatalf("update not applied: %+v", f2)
     }
 
     // delete
-    if err := svc.DeleteField(f.ID); err != nil {
+    if err := svc.DeleteField(context.Background(), f.ID, port.FieldDeletePolicyBlock); err != nil {
         t.Fatalf("delete error: %v", err)
     }
-    all2, _ := svc.GetAllFields()
+    all2, _ := svc.GetAllFields(context.Background())
     if len(all2) != 0 {
         t.Fatalf("expected 0 after delete, got %d", len(all2))
     }
 }
+
+func TestFieldService_DeleteField_Policies(t *testing.T) {
+    repo := &mockFieldRepo{
+        byID: map[uint]*domain.Field{1: {Name: "A"}, 2: {Name: "B"}},
+        upcoming: map[uint][]domain.Booking{
+            1: {{FieldID: 1, Status: domain.BookingStatusPaid}, {FieldID: 1, Status: domain.BookingStatusPending}},
+        },
+    }
+    repo.byID[1].ID, repo.byID[2].ID = 1, 2
+    repo.upcoming[1][0].ID, repo.upcoming[1][1].ID = 10, 11
+    svc := NewFieldService(repo, fixedClock(testNow), "Asia/Jakarta")
+
+    // unknown policy
+    if err := svc.DeleteField(context.Background(), 1, "purge"); !errors.Is(err, domain.ErrValidation) {
+        t.Fatalf("expected validation error, got %v", err)
+    }
+
+    // missing field
+    if err := svc.DeleteField(context.Background(), 99, port.FieldDeletePolicyBlock); err == nil {
+        t.Fatalf("expected error for missing field")
+    }
+
+    // block refuses while bookings are upcoming
+    if err := svc.DeleteField(context.Background(), 1, port.FieldDeletePolicyBlock); !errors.Is(err, domain.ErrConflict) {
+        t.Fatalf("expected conflict, got %v", err)
+    }
+    if _, ok := repo.byID[1]; !ok {
+        t.Fatalf("field should not be deleted under block policy")
+    }
+    if !repo.deletedAfter.Equal(testNow) {
+        t.Fatalf("expected bookings counted from the clock's now, got %v", repo.deletedAfter)
+    }
+
+    // block deletes a field without upcoming bookings
+    if err := svc.DeleteField(context.Background(), 2, ""); err != nil {
+        t.Fatalf("delete error: %v", err)
+    }
+
+    // cascade deletes the field and cancels its bookings
+    upcoming := repo.upcoming[1]
+    if err := svc.DeleteField(context.Background(), 1, port.FieldDeletePolicyCascade); err != nil {
+        t.Fatalf("cascade error: %v", err)
+    }
+    if _, ok := repo.byID[1]; ok {
+        t.Fatalf("field should be deleted under cascade policy")
+    }
+    for _, b := range upcoming {
+        if b.Status != domain.BookingStatusCancelled {
+            t.Fatalf("expected booking %d cancelled, got %s", b.ID, b.Status)
+        }
+    }
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/HIUNCY/sagara-booking-api/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxOverrideReason is the longest override reason stored.
+	maxOverrideReason = 500
+	maxGuestName      = 100
+	// maxStartedFor is how long ago a slot booked with past_start may have
+	// started, enough to record a walk-in after the fact but not to rewrite
+	// old history.
+	maxStartedFor = 24 * time.Hour
+)
+
+// frontDeskOverrides are the booking rules an admin may waive.
+var frontDeskOverrides = map[string]bool{
+	domain.OverridePastStart:    true,
+	domain.OverrideOpeningHours: true,
+}
+
+// phoneSeparators are dropped from guest phone numbers, so the same number
+// written differently finds the same guest.
+var (
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+	phonePattern    = regexp.MustCompile(`^\+?[0-9]{6,20}$`)
+)
+
+type FrontDeskServiceImpl struct {
+	bookingRepo port.BookingRepository
+	fieldRepo   port.FieldRepository
+	userRepo    port.UserRepository
+	clock       port.Clock
+}
+
+func NewFrontDeskService(bookingRepo port.BookingRepository, fieldRepo port.FieldRepository, userRepo port.UserRepository,
+	clock port.Clock) port.FrontDeskService {
+	return &FrontDeskServiceImpl{bookingRepo: bookingRepo, fieldRepo: fieldRepo, userRepo: userRepo, clock: clock}
+}
+
+func (s *FrontDeskServiceImpl) CreateBooking(ctx context.Context, staffID uint, req *port.FrontDeskBookingRequest) (_ *domain.Booking, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "FrontDeskService.CreateBooking", trace.WithAttributes(
+		attribute.Int("booking.staff_id", int(staffID)),
+		attribute.Int("booking.field_id", int(req.FieldID)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	overrides, reason, err := checkOverrides(req.Overrides, req.OverrideReason)
+	if err != nil {
+		return nil, err
+	}
+	method, reference := strings.TrimSpace(req.PaymentMethod), strings.TrimSpace(req.PaymentReference)
+	switch {
+	case method != "" && method != domain.PaymentMethodCash:
+		return nil, domain.NewValidationError("payment_method must be cash, or empty to leave the booking unpaid")
+	case len(reference) > maxPaymentReference:
+		return nil, domain.NewValidationError(fmt.Sprintf("payment_reference must be at most %d characters", maxPaymentReference))
+	case reference != "" && method == "":
+		return nil, domain.NewValidationError("payment_reference is only recorded with a payment_method")
+	}
+	guest, err := checkCustomer(req)
+	if err != nil {
+		return nil, err
+	}
+
+	field, start, end, err := s.checkSlot(ctx, req, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if guest == nil {
+		if _, err := s.userRepo.GetByID(ctx, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Guests have no holds of their own, so any hold on the slot counts.
+	isBooked, err := s.bookingRepo.CheckAvailability(ctx, field.ID, req.UserID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if isBooked {
+		return nil, domain.NewConflictError("field is already booked or held at this time")
+	}
+
+	booking := &domain.Booking{
+		UserID:    req.UserID,
+		FieldID:   field.ID,
+		StartTime: start,
+		EndTime:   end,
+		Status:    domain.BookingStatusPending,
+		CreatedBy: &staffID,
+	}
+	if len(overrides) > 0 {
+		booking.Overrides, booking.OverrideReason = strings.Join(overrides, ","), reason
+	}
+	if method == domain.PaymentMethodCash {
+		now, amount := s.clock.Now(), field.Price(start, end)
+		booking.Status, booking.PaymentMethod, booking.PaymentReference = domain.BookingStatusPaid, method, reference
+		booking.PaidAt, booking.AmountPaid = &now, &amount
+	}
+
+	// The guest is saved with the booking, so a booking that fails leaves no
+	// guest behind.
+	booking.User = guest
+	if err := s.bookingRepo.Create(ctx, booking); err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("booking.id", int(booking.ID)))
+
+	logger := logging.FromContext(ctx).With("booking_id", booking.ID, "field_id", booking.FieldID,
+		"user_id", booking.UserID, "staff_id", staffID)
+	logger.Info("booking created at the front desk", "guest", guest != nil, "status", booking.Status)
+	if len(overrides) > 0 {
+		logger.Warn("booking rules overridden", "overrides", booking.Overrides, "reason", reason)
+	}
+	return booking, nil
+}
+
+// checkOverrides validates the rules to waive and the reason for waiving
+// them, and returns them without duplicates.
+func checkOverrides(names []string, reason string) ([]string, string, error) {
+	reason = strings.TrimSpace(reason)
+	var overrides []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !frontDeskOverrides[name] {
+			return nil, "", domain.NewValidationError(fmt.Sprintf("unknown override %q; rules that can be overridden are %s and %s",
+				name, domain.OverridePastStart, domain.OverrideOpeningHours))
+		}
+		if !seen[name] {
+			seen[name] = true
+			overrides = append(overrides, name)
+		}
+	}
+
+	switch {
+	case len(overrides) > 0 && reason == "":
+		return nil, "", domain.NewValidationError("override_reason is required to override booking rules")
+	case len(overrides) == 0 && reason != "":
+		return nil, "", domain.NewValidationError("override_reason is only recorded with overrides")
+	case len(reason) > maxOverrideReason:
+		return nil, "", domain.NewValidationError(fmt.Sprintf("override_reason must be at most %d characters", maxOverrideReason))
+	}
+	return overrides, reason, nil
+}
+
+// checkCustomer requires either an existing user or a guest, and returns the
+// guest to save, if any, with their phone number normalised.
+func checkCustomer(req *port.FrontDeskBookingRequest) (*domain.User, error) {
+	if (req.UserID == 0) == (req.Guest == nil) {
+		return nil, domain.NewValidationError("give either user_id or guest")
+	}
+	if req.Guest == nil {
+		return nil, nil
+	}
+
+	name, phone := strings.TrimSpace(req.Guest.Name), phoneSeparators.Replace(strings.TrimSpace(req.Guest.Phone))
+	switch {
+	case name == "":
+		return nil, domain.NewValidationError("guest name is required")
+	case len(name) > maxGuestName:
+		return nil, domain.NewValidationError(fmt.Sprintf("guest name must be at most %d characters", maxGuestName))
+	case !phonePattern.MatchString(phone):
+		return nil, domain.NewValidationError("guest phone must be a phone number of 6 to 20 digits")
+	}
+	return &domain.User{Name: name, Phone: phone, Role: domain.RoleGuest}, nil
+}
+
+// checkSlot is the customer's checkSlot with the overridden rules waived,
+// returning the field and the slot in UTC.
+func (s *FrontDeskServiceImpl) checkSlot(ctx context.Context, req *port.FrontDeskBookingRequest, overrides []string) (*domain.Field, time.Time, time.Time, error) {
+	waived := map[string]bool{}
+	for _, name := range overrides {
+		waived[name] = true
+	}
+
+	if req.FieldID == 0 {
+		return nil, req.StartTime, req.EndTime, domain.NewValidationError("field_id is required")
+	}
+	start, end := req.StartTime.UTC(), req.EndTime.UTC()
+	if !end.After(start) {
+		return nil, start, end, domain.NewValidationError("start time must be before end time")
+	}
+	now := s.clock.Now()
+	switch {
+	case !waived[domain.OverridePastStart] && !start.After(now):
+		return nil, start, end, domain.NewValidationError("start time must be in the future; override " +
+			domain.OverridePastStart + " to book a slot that has started")
+	case start.Before(now.Add(-maxStartedFor)):
+		return nil, start, end, domain.NewValidationError(fmt.Sprintf("a slot can be booked at most %s after it started",
+			maxStartedFor))
+	}
+
+	field, err := s.fieldRepo.GetByID(ctx, req.FieldID)
+	if err != nil {
+		return nil, start, end, err
+	}
+	if !waived[domain.OverrideOpeningHours] {
+		if err := checkOpeningHours(field, start, end); err != nil {
+			return nil, start, end, err
+		}
+	} else if err := checkSameDay(field, start, end); err != nil {
+		return nil, start, end, err
+	}
+	return field, start, end, nil
+}
+
+// checkSameDay requires a booking made outside the opening hours to still
+// end by midnight, in the field's time zone, of the day it starts.
+func checkSameDay(field *domain.Field, start, end time.Time) error {
+	loc, err := field.Zone()
+	if err != nil {
+		return fmt.Errorf("field %d has an invalid time zone: %w", field.ID, err)
+	}
+	y, m, d := start.In(loc).Date()
+	if end.After(time.Date(y, m, d+1, 0, 0, 0, 0, loc)) {
+		return domain.NewValidationError(fmt.Sprintf("a booking must end by midnight %s on the day it starts, "+
+			"even with %s overridden", loc, domain.OverrideOpeningHours))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+func newTestFrontDesk(bookings *mockBookingRepo) port.FrontDeskService {
+	return NewFrontDeskService(bookings, courtARepo(), budiRepo(), fixedClock(testNow))
+}
+
+// frontDeskRequest asks for field 1 from start for 90 minutes; testNow is
+// 10:00 in Jakarta.
+func frontDeskRequest(start time.Time) *port.FrontDeskBookingRequest {
+	return &port.FrontDeskBookingRequest{
+		BookingRequest: port.BookingRequest{FieldID: 1, StartTime: start, EndTime: start.Add(90 * time.Minute)},
+	}
+}
+
+func TestFrontDeskService_GuestPaysCash(t *testing.T) {
+	bookings := &mockBookingRepo{}
+	svc := newTestFrontDesk(bookings)
+	ctx := context.Background()
+
+	req := frontDeskRequest(testNow.Add(2 * time.Hour))
+	req.Guest = &port.GuestContact{Name: " Andi ", Phone: "+62 812-3456-7890"}
+	req.PaymentMethod, req.PaymentReference = domain.PaymentMethodCash, "RCPT-001"
+	booking, err := svc.CreateBooking(ctx, 99, req)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	guest := bookings.guests["+6281234567890"]
+	if guest == nil || guest.ID != booking.UserID || guest.Role != domain.RoleGuest || guest.Name != "Andi" {
+		t.Fatalf("unexpected guest %+v for user %d", guest, booking.UserID)
+	}
+	if booking.Status != domain.BookingStatusPaid || booking.PaymentMethod != domain.PaymentMethodCash ||
+		booking.PaymentReference != "RCPT-001" || *booking.AmountPaid != 150000 || !booking.PaidAt.Equal(testNow) {
+		t.Fatalf("expected a cash payment, got %+v", booking)
+	}
+	if booking.CreatedBy == nil || *booking.CreatedBy != 99 || booking.Overrides != "" {
+		t.Fatalf("unexpected audit fields %+v", booking)
+	}
+
+	// The same number written differently is the same guest, whose name on
+	// file is kept.
+	req = frontDeskRequest(testNow.Add(4 * time.Hour))
+	req.Guest = &port.GuestContact{Name: "Andi S.", Phone: "+62 (812) 3456.7890"}
+	again, err := svc.CreateBooking(ctx, 99, req)
+	if err != nil || again.UserID != booking.UserID || again.Status != domain.BookingStatusPending {
+		t.Fatalf("expected an unpaid booking for the same guest, got %+v, %v", again, err)
+	}
+	if again.User.Name != "Andi" || len(bookings.guests) != 1 {
+		t.Fatalf("expected the guest's name to be kept, got %+v", again.User)
+	}
+}
+
+func TestFrontDeskService_Overrides(t *testing.T) {
+	svc := newTestFrontDesk(&mockBookingRepo{})
+	ctx := context.Background()
+	started := frontDeskRequest(testNow.Add(-30 * time.Minute))
+	started.UserID = 7
+	late := frontDeskRequest(time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)) // 22:00-23:30 WIB
+	late.UserID = 7
+
+	if _, err := svc.CreateBooking(ctx, 99, started); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("a started slot needs an override, got %v", err)
+	}
+	if _, err := svc.CreateBooking(ctx, 99, late); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("a slot past closing needs an override, got %v", err)
+	}
+
+	late.Overrides = []string{domain.OverrideOpeningHours}
+	if _, err := svc.CreateBooking(ctx, 99, late); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("an override needs a reason, got %v", err)
+	}
+	late.OverrideReason = "Tournament final"
+	booking, err := svc.CreateBooking(ctx, 99, late)
+	if err != nil || booking.Overrides != "opening_hours" || booking.OverrideReason != "Tournament final" {
+		t.Fatalf("unexpected overridden booking %+v, %v", booking, err)
+	}
+
+	// Waiving one rule does not waive the other.
+	started.Overrides, started.OverrideReason = []string{domain.OverrideOpeningHours}, "Walk-in"
+	if _, err := svc.CreateBooking(ctx, 99, started); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected the start time to still be checked, got %v", err)
+	}
+	started.Overrides = []string{domain.OverridePastStart, domain.OverridePastStart}
+	if booking, err := svc.CreateBooking(ctx, 99, started); err != nil || booking.Overrides != "past_start" {
+		t.Fatalf("unexpected booking %+v, %v", booking, err)
+	}
+
+	// Overrides still keep a booking to one day, and to recent history.
+	overnight := frontDeskRequest(time.Date(2025, 6, 2, 16, 0, 0, 0, time.UTC)) // 23:00-00:30 WIB
+	overnight.UserID, overnight.Overrides, overnight.OverrideReason = 7, []string{domain.OverrideOpeningHours}, "Late final"
+	if _, err := svc.CreateBooking(ctx, 99, overnight); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("a booking past midnight must be refused, got %v", err)
+	}
+	old := frontDeskRequest(testNow.Add(-25 * time.Hour))
+	old.UserID, old.Overrides, old.OverrideReason = 7, []string{domain.OverridePastStart, domain.OverrideOpeningHours}, "Forgot"
+	if _, err := svc.CreateBooking(ctx, 99, old); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("a slot that started over a day ago must be refused, got %v", err)
+	}
+}
+
+func TestFrontDeskService_Rejects(t *testing.T) {
+	bookings := &mockBookingRepo{avail: map[uint]bool{1: true}}
+	svc := newTestFrontDesk(bookings)
+	ctx := context.Background()
+	start := testNow.Add(2 * time.Hour)
+
+	cases := []struct {
+		name string
+		set  func(*port.FrontDeskBookingRequest)
+		want error
+	}{
+		{"no customer", func(r *port.FrontDeskBookingRequest) {}, domain.ErrValidation},
+		{"user and guest", func(r *port.FrontDeskBookingRequest) {
+			r.UserID, r.Guest = 7, &port.GuestContact{Name: "Andi", Phone: "0812345678"}
+		}, domain.ErrValidation},
+		{"guest without phone", func(r *port.FrontDeskBookingRequest) { r.Guest = &port.GuestContact{Name: "Andi"} }, domain.ErrValidation},
+		{"unknown user", func(r *port.FrontDeskBookingRequest) { r.UserID = 8 }, domain.ErrNotFound},
+		{"card payment", func(r *port.FrontDeskBookingRequest) { r.UserID, r.PaymentMethod = 7, "card" }, domain.ErrValidation},
+		{"reference without payment", func(r *port.FrontDeskBookingRequest) { r.UserID, r.PaymentReference = 7, "RCPT-1" }, domain.ErrValidation},
+		{"unknown override", func(r *port.FrontDeskBookingRequest) {
+			r.UserID, r.Overrides, r.OverrideReason = 7, []string{"overlap"}, "VIP"
+		}, domain.ErrValidation},
+		{"reason without override", func(r *port.FrontDeskBookingRequest) { r.UserID, r.OverrideReason = 7, "VIP" }, domain.ErrValidation},
+		{"slot taken", func(r *port.FrontDeskBookingRequest) { r.UserID = 7 }, domain.ErrConflict},
+	}
+	for _, tc := range cases {
+		req := frontDeskRequest(start)
+		tc.set(req)
+		if _, err := svc.CreateBooking(ctx, 99, req); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if len(bookings.created) != 0 {
+		t.Fatalf("nothing should have been booked, got %d", len(bookings.created))
+	}
+}
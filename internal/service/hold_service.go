@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// maxActiveHolds is how many checkout holds a customer may have at once, so
+// nobody can block a field's calendar by holding slot after slot.
+const maxActiveHolds = 3
+
+type HoldServiceImpl struct {
+	repo      port.HoldRepository
+	bookings  port.BookingService
+	fieldRepo port.FieldRepository
+	clock     port.Clock
+	// maxHold is the longest a slot can be held, and how long it is held
+	// when the request does not say.
+	maxHold time.Duration
+}
+
+func NewHoldService(repo port.HoldRepository, bookings port.BookingService, fieldRepo port.FieldRepository, clock port.Clock, maxHold time.Duration) port.HoldService {
+	return &HoldServiceImpl{repo: repo, bookings: bookings, fieldRepo: fieldRepo, clock: clock, maxHold: maxHold}
+}
+
+// PlaceHold holds a slot a booking could be made for. The hold never
+// outlasts the start of the slot.
+func (s *HoldServiceImpl) PlaceHold(ctx context.Context, userID uint, req *port.HoldRequest) (*domain.SlotHold, error) {
+	maxMinutes := int(s.maxHold / time.Minute)
+	if req.Minutes < 0 || req.Minutes > maxMinutes {
+		return nil, domain.NewValidationError(fmt.Sprintf("minutes must be between 0 and %d, where 0 holds for the longest time", maxMinutes))
+	}
+	now := s.clock.Now()
+	start, end, err := checkSlot(ctx, s.fieldRepo, now, req.FieldID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := s.maxHold
+	if req.Minutes > 0 {
+		duration = time.Duration(req.Minutes) * time.Minute
+	}
+	expires := now.Add(duration)
+	if expires.After(start) {
+		expires = start
+	}
+	hold := &domain.SlotHold{
+		FieldID:   req.FieldID,
+		UserID:    userID,
+		StartTime: start,
+		EndTime:   end,
+		ExpiresAt: expires.UTC().Truncate(time.Microsecond),
+	}
+	if err := s.repo.Create(ctx, hold, maxActiveHolds); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("slot held",
+		"hold_id", hold.ID, "field_id", hold.FieldID, "user_id", userID, "expires_at", hold.ExpiresAt)
+	return hold, nil
+}
+
+func (s *HoldServiceImpl) GetMyHolds(ctx context.Context, userID uint) ([]domain.SlotHold, error) {
+	return s.repo.ListActiveByUser(ctx, userID, s.clock.Now())
+}
+
+func (s *HoldServiceImpl) ReleaseHold(ctx context.Context, userID, id uint) error {
+	if _, err := s.ownHold(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Release(ctx, id)
+}
+
+// Checkout books the held slot through the booking service, so the booking
+// is validated, recorded and announced like any other; creating it
+// consumes the hold.
+func (s *HoldServiceImpl) Checkout(ctx context.Context, userID, id uint) (*domain.Booking, error) {
+	hold, err := s.ownHold(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !hold.ExpiresAt.After(s.clock.Now()) {
+		return nil, domain.NewConflictError("the hold has expired; hold or book the slot again")
+	}
+	return s.bookings.CreateBooking(ctx, userID, &port.BookingRequest{
+		FieldID:   hold.FieldID,
+		StartTime: hold.StartTime,
+		EndTime:   hold.EndTime,
+	})
+}
+
+// ownHold finds one of the user's holds. Other customers' holds are not
+// acknowledged to exist.
+func (s *HoldServiceImpl) ownHold(ctx context.Context, userID, id uint) (*domain.SlotHold, error) {
+	hold, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hold.UserID != userID {
+		return nil, domain.NewNotFoundError("hold not found")
+	}
+	return hold, nil
+}
+
+// SlotHoldExpirer deletes checkout holds that ran out. They stop blocking
+// their slot the moment they expire; deleting them passes the slot to its
+// waitlist through hold.released events, and keeps the table small.
+type SlotHoldExpirer struct {
+	repo  port.HoldRepository
+	clock port.Clock
+}
+
+func NewSlotHoldExpirer(repo port.HoldRepository, clock port.Clock) *SlotHoldExpirer {
+	return &SlotHoldExpirer{repo: repo, clock: clock}
+}
+
+// ExpireOnce deletes every expired checkout hold and returns how many.
+func (e *SlotHoldExpirer) ExpireOnce(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := e.repo.DeleteExpired(ctx, e.clock.Now(), expireBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += int(n)
+		if n < expireBatchSize {
+			if total > 0 {
+				logging.FromContext(ctx).Debug("expired holds released", "count", total)
+			}
+			return total, nil
+		}
+	}
+}
+
+// Run deletes expired holds every interval until ctx is cancelled.
+func (e *SlotHoldExpirer) Run(ctx context.Context, interval time.Duration, beat func()) {
+	for {
+		beat()
+		if _, err := e.ExpireOnce(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("releasing expired holds failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
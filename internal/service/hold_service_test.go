@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+// memoryHoldRepo writes hold.released events to outbox, if set, for the
+// holds it removes, as the database repository does.
+type memoryHoldRepo struct {
+	holds    map[uint]*domain.SlotHold
+	nextID   uint
+	released []uint
+	outbox   *memoryOutbox
+}
+
+func (m *memoryHoldRepo) Create(ctx context.Context, hold *domain.SlotHold, maxActive int) error {
+	if m.holds == nil {
+		m.holds = map[uint]*domain.SlotHold{}
+	}
+	active := 0
+	for _, h := range m.holds {
+		if h.UserID == hold.UserID {
+			active++
+		}
+	}
+	if active >= maxActive {
+		return domain.NewConflictError("too many holds")
+	}
+	m.nextID++
+	hold.ID = m.nextID
+	m.holds[hold.ID] = hold
+	return nil
+}
+
+func (m *memoryHoldRepo) GetByID(ctx context.Context, id uint) (*domain.SlotHold, error) {
+	if h, ok := m.holds[id]; ok {
+		return h, nil
+	}
+	return nil, domain.NewNotFoundError("hold not found")
+}
+
+func (m *memoryHoldRepo) ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]domain.SlotHold, error) {
+	var res []domain.SlotHold
+	for _, h := range m.holds {
+		if h.UserID == userID && h.ExpiresAt.After(now) {
+			res = append(res, *h)
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryHoldRepo) Release(ctx context.Context, id uint) error {
+	if h, ok := m.holds[id]; ok {
+		m.outbox.appendHold(h)
+	}
+	delete(m.holds, id)
+	m.released = append(m.released, id)
+	return nil
+}
+
+func (m *memoryHoldRepo) DeleteExpired(ctx context.Context, now time.Time, limit int) (int64, error) {
+	var n int64
+	for id, h := range m.holds {
+		if int(n) == limit {
+			break
+		}
+		if !h.ExpiresAt.After(now) {
+			m.outbox.appendHold(h)
+			delete(m.holds, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func newTestHolds(repo *memoryHoldRepo, bookings *mockBookingRepo) port.HoldService {
+	fields := newFieldRepoWith(1)
+	clock := fixedClock(testNow)
+	return NewHoldService(repo, NewBookingService(bookings, fields, clock), fields, clock, 10*time.Minute)
+}
+
+func TestHoldService_PlaceHold(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryHoldRepo{}
+	svc := newTestHolds(repo, &mockBookingRepo{})
+	start := testNow.Add(3 * time.Hour)
+	end := start.Add(time.Hour)
+
+	for _, tc := range []struct {
+		name string
+		req  port.HoldRequest
+	}{
+		{"too long", port.HoldRequest{FieldID: 1, StartTime: start, EndTime: end, Minutes: 11}},
+		{"negative", port.HoldRequest{FieldID: 1, StartTime: start, EndTime: end, Minutes: -1}},
+		{"end before start", port.HoldRequest{FieldID: 1, StartTime: end, EndTime: start}},
+		{"already begun", port.HoldRequest{FieldID: 1, StartTime: testNow, EndTime: end}},
+	} {
+		if _, err := svc.PlaceHold(ctx, 10, &tc.req); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected a validation error, got %v", tc.name, err)
+		}
+	}
+	if _, err := svc.PlaceHold(ctx, 10, &port.HoldRequest{FieldID: 9, StartTime: start, EndTime: end}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected unknown field to be not found, got %v", err)
+	}
+
+	hold, err := svc.PlaceHold(ctx, 10, &port.HoldRequest{FieldID: 1, StartTime: start.In(time.FixedZone("WIB", 7*3600)), EndTime: end, Minutes: 5})
+	if err != nil {
+		t.Fatalf("place: %v", err)
+	}
+	if !hold.ExpiresAt.Equal(testNow.Add(5*time.Minute)) || hold.StartTime.Location() != time.UTC || hold.UserID != 10 {
+		t.Fatalf("unexpected hold %+v", hold)
+	}
+	hold, _ = svc.PlaceHold(ctx, 10, &port.HoldRequest{FieldID: 1, StartTime: end, EndTime: end.Add(time.Hour)})
+	if !hold.ExpiresAt.Equal(testNow.Add(10 * time.Minute)) {
+		t.Fatalf("expected the maximum hold by default, got %s", hold.ExpiresAt)
+	}
+
+	// A hold never outlasts the start of its slot.
+	soon := testNow.Add(4 * time.Minute)
+	hold, _ = svc.PlaceHold(ctx, 10, &port.HoldRequest{FieldID: 1, StartTime: soon, EndTime: soon.Add(time.Hour)})
+	if !hold.ExpiresAt.Equal(soon) {
+		t.Fatalf("expected the hold to end when the slot starts, got %s", hold.ExpiresAt)
+	}
+
+	if _, err := svc.PlaceHold(ctx, 10, &port.HoldRequest{FieldID: 1, StartTime: start.Add(24 * time.Hour), EndTime: end.Add(24 * time.Hour)}); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected a fourth hold to be refused, got %v", err)
+	}
+}
+
+func TestHoldService_Checkout(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryHoldRepo{}
+	bookings := &mockBookingRepo{}
+	svc := newTestHolds(repo, bookings)
+	start := testNow.Add(3 * time.Hour)
+
+	hold, _ := svc.PlaceHold(ctx, 10, &port.HoldRequest{FieldID: 1, StartTime: start, EndTime: start.Add(time.Hour)})
+
+	if _, err := svc.Checkout(ctx, 11, hold.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected another customer's hold to be not found, got %v", err)
+	}
+	if err := svc.ReleaseHold(ctx, 11, hold.ID); !errors.Is(err, domain.ErrNotFound) || len(repo.released) != 0 {
+		t.Fatalf("expected another customer's hold to be not found, got %v", err)
+	}
+
+	booking, err := svc.Checkout(ctx, 10, hold.ID)
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if booking.UserID != 10 || !booking.StartTime.Equal(start) || booking.Status != domain.BookingStatusPending || len(bookings.created) != 1 {
+		t.Fatalf("unexpected booking %+v", booking)
+	}
+
+	expired := testNow.Add(-time.Second)
+	repo.holds[hold.ID].ExpiresAt = expired
+	if _, err := svc.Checkout(ctx, 10, hold.ID); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected an expired hold to conflict, got %v", err)
+	}
+
+	if err := svc.ReleaseHold(ctx, 10, hold.ID); err != nil || len(repo.released) != 1 {
+		t.Fatalf("release: %v", err)
+	}
+}
+
+func TestSlotHoldExpirer_ExpireOnce(t *testing.T) {
+	repo := &memoryHoldRepo{holds: map[uint]*domain.SlotHold{}}
+	for i := uint(1); i <= expireBatchSize+5; i++ {
+		repo.holds[i] = &domain.SlotHold{ID: i, ExpiresAt: testNow.Add(-time.Minute)}
+	}
+	repo.holds[1000] = &domain.SlotHold{ID: 1000, ExpiresAt: testNow.Add(time.Minute)}
+
+	e := NewSlotHoldExpirer(repo, fixedClock(testNow))
+	n, err := e.ExpireOnce(context.Background())
+	if err != nil || n != expireBatchSize+5 {
+		t.Fatalf("expected %d holds released, got %d (%v)", expireBatchSize+5, n, err)
+	}
+	if len(repo.holds) != 1 || repo.holds[1000] == nil {
+		t.Fatalf("expected only the unexpired hold left, got %d", len(repo.holds))
+	}
+}
+
+func TestBookingService_GetAvailability(t *testing.T) {
+	ctx := context.Background()
+	fields := scheduledFieldRepo("Asia/Jakarta", "08:00", "22:00")
+	// 18:00-19:30 WIB on Monday 2 June.
+	bookings := &mockBookingRepo{byID: map[uint]*domain.Booking{
+		1: {FieldID: 1, StartTime: time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC), Status: domain.BookingStatusPaid},
+		2: {FieldID: 1, StartTime: time.Date(2025, 6, 2, 13, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 6, 2, 14, 0, 0, 0, time.UTC), Status: domain.BookingStatusCancelled},
+	}}
+	svc := NewBookingService(bookings, fields, fixedClock(testNow))
+
+	a, err := svc.GetAvailability(ctx, 1, "")
+	if err != nil {
+		t.Fatalf("availability: %v", err)
+	}
+	if a.Date != "2025-06-02" || a.TimeZone != "Asia/Jakarta" ||
+		!a.OpensAt.Equal(time.Date(2025, 6, 2, 1, 0, 0, 0, time.UTC)) || !a.ClosesAt.Equal(time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected day %+v", a)
+	}
+	if len(a.Busy) != 1 || a.Busy[0].Kind != domain.BusyBooking {
+		t.Fatalf("expected only the paid booking busy, got %+v", a.Busy)
+	}
+	if len(a.Free) != 2 || !a.Free[0].EndTime.Equal(a.Busy[0].StartTime) || !a.Free[1].StartTime.Equal(a.Busy[0].EndTime) {
+		t.Fatalf("expected free time either side of the booking, got %+v", a.Free)
+	}
+
+	a, _ = svc.GetAvailability(ctx, 1, "2025-06-03")
+	if len(a.Busy) != 0 || len(a.Free) != 1 || !a.OpensAt.Equal(time.Date(2025, 6, 3, 1, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the next day free, got %+v", a)
+	}
+	if _, err := svc.GetAvailability(ctx, 1, "3 June"); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
+
+func TestFreeRanges(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 6, 2, h, 0, 0, 0, time.UTC) }
+	busy := func(from, to int, kind string) domain.BusySlot {
+		return domain.BusySlot{TimeRange: domain.TimeRange{StartTime: at(from), EndTime: at(to)}, Kind: kind}
+	}
+
+	// Overlapping, unsorted, and sticking out past closing time.
+	free := domain.FreeRanges(at(8), at(22), []domain.BusySlot{
+		busy(20, 23, domain.BusyBooking), busy(10, 12, domain.BusyHold), busy(11, 13, domain.BusyBooking),
+	})
+	want := []domain.TimeRange{{StartTime: at(8), EndTime: at(10)}, {StartTime: at(13), EndTime: at(20)}}
+	if len(free) != len(want) {
+		t.Fatalf("expected %v, got %v", want, free)
+	}
+	for i := range want {
+		if !free[i].StartTime.Equal(want[i].StartTime) || !free[i].EndTime.Equal(want[i].EndTime) {
+			t.Fatalf("expected %v, got %v", want, free)
+		}
+	}
+	if free := domain.FreeRanges(at(8), at(22), []domain.BusySlot{busy(6, 23, domain.BusyHold)}); len(free) != 0 {
+		t.Fatalf("expected nothing free, got %v", free)
+	}
+}
@@ -0,0 +1,478 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// maxImportRows bounds each import file; every booking row is checked
+// against the database.
+const maxImportRows = 10000
+
+// Import file columns. Fields take the same values as CreateFieldRequest.
+// A booking names its field by field_id, or by field_name for a field that
+// already exists or is in the same import.
+var (
+	importFieldColumns    = []string{"name", "price_per_hour", "location", "time_zone", "open_time", "close_time"}
+	importFieldRequired   = []string{"name", "price_per_hour"}
+	importBookingColumns  = []string{"field_id", "field_name", "user_email", "start_time", "end_time", "status", "amount_paid", "payment_reference"}
+	importBookingRequired = []string{"user_email", "start_time", "end_time"}
+)
+
+// importStatuses are the statuses an imported booking may have; pending
+// bookings would expire before anyone could pay for them.
+var importStatuses = map[string]bool{
+	domain.BookingStatusPaid:      true,
+	domain.BookingStatusCancelled: true,
+	domain.BookingStatusNoShow:    true,
+}
+
+type ImportServiceImpl struct {
+	repo        port.ImportRepository
+	bookingRepo port.BookingRepository
+	fieldRepo   port.FieldRepository
+	userRepo    port.UserRepository
+	clock       port.Clock
+	// defaultTimeZone is given to fields imported without a time zone.
+	defaultTimeZone string
+}
+
+func NewImportService(repo port.ImportRepository, bookingRepo port.BookingRepository, fieldRepo port.FieldRepository,
+	userRepo port.UserRepository, clock port.Clock, defaultTimeZone string) port.ImportService {
+	return &ImportServiceImpl{repo: repo, bookingRepo: bookingRepo, fieldRepo: fieldRepo, userRepo: userRepo,
+		clock: clock, defaultTimeZone: defaultTimeZone}
+}
+
+// importRun collects what one import would create and what is wrong with it.
+type importRun struct {
+	result   *domain.ImportResult
+	fields   []domain.Field
+	bookings []domain.Booking
+	// byID and byName find the current fields, and byName the new ones too.
+	byID   map[uint]*domain.Field
+	byName map[string][]*domain.Field
+	users  map[string]uint
+	// taken holds the slots occupied so far by each field's bookings.
+	taken map[*domain.Field][]importedSlot
+}
+
+type importedSlot struct {
+	line       int
+	start, end time.Time
+}
+
+func (r *importRun) fail(file string, line int, column, message string) {
+	r.result.Errors = append(r.result.Errors, domain.ImportError{File: file, Row: line, Column: column, Message: message})
+}
+
+// csvRow is a data row of an import file, by column name.
+type csvRow struct {
+	line   int
+	values map[string]string
+}
+
+func (s *ImportServiceImpl) Import(ctx context.Context, req *port.ImportRequest) (*domain.ImportResult, error) {
+	if req.Fields == nil && req.Bookings == nil {
+		return nil, domain.NewValidationError("nothing to import; give a fields or a bookings file")
+	}
+
+	existing, err := s.fieldRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	run := &importRun{
+		result: &domain.ImportResult{DryRun: req.DryRun, Errors: []domain.ImportError{}},
+		byID:   map[uint]*domain.Field{},
+		byName: map[string][]*domain.Field{},
+		users:  map[string]uint{},
+		taken:  map[*domain.Field][]importedSlot{},
+	}
+	for i := range existing {
+		f := &existing[i]
+		run.byID[f.ID] = f
+		run.byName[nameKey(f.Name)] = append(run.byName[nameKey(f.Name)], f)
+	}
+
+	if req.Fields != nil {
+		rows := readImportFile(run, domain.ImportFileFields, req.Fields, importFieldColumns, importFieldRequired)
+		for _, row := range rows {
+			s.importField(run, row)
+		}
+	}
+	// Bookings point into run.fields, which must not grow from here on.
+	for i := range run.fields {
+		f := &run.fields[i]
+		run.byName[nameKey(f.Name)] = append(run.byName[nameKey(f.Name)], f)
+	}
+	if req.Bookings != nil {
+		rows := readImportFile(run, domain.ImportFileBookings, req.Bookings, importBookingColumns, importBookingRequired)
+		for _, row := range rows {
+			if err := s.importBooking(ctx, run, row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Rows too short to check are reported as the file is read, ahead of
+	// the rows before them.
+	result := run.result
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		a, b := result.Errors[i], result.Errors[j]
+		if a.File != b.File {
+			return a.File == domain.ImportFileFields
+		}
+		return a.Row < b.Row
+	})
+	result.Fields, result.Bookings = len(run.fields), len(run.bookings)
+	if len(result.Errors) > 0 || req.DryRun {
+		return result, nil
+	}
+	if err := s.repo.Import(ctx, run.fields, run.bookings); err != nil {
+		return nil, err
+	}
+	result.Imported = true
+	logging.FromContext(ctx).Info("import complete", "fields", result.Fields, "bookings", result.Bookings)
+	return result, nil
+}
+
+// readImportFile reads the data rows of a CSV file whose header names
+// columns, reporting problems with the file or its rows in run. A file
+// whose header is wrong yields no rows.
+func readImportFile(run *importRun, file string, r io.Reader, columns, required []string) []csvRow {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		run.fail(file, 0, "", "the file is empty")
+		return nil
+	}
+	if err != nil {
+		run.fail(file, 1, "", csvError(err))
+		return nil
+	}
+
+	failures := len(run.result.Errors)
+	known := map[string]bool{}
+	for _, name := range columns {
+		known[name] = true
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		// Spreadsheets often save CSV with a byte order mark.
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch _, seen := index[name]; {
+		case !known[name]:
+			run.fail(file, 1, name, "unknown column; the columns are "+strings.Join(columns, ", "))
+		case seen:
+			run.fail(file, 1, name, "the column appears twice")
+		default:
+			index[name] = i
+		}
+	}
+	for _, name := range required {
+		if _, ok := index[name]; !ok {
+			run.fail(file, 1, name, "the column is required")
+		}
+	}
+	if file == domain.ImportFileBookings {
+		_, byID := index["field_id"]
+		_, byName := index["field_name"]
+		if !byID && !byName {
+			run.fail(file, 1, "field_id", "a field_id or field_name column is required")
+		}
+	}
+	if len(run.result.Errors) > failures {
+		return nil
+	}
+
+	var rows []csvRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows
+		}
+		if err != nil {
+			// The rest of a malformed file cannot be trusted.
+			var parseErr *csv.ParseError
+			line := 0
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+			}
+			run.fail(file, line, "", csvError(err))
+			return rows
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == maxImportRows {
+			run.fail(file, line, "", fmt.Sprintf("the file has more than %d rows; split it into several imports", maxImportRows))
+			return rows
+		}
+		if len(record) != len(header) {
+			run.fail(file, line, "", fmt.Sprintf("the row has %d columns but the header has %d", len(record), len(header)))
+			continue
+		}
+		values := make(map[string]string, len(index))
+		for name, i := range index {
+			values[name] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, csvRow{line: line, values: values})
+	}
+}
+
+func csvError(err error) string {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return "the file is not valid CSV: " + parseErr.Err.Error()
+	}
+	return "the file could not be read: " + err.Error()
+}
+
+// nameKey matches field names regardless of case and surrounding spaces.
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// importField validates a field row as CreateField does.
+func (s *ImportServiceImpl) importField(run *importRun, row csvRow) {
+	fail := func(column, message string) { run.fail(domain.ImportFileFields, row.line, column, message) }
+
+	price, err := strconv.Atoi(row.values["price_per_hour"])
+	if err != nil {
+		fail("price_per_hour", "price_per_hour must be a whole number")
+		return
+	}
+	req := &port.CreateFieldRequest{
+		Name:         row.values["name"],
+		PricePerHour: price,
+		Location:     row.values["location"],
+		TimeZone:     row.values["time_zone"],
+		OpenTime:     row.values["open_time"],
+		CloseTime:    row.values["close_time"],
+	}
+	if err := validateFieldRequest(req); err != nil {
+		fail("", err.Error())
+		return
+	}
+	field := domain.Field{
+		Name:         req.Name,
+		PricePerHour: req.PricePerHour,
+		Location:     req.Location,
+		TimeZone:     s.defaultTimeZone,
+		OpenTime:     domain.DefaultOpenTime,
+		CloseTime:    domain.DefaultCloseTime,
+	}
+	applySchedule(&field, req)
+	if err := validateSchedule(&field); err != nil {
+		fail("", err.Error())
+		return
+	}
+	// Names identify fields in the bookings file, and running an import
+	// twice must not create every field twice.
+	if len(run.byName[nameKey(field.Name)]) > 0 {
+		fail("name", fmt.Sprintf("a field named %q already exists", field.Name))
+		return
+	}
+	for _, f := range run.fields {
+		if nameKey(f.Name) == nameKey(field.Name) {
+			fail("name", fmt.Sprintf("the field %q is in the file twice", field.Name))
+			return
+		}
+	}
+	run.fields = append(run.fields, field)
+}
+
+// importBooking validates a booking row with the rules CreateBooking
+// applies, except that it may be in the past, and adds it to run. Only
+// unexpected failures are returned.
+func (s *ImportServiceImpl) importBooking(ctx context.Context, run *importRun, row csvRow) error {
+	fail := func(column, message string) { run.fail(domain.ImportFileBookings, row.line, column, message) }
+
+	field, ok := run.bookingField(row, fail)
+	if !ok {
+		return nil
+	}
+	userID, err := s.importUser(ctx, run, row.values["user_email"])
+	if errors.Is(err, domain.ErrNotFound) {
+		fail("user_email", fmt.Sprintf("no user has the email %q", row.values["user_email"]))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	loc, err := field.Zone()
+	if err != nil {
+		return fmt.Errorf("field %d has an invalid time zone: %w", field.ID, err)
+	}
+	start, err := parseImportTime(row.values["start_time"], loc)
+	if err != nil {
+		fail("start_time", "start_time must be RFC 3339 or YYYY-MM-DD HH:MM in the field's time zone")
+		return nil
+	}
+	end, err := parseImportTime(row.values["end_time"], loc)
+	if err != nil {
+		fail("end_time", "end_time must be RFC 3339 or YYYY-MM-DD HH:MM in the field's time zone")
+		return nil
+	}
+	if !end.After(start) {
+		fail("", "start time must be before end time")
+		return nil
+	}
+	if err := checkOpeningHours(field, start, end); err != nil {
+		if !errors.Is(err, domain.ErrValidation) {
+			return err
+		}
+		fail("", err.Error())
+		return nil
+	}
+
+	booking := domain.Booking{UserID: userID, StartTime: start, EndTime: end, Status: row.values["status"]}
+	if booking.Status == "" {
+		booking.Status = domain.BookingStatusPaid
+	}
+	if !importStatuses[booking.Status] {
+		fail("status", "status must be paid, cancelled or no_show")
+		return nil
+	}
+	if booking.Status == domain.BookingStatusNoShow && start.After(s.clock.Now()) {
+		fail("status", "a booking that has not started yet cannot be a no_show")
+		return nil
+	}
+	if !s.importPayment(&booking, field, row, fail) {
+		return nil
+	}
+	// The file does not say when a booking reached its status; the start is
+	// when it had at the latest.
+	at := start
+	switch booking.Status {
+	case domain.BookingStatusPaid:
+		booking.PaidAt = &at
+	case domain.BookingStatusNoShow:
+		booking.PaidAt, booking.NoShowAt = &at, &at
+	case domain.BookingStatusCancelled:
+		booking.CancelledAt = &at
+	}
+
+	// Paid bookings and no-shows keep their slot.
+	if booking.Status != domain.BookingStatusCancelled {
+		for _, slot := range run.taken[field] {
+			if slot.start.Before(end) && slot.end.After(start) {
+				fail("", fmt.Sprintf("overlaps the booking on line %d", slot.line))
+				return nil
+			}
+		}
+		if field.ID != 0 {
+			isBooked, err := s.bookingRepo.CheckAvailability(ctx, field.ID, userID, start, end)
+			if err != nil {
+				return err
+			}
+			if isBooked {
+				fail("", "field is already booked or held at this time")
+				return nil
+			}
+		}
+		run.taken[field] = append(run.taken[field], importedSlot{line: row.line, start: start, end: end})
+	}
+
+	if field.ID == 0 {
+		booking.Field = field
+	} else {
+		booking.FieldID = field.ID
+	}
+	run.bookings = append(run.bookings, booking)
+	return nil
+}
+
+// bookingField finds the field a booking row names.
+func (r *importRun) bookingField(row csvRow, fail func(column, message string)) (*domain.Field, bool) {
+	id, name := row.values["field_id"], row.values["field_name"]
+	switch {
+	case id != "" && name != "":
+		fail("field_id", "give field_id or field_name, not both")
+	case id != "":
+		n, err := strconv.ParseUint(id, 10, 64)
+		if err != nil || n == 0 {
+			fail("field_id", "field_id must be a field's ID")
+			break
+		}
+		// Deleted fields are not listed, so this also rejects them.
+		if field, ok := r.byID[uint(n)]; ok {
+			return field, true
+		}
+		fail("field_id", fmt.Sprintf("no field has the ID %d", n))
+	case name != "":
+		switch matches := r.byName[nameKey(name)]; len(matches) {
+		case 0:
+			fail("field_name", fmt.Sprintf("no field is named %q", name))
+		case 1:
+			return matches[0], true
+		default:
+			fail("field_name", fmt.Sprintf("%d fields are named %q; use field_id", len(matches), name))
+		}
+	default:
+		fail("field_id", "field_id or field_name is required")
+	}
+	return nil, false
+}
+
+func (s *ImportServiceImpl) importUser(ctx context.Context, run *importRun, email string) (uint, error) {
+	if id, ok := run.users[email]; ok {
+		return id, nil
+	}
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+	run.users[email] = user.ID
+	return user.ID, nil
+}
+
+// importPayment sets what a paid or no-show booking was paid: amount_paid,
+// or its field's price as PayBooking charges it.
+func (s *ImportServiceImpl) importPayment(booking *domain.Booking, field *domain.Field, row csvRow, fail func(column, message string)) bool {
+	reference, amount := row.values["payment_reference"], row.values["amount_paid"]
+	if len(reference) > maxPaymentReference {
+		fail("payment_reference", fmt.Sprintf("payment_reference must be at most %d characters", maxPaymentReference))
+		return false
+	}
+	booking.PaymentReference = reference
+	if booking.Status == domain.BookingStatusCancelled {
+		if amount != "" {
+			fail("amount_paid", "a cancelled booking has no amount_paid")
+			return false
+		}
+		return true
+	}
+
+	paid := field.Price(booking.StartTime, booking.EndTime)
+	if amount != "" {
+		n, err := strconv.ParseInt(amount, 10, 64)
+		if err != nil || n < 0 {
+			fail("amount_paid", "amount_paid must be a whole number, zero or more")
+			return false
+		}
+		paid = n
+	}
+	booking.AmountPaid = &paid
+	return true
+}
+
+// parseImportTime reads an RFC 3339 time, or a wall-clock time in loc as
+// spreadsheets write them, and returns it in UTC.
+func parseImportTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+	return t.UTC(), err
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+type mockImportRepo struct {
+	fields   []domain.Field
+	bookings []domain.Booking
+	calls    int
+}
+
+func (m *mockImportRepo) Import(ctx context.Context, fields []domain.Field, bookings []domain.Booking) error {
+	m.calls++
+	for i := range fields {
+		fields[i].ID = uint(100 + i)
+	}
+	for i := range bookings {
+		if bookings[i].Field != nil {
+			bookings[i].FieldID = bookings[i].Field.ID
+		}
+	}
+	m.fields, m.bookings = fields, bookings
+	return nil
+}
+
+func newTestImport(repo *mockImportRepo, bookings *mockBookingRepo) port.ImportService {
+	return NewImportService(repo, bookings, courtARepo(), budiRepo(), fixedClock(testNow), "Asia/Jakarta")
+}
+
+func TestImportService_DryRunAndCommit(t *testing.T) {
+	fieldsCSV := "\ufeffName,price_per_hour,location,open_time,close_time\n" +
+		"Court B,120000,Senayan,08:00,22:00\n"
+	bookingsCSV := "field_name,field_id,user_email,start_time,end_time,status,amount_paid,payment_reference\n" +
+		"court b,,budi@example.com,2025-05-20 18:00,2025-05-20 19:30,,,TRX-1\n" +
+		",1,budi@example.com,2025-05-20T11:00:00Z,2025-05-20T12:00:00Z,no_show,90000,\n" +
+		"Court A,,budi@example.com,2025-06-10 07:00,2025-06-10 08:00,cancelled,,\n"
+
+	repo := &mockImportRepo{}
+	svc := newTestImport(repo, &mockBookingRepo{})
+
+	result, err := svc.Import(context.Background(), &port.ImportRequest{
+		Fields: strings.NewReader(fieldsCSV), Bookings: strings.NewReader(bookingsCSV), DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(result.Errors) != 0 || result.Fields != 1 || result.Bookings != 3 || result.Imported || repo.calls != 0 {
+		t.Fatalf("unexpected dry run %+v", result)
+	}
+
+	result, err = svc.Import(context.Background(), &port.ImportRequest{
+		Fields: strings.NewReader(fieldsCSV), Bookings: strings.NewReader(bookingsCSV),
+	})
+	if err != nil || !result.Imported || repo.calls != 1 {
+		t.Fatalf("expected an import, got %+v, %v", result, err)
+	}
+
+	field := repo.fields[0]
+	if field.Name != "Court B" || field.Location != "Senayan" || field.TimeZone != "Asia/Jakarta" || field.OpenTime != "08:00" {
+		t.Fatalf("unexpected field %+v", field)
+	}
+	paid := repo.bookings[0]
+	if paid.FieldID != 100 || paid.UserID != 7 || paid.Status != domain.BookingStatusPaid || paid.PaymentReference != "TRX-1" {
+		t.Fatalf("unexpected paid booking %+v", paid)
+	}
+	if !paid.StartTime.Equal(time.Date(2025, 5, 20, 11, 0, 0, 0, time.UTC)) || paid.StartTime.Location() != time.UTC {
+		t.Fatalf("expected the Jakarta wall-clock time in UTC, got %v", paid.StartTime)
+	}
+	if paid.AmountPaid == nil || *paid.AmountPaid != 180000 {
+		t.Fatalf("expected 90 minutes at the field's price, got %v", paid.AmountPaid)
+	}
+	if paid.PaidAt == nil || !paid.PaidAt.Equal(paid.StartTime) {
+		t.Fatalf("expected the booking paid by its start, got %v", paid.PaidAt)
+	}
+	noShow := repo.bookings[1]
+	if noShow.FieldID != 1 || noShow.Field != nil || *noShow.AmountPaid != 90000 {
+		t.Fatalf("unexpected no-show booking %+v", noShow)
+	}
+	if noShow.PaidAt == nil || noShow.NoShowAt == nil || !noShow.NoShowAt.Equal(noShow.StartTime) {
+		t.Fatalf("expected a no-show paid and marked by its start, got %+v", noShow)
+	}
+	cancelled := repo.bookings[2]
+	if cancelled.Status != domain.BookingStatusCancelled || cancelled.AmountPaid != nil ||
+		cancelled.CancelledAt == nil || cancelled.PaidAt != nil {
+		t.Fatalf("unexpected cancelled booking %+v", cancelled)
+	}
+}
+
+func TestImportService_RowErrors(t *testing.T) {
+	fieldsCSV := "name,price_per_hour\n" +
+		"Court A,100000\n" +
+		"Court C,free\n" +
+		"Court D,50000\n" +
+		"court d,60000\n"
+	bookingsCSV := "field_name,user_email,start_time,end_time,status\n" +
+		"Court D,budi@example.com,2025-05-20 18:00,2025-05-20 19:00,\n" +
+		"Court D,budi@example.com,2025-05-20 18:30,2025-05-20 19:30,paid\n" +
+		"Court A,budi@example.com,2025-05-20 05:00,2025-05-20 07:00,\n" +
+		"Court A,nobody@example.com,2025-05-21 18:00,2025-05-21 19:00,\n" +
+		"Court A,budi@example.com,2025-06-20 18:00,2025-06-20 19:00,no_show\n" +
+		"Court A,budi@example.com,2025-05-22 18:00,2025-05-22 19:00,pending\n" +
+		"Court Z,budi@example.com,2025-05-22 18:00,2025-05-22 19:00,\n" +
+		"Court A,budi@example.com,yesterday,2025-05-22 19:00,\n" +
+		"Court A,budi@example.com\n" +
+		"Court D,budi@example.com,2025-05-20 18:30,2025-05-20 19:00,no_show\n"
+
+	repo := &mockImportRepo{}
+	svc := newTestImport(repo, &mockBookingRepo{})
+	result, err := svc.Import(context.Background(), &port.ImportRequest{
+		Fields: strings.NewReader(fieldsCSV), Bookings: strings.NewReader(bookingsCSV),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Imported || repo.calls != 0 {
+		t.Fatalf("nothing may be imported when a row is invalid")
+	}
+
+	want := []struct {
+		file   string
+		row    int
+		column string
+		text   string
+	}{
+		{"fields", 2, "name", "already exists"},
+		{"fields", 3, "price_per_hour", "whole number"},
+		{"fields", 5, "name", "in the file twice"},
+		{"bookings", 3, "", "overlaps the booking on line 2"},
+		{"bookings", 4, "", "open 06:00-23:00"},
+		{"bookings", 5, "user_email", "no user"},
+		{"bookings", 6, "status", "not started"},
+		{"bookings", 7, "status", "paid, cancelled or no_show"},
+		{"bookings", 8, "field_name", "no field is named"},
+		{"bookings", 9, "start_time", "RFC 3339"},
+		{"bookings", 10, "", "2 columns"},
+		{"bookings", 11, "", "overlaps the booking on line 2"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), result.Errors)
+	}
+	for i, w := range want {
+		got := result.Errors[i]
+		if got.File != w.file || got.Row != w.row || got.Column != w.column || !strings.Contains(got.Message, w.text) {
+			t.Errorf("error %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+func TestImportService_ExistingBookingsAndHeaders(t *testing.T) {
+	svc := newTestImport(&mockImportRepo{}, &mockBookingRepo{avail: map[uint]bool{1: true}})
+	result, err := svc.Import(context.Background(), &port.ImportRequest{
+		Bookings: strings.NewReader("field_id,user_email,start_time,end_time\n1,budi@example.com,2025-05-20 18:00,2025-05-20 19:00\n"),
+	})
+	if err != nil || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "already booked") {
+		t.Fatalf("expected an overlap with an existing booking, got %+v, %v", result, err)
+	}
+
+	result, _ = svc.Import(context.Background(), &port.ImportRequest{
+		Bookings: strings.NewReader("user_email,start_time,end_time,colour\nbudi@example.com,a,b,red\n"),
+	})
+	if len(result.Errors) != 2 || result.Errors[0].Column != "colour" || result.Errors[1].Column != "field_id" || result.Bookings != 0 {
+		t.Fatalf("expected header errors only, got %+v", result.Errors)
+	}
+
+	result, _ = svc.Import(context.Background(), &port.ImportRequest{Fields: strings.NewReader("")})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "the file is empty" {
+		t.Fatalf("expected an empty file error, got %+v", result.Errors)
+	}
+
+	if _, err := svc.Import(context.Background(), &port.ImportRequest{}); err == nil {
+		t.Fatalf("expected an error without files")
+	}
+}
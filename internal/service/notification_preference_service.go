@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+type NotificationPreferenceServiceImpl struct {
+	repo            port.NotificationPreferenceRepository
+	defaultLanguage string
+}
+
+func NewNotificationPreferenceService(repo port.NotificationPreferenceRepository, defaultLanguage string) port.NotificationPreferenceService {
+	return &NotificationPreferenceServiceImpl{repo: repo, defaultLanguage: defaultLanguage}
+}
+
+// GetPreference returns the defaults for users who never saved one.
+func (s *NotificationPreferenceServiceImpl) GetPreference(ctx context.Context, userID uint) (*domain.NotificationPreference, error) {
+	pref, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pref == nil {
+		pref = &domain.NotificationPreference{UserID: userID, Language: s.defaultLanguage}
+	}
+	return pref, nil
+}
+
+func (s *NotificationPreferenceServiceImpl) UpdatePreference(ctx context.Context, userID uint, req *port.NotificationPreferenceRequest) (*domain.NotificationPreference, error) {
+	lang := req.Language
+	if lang == "" {
+		lang = s.defaultLanguage
+	}
+	if lang != domain.LanguageIndonesian && lang != domain.LanguageEnglish {
+		return nil, domain.NewValidationError("language must be 'id' or 'en'")
+	}
+
+	pref := &domain.NotificationPreference{
+		UserID:                 userID,
+		Language:               lang,
+		OptOutNonTransactional: req.OptOutNonTransactional,
+	}
+	if err := s.repo.Save(ctx, pref); err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
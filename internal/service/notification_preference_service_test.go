@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+type mockPreferenceRepo struct {
+	prefs map[uint]*domain.NotificationPreference
+}
+
+func (m *mockPreferenceRepo) Get(ctx context.Context, userID uint) (*domain.NotificationPreference, error) {
+	return m.prefs[userID], nil
+}
+
+func (m *mockPreferenceRepo) Save(ctx context.Context, pref *domain.NotificationPreference) error {
+	m.prefs[pref.UserID] = pref
+	return nil
+}
+
+func TestNotificationPreferenceService(t *testing.T) {
+	repo := &mockPreferenceRepo{prefs: map[uint]*domain.NotificationPreference{}}
+	svc := NewNotificationPreferenceService(repo, domain.LanguageIndonesian)
+	ctx := context.Background()
+
+	pref, err := svc.GetPreference(ctx, 7)
+	if err != nil || pref.Language != "id" || pref.OptOutNonTransactional {
+		t.Fatalf("expected defaults, got %+v %v", pref, err)
+	}
+
+	if _, err := svc.UpdatePreference(ctx, 7, &port.NotificationPreferenceRequest{Language: "fr"}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+
+	if _, err := svc.UpdatePreference(ctx, 7, &port.NotificationPreferenceRequest{Language: "en", OptOutNonTransactional: true}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	pref, _ = svc.GetPreference(ctx, 7)
+	if pref.Language != "en" || !pref.OptOutNonTransactional {
+		t.Fatalf("preference not saved: %+v", pref)
+	}
+
+	// an empty language falls back to the default
+	pref, _ = svc.UpdatePreference(ctx, 7, &port.NotificationPreferenceRequest{})
+	if pref.Language != "id" {
+		t.Fatalf("expected default language, got %+v", pref)
+	}
+}
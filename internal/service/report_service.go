@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+const (
+	// defaultReportDays is how many days up to today a report covers when
+	// the admin gives no range; maxReportDays bounds the range given.
+	defaultReportDays = 30
+	maxReportDays     = 366
+
+	defaultTopCustomers = 10
+	maxTopCustomers     = 100
+)
+
+type ReportServiceImpl struct {
+	repo      port.ReportRepository
+	fieldRepo port.FieldRepository
+	clock     port.Clock
+	// loc decides what "today" is for the default range.
+	loc *time.Location
+}
+
+func NewReportService(repo port.ReportRepository, fieldRepo port.FieldRepository, clock port.Clock, loc *time.Location) port.ReportService {
+	return &ReportServiceImpl{repo: repo, fieldRepo: fieldRepo, clock: clock, loc: loc}
+}
+
+func (s *ReportServiceImpl) Revenue(ctx context.Context, q *port.ReportQuery, period, group string) ([]domain.RevenueRow, error) {
+	switch period {
+	case "":
+		period = domain.ReportPeriodDay
+	case domain.ReportPeriodDay, domain.ReportPeriodWeek, domain.ReportPeriodMonth:
+	default:
+		return nil, domain.NewValidationError("period must be day, week or month")
+	}
+	switch group {
+	case "":
+		group = domain.ReportGroupField
+	case domain.ReportGroupField, domain.ReportGroupVenue, domain.ReportGroupAll:
+	default:
+		return nil, domain.NewValidationError("group must be field, venue or all")
+	}
+	rng, err := s.parseRange(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.Revenue(ctx, rng, period, group)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []domain.RevenueRow{}
+	}
+	return rows, nil
+}
+
+// Utilization counts opening hours day by day in each field's own zone, so
+// days the clocks change are an hour longer or shorter.
+func (s *ReportServiceImpl) Utilization(ctx context.Context, q *port.ReportQuery) ([]domain.UtilizationRow, error) {
+	rng, err := s.parseRange(q)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	booked, err := s.repo.BookedHours(ctx, rng)
+	if err != nil {
+		return nil, err
+	}
+	hours := make(map[uint]float64, len(booked))
+	for _, b := range booked {
+		hours[b.FieldID] = b.Hours
+	}
+
+	rows := []domain.UtilizationRow{}
+	for i := range fields {
+		field := &fields[i]
+		if rng.FieldID != 0 && field.ID != rng.FieldID {
+			continue
+		}
+		loc, err := field.Zone()
+		if err != nil {
+			return nil, fmt.Errorf("field %d has an invalid time zone: %w", field.ID, err)
+		}
+		var open time.Duration
+		for d := rng.From; !d.After(rng.To); d = d.AddDate(0, 0, 1) {
+			opens, closes, err := field.OpeningHours(time.Date(d.Year(), d.Month(), d.Day(), 12, 0, 0, 0, loc))
+			if err != nil {
+				return nil, fmt.Errorf("field %d has invalid opening hours: %w", field.ID, err)
+			}
+			open += closes.Sub(opens)
+		}
+		row := domain.UtilizationRow{
+			FieldID:     field.ID,
+			FieldName:   field.Name,
+			BookedHours: hours[field.ID],
+			OpenHours:   open.Hours(),
+		}
+		row.UtilizationPct = percent(row.BookedHours, row.OpenHours)
+		rows = append(rows, row)
+	}
+	if rng.FieldID != 0 && len(rows) == 0 {
+		return nil, domain.NewNotFoundError("field not found")
+	}
+	return rows, nil
+}
+
+func (s *ReportServiceImpl) Heatmap(ctx context.Context, q *port.ReportQuery) ([]domain.HeatmapCell, error) {
+	rng, err := s.parseRange(q)
+	if err != nil {
+		return nil, err
+	}
+	counted, err := s.repo.Heatmap(ctx, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	cells := make([]domain.HeatmapCell, 0, 7*24)
+	for weekday := 1; weekday <= 7; weekday++ {
+		for hour := 0; hour < 24; hour++ {
+			cells = append(cells, domain.HeatmapCell{
+				Weekday: weekday,
+				Day:     time.Weekday(weekday % 7).String(),
+				Hour:    hour,
+			})
+		}
+	}
+	for _, c := range counted {
+		if c.Weekday >= 1 && c.Weekday <= 7 && c.Hour >= 0 && c.Hour < 24 {
+			cells[(c.Weekday-1)*24+c.Hour].Bookings = c.Bookings
+		}
+	}
+	return cells, nil
+}
+
+func (s *ReportServiceImpl) Cancellations(ctx context.Context, q *port.ReportQuery) ([]domain.CancellationRow, error) {
+	rng, err := s.parseRange(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.Outcomes(ctx, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	total := domain.CancellationRow{FieldName: "All fields"}
+	for i := range rows {
+		setRates(&rows[i])
+		total.Bookings += rows[i].Bookings
+		total.Paid += rows[i].Paid
+		total.Cancelled += rows[i].Cancelled
+		total.Expired += rows[i].Expired
+		total.NoShows += rows[i].NoShows
+	}
+	setRates(&total)
+	return append(rows, total), nil
+}
+
+// setRates fills in the row's cancellation rate, over all its bookings, and
+// no-show rate, over its confirmed ones.
+func setRates(row *domain.CancellationRow) {
+	row.CancellationRate = percent(float64(row.Cancelled), float64(row.Bookings))
+	row.NoShowRate = percent(float64(row.NoShows), float64(row.Paid+row.NoShows))
+}
+
+func (s *ReportServiceImpl) TopCustomers(ctx context.Context, q *port.ReportQuery, limit int) ([]domain.CustomerRow, error) {
+	if limit == 0 {
+		limit = defaultTopCustomers
+	}
+	if limit < 1 || limit > maxTopCustomers {
+		return nil, domain.NewValidationError(fmt.Sprintf("limit must be between 1 and %d", maxTopCustomers))
+	}
+	rng, err := s.parseRange(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.TopCustomers(ctx, rng, limit)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []domain.CustomerRow{}
+	}
+	return rows, nil
+}
+
+// parseRange validates the query's dates, defaulting to the last
+// defaultReportDays days up to today.
+func (s *ReportServiceImpl) parseRange(q *port.ReportQuery) (port.ReportRange, error) {
+	y, m, d := s.clock.Now().In(s.loc).Date()
+	rng := port.ReportRange{To: time.Date(y, m, d, 0, 0, 0, 0, time.UTC), FieldID: q.FieldID}
+
+	var err error
+	if q.To != "" {
+		if rng.To, err = time.Parse("2006-01-02", q.To); err != nil {
+			return rng, domain.NewValidationError("to must be in YYYY-MM-DD form")
+		}
+	}
+	rng.From = rng.To.AddDate(0, 0, 1-defaultReportDays)
+	if q.From != "" {
+		if rng.From, err = time.Parse("2006-01-02", q.From); err != nil {
+			return rng, domain.NewValidationError("from must be in YYYY-MM-DD form")
+		}
+	}
+	if rng.To.Before(rng.From) {
+		return rng, domain.NewValidationError("from must not be after to")
+	}
+	if !rng.To.Before(rng.From.AddDate(0, 0, maxReportDays)) {
+		return rng, domain.NewValidationError(fmt.Sprintf("a report covers at most %d days", maxReportDays))
+	}
+	return rng, nil
+}
+
+// percent returns part as a percentage of whole to one decimal place, 0
+// when whole is.
+func percent(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(part/whole*1000) / 10
+}
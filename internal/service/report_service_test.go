@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+type memoryReportRepo struct {
+	rng      port.ReportRange
+	period   string
+	group    string
+	limit    int
+	hours    []port.FieldHours
+	cells    []domain.HeatmapCell
+	outcomes []domain.CancellationRow
+}
+
+func (m *memoryReportRepo) Revenue(ctx context.Context, rng port.ReportRange, period, group string) ([]domain.RevenueRow, error) {
+	m.rng, m.period, m.group = rng, period, group
+	return nil, nil
+}
+
+func (m *memoryReportRepo) BookedHours(ctx context.Context, rng port.ReportRange) ([]port.FieldHours, error) {
+	m.rng = rng
+	return m.hours, nil
+}
+
+func (m *memoryReportRepo) Heatmap(ctx context.Context, rng port.ReportRange) ([]domain.HeatmapCell, error) {
+	m.rng = rng
+	return m.cells, nil
+}
+
+func (m *memoryReportRepo) Outcomes(ctx context.Context, rng port.ReportRange) ([]domain.CancellationRow, error) {
+	m.rng = rng
+	return m.outcomes, nil
+}
+
+func (m *memoryReportRepo) TopCustomers(ctx context.Context, rng port.ReportRange, limit int) ([]domain.CustomerRow, error) {
+	m.rng, m.limit = rng, limit
+	return nil, nil
+}
+
+func newTestReports(repo *memoryReportRepo, fields *mockFieldRepo) port.ReportService {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	return NewReportService(repo, fields, fixedClock(testNow), jakarta)
+}
+
+func day(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestReportService_Range(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryReportRepo{}
+	svc := newTestReports(repo, newFieldRepoWith(1))
+
+	rows, err := svc.Revenue(ctx, &port.ReportQuery{FieldID: 1}, "", "")
+	if err != nil || rows == nil {
+		t.Fatalf("revenue: %v", err)
+	}
+	if !repo.rng.To.Equal(day("2025-06-02")) || !repo.rng.From.Equal(day("2025-05-04")) || repo.rng.FieldID != 1 {
+		t.Fatalf("expected the last 30 days by default, got %+v", repo.rng)
+	}
+	if repo.period != domain.ReportPeriodDay || repo.group != domain.ReportGroupField {
+		t.Fatalf("unexpected defaults %q / %q", repo.period, repo.group)
+	}
+
+	if _, err := svc.Revenue(ctx, &port.ReportQuery{From: "2025-01-01", To: "2025-01-31"}, "month", "venue"); err != nil {
+		t.Fatalf("revenue: %v", err)
+	}
+	if !repo.rng.From.Equal(day("2025-01-01")) || !repo.rng.To.Equal(day("2025-01-31")) || repo.period != "month" {
+		t.Fatalf("unexpected range %+v", repo.rng)
+	}
+	if _, err := svc.Revenue(ctx, &port.ReportQuery{From: "2024-01-01", To: "2024-12-31"}, "", ""); err != nil {
+		t.Fatalf("expected a full leap year to be allowed, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		q      port.ReportQuery
+		period string
+		group  string
+	}{
+		{"bad from", port.ReportQuery{From: "1 June"}, "", ""},
+		{"bad to", port.ReportQuery{To: "2025-13-01"}, "", ""},
+		{"reversed", port.ReportQuery{From: "2025-06-02", To: "2025-06-01"}, "", ""},
+		{"too long", port.ReportQuery{From: "2024-01-01", To: "2025-01-01"}, "", ""},
+		{"bad period", port.ReportQuery{}, "year", ""},
+		{"bad group", port.ReportQuery{}, "", "city"},
+	} {
+		if _, err := svc.Revenue(ctx, &tc.q, tc.period, tc.group); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("%s: expected a validation error, got %v", tc.name, err)
+		}
+	}
+
+	if _, err := svc.TopCustomers(ctx, &port.ReportQuery{}, 0); err != nil || repo.limit != defaultTopCustomers {
+		t.Fatalf("expected the default limit, got %d (%v)", repo.limit, err)
+	}
+	if _, err := svc.TopCustomers(ctx, &port.ReportQuery{}, maxTopCustomers+1); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+}
+
+func TestReportService_Utilization(t *testing.T) {
+	ctx := context.Background()
+	// Open all day; the clocks went forward in London on 30 March 2025.
+	fields := scheduledFieldRepo("Europe/London", "", "")
+	repo := &memoryReportRepo{hours: []port.FieldHours{{FieldID: 1, Hours: 17.75}}}
+	svc := newTestReports(repo, fields)
+
+	rows, err := svc.Utilization(ctx, &port.ReportQuery{From: "2025-03-29", To: "2025-03-31"})
+	if err != nil {
+		t.Fatalf("utilization: %v", err)
+	}
+	if len(rows) != 1 || rows[0].OpenHours != 71 || rows[0].BookedHours != 17.75 || rows[0].UtilizationPct != 25 {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+
+	fields.byID[1].OpenTime, fields.byID[1].CloseTime = "08:00", "22:00"
+	rows, _ = svc.Utilization(ctx, &port.ReportQuery{From: "2025-03-29", To: "2025-03-31"})
+	if rows[0].OpenHours != 42 {
+		t.Fatalf("expected 14 open hours a day, got %v", rows[0].OpenHours)
+	}
+
+	if _, err := svc.Utilization(ctx, &port.ReportQuery{FieldID: 9}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected an unknown field to be not found, got %v", err)
+	}
+}
+
+func TestReportService_Heatmap(t *testing.T) {
+	repo := &memoryReportRepo{cells: []domain.HeatmapCell{{Weekday: 1, Hour: 18, Bookings: 4}, {Weekday: 7, Hour: 23, Bookings: 1}}}
+	svc := newTestReports(repo, newFieldRepoWith(1))
+
+	cells, err := svc.Heatmap(context.Background(), &port.ReportQuery{})
+	if err != nil || len(cells) != 7*24 {
+		t.Fatalf("expected every cell, got %d (%v)", len(cells), err)
+	}
+	if c := cells[18]; c.Day != "Monday" || c.Hour != 18 || c.Bookings != 4 {
+		t.Fatalf("unexpected Monday 18:00 %+v", c)
+	}
+	if c := cells[len(cells)-1]; c.Day != "Sunday" || c.Weekday != 7 || c.Bookings != 1 {
+		t.Fatalf("unexpected last cell %+v", c)
+	}
+	if cells[19].Bookings != 0 {
+		t.Fatalf("expected empty cells to be zero")
+	}
+}
+
+func TestReportService_Cancellations(t *testing.T) {
+	repo := &memoryReportRepo{outcomes: []domain.CancellationRow{
+		{FieldID: 1, FieldName: "A", Bookings: 10, Paid: 6, Cancelled: 2, Expired: 0, NoShows: 2},
+		{FieldID: 2, FieldName: "B", Bookings: 5, Paid: 0, Cancelled: 0, Expired: 5},
+	}}
+	svc := newTestReports(repo, newFieldRepoWith(1, 2))
+
+	rows, err := svc.Cancellations(context.Background(), &port.ReportQuery{})
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("expected two fields and a total, got %+v (%v)", rows, err)
+	}
+	if rows[0].CancellationRate != 20 || rows[0].NoShowRate != 25 || rows[1].NoShowRate != 0 {
+		t.Fatalf("unexpected rates %+v", rows[:2])
+	}
+	total := rows[2]
+	if total.FieldID != 0 || total.Bookings != 15 || total.Expired != 5 || total.CancellationRate != 13.3 || total.NoShowRate != 25 {
+		t.Fatalf("unexpected total %+v", total)
+	}
+}
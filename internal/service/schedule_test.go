@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+)
+
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func scheduledFieldRepo(zone, open, close string) *mockFieldRepo {
+	f := &domain.Field{Name: "F", TimeZone: zone, OpenTime: open, CloseTime: close}
+	f.ID = 1
+	return &mockFieldRepo{byID: map[uint]*domain.Field{1: f}}
+}
+
+// courtARepo has field 1, Court A, open 06:00-23:00 in Jakarta at 100000 an
+// hour.
+func courtARepo() *mockFieldRepo {
+	fields := scheduledFieldRepo("Asia/Jakarta", "06:00", "23:00")
+	fields.byID[1].Name, fields.byID[1].PricePerHour = "Court A", 100000
+	return fields
+}
+
+// budiRepo has one customer, Budi, user 7 at budi@example.com.
+func budiRepo() *mockUserRepo {
+	budi := &domain.User{Name: "Budi", Email: "budi@example.com"}
+	budi.ID = 7
+	return &mockUserRepo{users: map[string]*domain.User{budi.Email: budi}}
+}
+
+func book(svc port.BookingService, start, end time.Time) error {
+	_, err := svc.CreateBooking(context.Background(), 1, &port.BookingRequest{FieldID: 1, StartTime: start, EndTime: end})
+	return err
+}
+
+func TestCreateBooking_RejectsThePast(t *testing.T) {
+	svc := NewBookingService(&mockBookingRepo{}, newFieldRepoWith(1), fixedClock(testNow))
+
+	for _, start := range []time.Time{testNow.Add(-time.Hour), testNow} {
+		if err := book(svc, start, start.Add(time.Hour)); !errors.Is(err, domain.ErrValidation) {
+			t.Fatalf("start %s: expected validation error, got %v", start, err)
+		}
+	}
+	if err := book(svc, testNow.Add(time.Minute), testNow.Add(time.Hour)); err != nil {
+		t.Fatalf("expected a booking starting a minute from now, got %v", err)
+	}
+}
+
+func TestCreateBooking_StoresUTC(t *testing.T) {
+	repo := &mockBookingRepo{}
+	svc := NewBookingService(repo, newFieldRepoWith(1), fixedClock(testNow))
+	wib := time.FixedZone("", 7*60*60)
+
+	start := time.Date(2025, 6, 2, 18, 0, 0, 0, wib)
+	if err := book(svc, start, start.Add(time.Hour)); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got := repo.created[0]
+	if got.StartTime.Location() != time.UTC || !got.StartTime.Equal(time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 11:00 UTC, got %s", got.StartTime)
+	}
+}
+
+func TestCreateBooking_OpeningHoursWithoutDST(t *testing.T) {
+	jakarta := mustZone(t, "Asia/Jakarta")
+	svc := NewBookingService(&mockBookingRepo{}, scheduledFieldRepo("Asia/Jakarta", "06:00", "22:00"), fixedClock(testNow))
+	at := func(day, hour, minute int) time.Time { return time.Date(2025, 6, day, hour, minute, 0, 0, jakarta) }
+
+	cases := []struct {
+		name       string
+		start, end time.Time
+		ok         bool
+	}{
+		{"first slot", at(3, 6, 0), at(3, 7, 0), true},
+		{"last slot", at(3, 21, 0), at(3, 22, 0), true},
+		{"before opening", at(3, 5, 30), at(3, 6, 30), false},
+		{"past closing", at(3, 21, 30), at(3, 22, 30), false},
+		{"across midnight", at(3, 21, 0), at(4, 7, 0), false},
+		// 23:00 UTC is already 06:00 the next day in Jakarta
+		{"UTC evening", time.Date(2025, 6, 3, 23, 0, 0, 0, time.UTC), time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), true},
+	}
+	for _, c := range cases {
+		err := book(svc, c.start, c.end)
+		if c.ok && err != nil {
+			t.Errorf("%s: expected success, got %v", c.name, err)
+		}
+		if !c.ok && !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("%s: expected validation error, got %v", c.name, err)
+		}
+	}
+
+	err := book(svc, at(3, 5, 0), at(3, 6, 0))
+	if err == nil || !strings.Contains(err.Error(), "06:00-22:00 Asia/Jakarta") {
+		t.Fatalf("expected the opening hours in the error, got %v", err)
+	}
+}
+
+func TestCreateBooking_OpeningHoursAcrossDST(t *testing.T) {
+	// Berlin moves from UTC+1 to UTC+2 at 02:00 on 30 March 2025.
+	svc := NewBookingService(&mockBookingRepo{}, scheduledFieldRepo("Europe/Berlin", "08:00", "20:00"), fixedClock(testNow.AddDate(0, -3, 0)))
+	utc := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC) }
+
+	// 06:00 UTC is 07:00 on Saturday but 08:00 on Sunday.
+	if err := book(svc, utc(29, 6), utc(29, 7)); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected 07:00 CET to be before opening, got %v", err)
+	}
+	if err := book(svc, utc(30, 6), utc(30, 7)); err != nil {
+		t.Fatalf("expected 08:00 CEST to be open, got %v", err)
+	}
+	// and closing moves an hour earlier in UTC
+	if err := book(svc, utc(29, 18), utc(29, 19)); err != nil {
+		t.Fatalf("expected 19:00-20:00 CET to be open, got %v", err)
+	}
+	if err := book(svc, utc(30, 18), utc(30, 19)); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected 20:00-21:00 CEST to be past closing, got %v", err)
+	}
+}
+
+func TestLocalDay_DST(t *testing.T) {
+	newYork := mustZone(t, "America/New_York")
+	cases := []struct {
+		zone *time.Location
+		day  time.Time
+		want time.Duration
+	}{
+		{mustZone(t, "Asia/Jakarta"), time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC), 24 * time.Hour},
+		{newYork, time.Date(2025, 3, 9, 12, 0, 0, 0, newYork), 23 * time.Hour},
+		{newYork, time.Date(2025, 11, 2, 12, 0, 0, 0, newYork), 25 * time.Hour},
+	}
+	for _, c := range cases {
+		start, end := domain.LocalDay(c.day, c.zone)
+		if end.Sub(start) != c.want || start.In(c.zone).Hour() != 0 || end.In(c.zone).Hour() != 0 {
+			t.Errorf("%s %s: day is %s to %s", c.zone, c.day.Format("2006-01-02"), start, end)
+		}
+	}
+
+	// a field open all day is open for all 25 hours of the long day
+	svc := NewBookingService(&mockBookingRepo{}, scheduledFieldRepo("America/New_York", "00:00", "24:00"), fixedClock(testNow))
+	start, end := domain.LocalDay(time.Date(2025, 11, 2, 12, 0, 0, 0, newYork), newYork)
+	if err := book(svc, start, end); err != nil {
+		t.Fatalf("expected the whole day to be bookable, got %v", err)
+	}
+	if err := book(svc, start, end.Add(time.Minute)); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected the next day to be a separate booking, got %v", err)
+	}
+}
+
+func TestFieldService_Schedule(t *testing.T) {
+	repo := &mockFieldRepo{}
+	svc := NewFieldService(repo, fixedClock(testNow), "Asia/Jakarta")
+	ctx := context.Background()
+
+	if err := svc.CreateField(ctx, &port.CreateFieldRequest{Name: "A", PricePerHour: 100000}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	f := repo.byID[1]
+	if f.TimeZone != "Asia/Jakarta" || f.OpenTime != "00:00" || f.CloseTime != "24:00" {
+		t.Fatalf("unexpected defaults: %+v", f)
+	}
+
+	invalid := []port.CreateFieldRequest{
+		{TimeZone: "Mars/Olympus"},
+		{TimeZone: "WIB"},
+		{OpenTime: "6am"},
+		{OpenTime: "24:00"},
+		{CloseTime: "25:00"},
+		{OpenTime: "22:00", CloseTime: "06:00"},
+		{OpenTime: "08:00", CloseTime: "08:00"},
+	}
+	for _, req := range invalid {
+		req.Name, req.PricePerHour = "B", 100000
+		if err := svc.CreateField(ctx, &req); !errors.Is(err, domain.ErrValidation) {
+			t.Errorf("%+v: expected validation error, got %v", req, err)
+		}
+	}
+
+	// updates keep whatever is not given
+	err := svc.UpdateField(ctx, 1, &port.CreateFieldRequest{Name: "A", PricePerHour: 100000, TimeZone: "Europe/Berlin", OpenTime: "08:00"})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	err = svc.UpdateField(ctx, 1, &port.CreateFieldRequest{Name: "A", PricePerHour: 120000, CloseTime: "20:30"})
+	if err != nil || f.TimeZone != "Europe/Berlin" || f.OpenTime != "08:00" || f.CloseTime != "20:30" {
+		t.Fatalf("unexpected field after update: %+v err=%v", f, err)
+	}
+}
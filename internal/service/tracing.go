@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// endSpan records err on span and ends it. Domain errors such as a booking
+// conflict are expected outcomes, so they are recorded as events without
+// marking the span failed; anything else sets the error status.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		var domainErr *domain.Error
+		var rateErr *domain.RateLimitError
+		if !errors.As(err, &domainErr) && !errors.As(err, &rateErr) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
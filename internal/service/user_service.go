@@ -1,22 +1,35 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
 	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
 	"github.com/HIUNCY/sagara-booking-api/pkg/util"
 )
 
+var errInvalidCredentials = domain.NewUnauthorizedError("invalid email or password")
+
 type UserServiceImpl struct {
-	repo port.UserRepository
+	repo         port.UserRepository
+	tokens       port.TokenIssuer
+	ipGuard      port.LoginGuard
+	accountGuard port.LoginGuard
 }
 
-func NewUserService(repo port.UserRepository) port.UserService {
-	return &UserServiceImpl{repo: repo}
+func NewUserService(repo port.UserRepository, tokens port.TokenIssuer, ipGuard, accountGuard port.LoginGuard) port.UserService {
+	util.PrepareDummyPassword()
+	return &UserServiceImpl{repo: repo, tokens: tokens, ipGuard: ipGuard, accountGuard: accountGuard}
 }
 
-func (s *UserServiceImpl) Register(req *port.RegisterRequest) error {
+func (s *UserServiceImpl) Register(ctx context.Context, req *port.RegisterRequest) error {
+	if req.Name == "" || req.Email == "" || req.Password == "" {
+		return domain.NewValidationError("name, email and password are required")
+	}
+
 	hashedPwd, err := util.HashPassword(req.Password)
 	if err != nil {
 		return err
@@ -25,6 +38,9 @@ func (s *UserServiceImpl) Register(req *port.RegisterRequest) error {
 	if req.Role == "" {
 		req.Role = "user"
 	}
+	if req.Role != "user" && req.Role != "admin" {
+		return domain.NewValidationError("role must be either 'user' or 'admin'")
+	}
 
 	user := &domain.User{
 		Name:     req.Name,
@@ -33,20 +49,41 @@ func (s *UserServiceImpl) Register(req *port.RegisterRequest) error {
 		Role:     req.Role,
 	}
 
-	return s.repo.CreateUser(user)
+	return s.repo.CreateUser(ctx, user)
 }
 
-func (s *UserServiceImpl) Login(req *port.LoginRequest) (*port.LoginResponse, error) {
-	user, err := s.repo.GetByEmail(req.Email)
+func (s *UserServiceImpl) Login(ctx context.Context, req *port.LoginRequest) (*port.LoginResponse, error) {
+	ipKey := "ip:" + req.ClientIP
+	accountKey := "account:" + strings.ToLower(strings.TrimSpace(req.Email))
+
+	// Throttled attempts are rejected before any bcrypt work is done.
+	if wait := max(s.ipGuard.Check(ctx, ipKey), s.accountGuard.Check(ctx, accountKey)); wait > 0 {
+		logging.FromContext(ctx).Warn("login throttled", "ip", req.ClientIP, "retry_after", wait.String())
+		return nil, domain.NewRateLimitError("too many failed login attempts, please try again later", wait)
+	}
+
+	user, err := s.repo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		util.CheckDummyPassword(req.Password)
+		s.ipGuard.Fail(ctx, ipKey)
+		s.accountGuard.Fail(ctx, accountKey)
+		return nil, errInvalidCredentials
 	}
 
 	if !util.CheckPasswordHash(req.Password, user.Password) {
-		return nil, errors.New("invalid email or password")
+		s.ipGuard.Fail(ctx, ipKey)
+		s.accountGuard.Fail(ctx, accountKey)
+		return nil, errInvalidCredentials
 	}
+	// Only the account is cleared: a valid login proves nothing about the
+	// other accounts tried from the same address, whose failures fade with
+	// the IP policy's ResetAfter instead.
+	s.accountGuard.Reset(ctx, accountKey)
 
-	token, err := util.GenerateToken(user.ID, user.Role)
+	token, err := s.tokens.GenerateToken(ctx, user.ID, user.Role)
 	if err != nil {
 		return nil, err
 	}
@@ -1,21 +1,26 @@
 package service
 
 import (
+    "context"
     "errors"
-    "os"
     "testing"
+    "time"
 
     "github.com/HIUNCY/sagara-booking-api/internal/core/domain"
     "github.com/HIUNCY/sagara-booking-api/internal/core/port"
+    "github.com/HIUNCY/sagara-booking-api/pkg/util"
+    "golang.org/x/crypto/bcrypt"
 )
 
+var testTokens = util.NewTokenManager("secret-secret-secret-secret-secret", time.Hour)
+
 type mockUserRepo struct {
     users map[string]*domain.User
     createErr error
     getErrByEmail map[string]error
 }
 
-func (m *mockUserRepo) CreateUser(user *domain.User) error {
+func (m *mockUserRepo) CreateUser(ctx context.Context, user *domain.User) error {
     if m.createErr != nil {
         return m.createErr
     }
@@ -28,7 +33,7 @@ func (m *mockUserRepo) CreateUser(user *domain.User) error {
     return nil
 }
 
-func (m *mockUserRepo) GetByEmail(email string) (*domain.User, error) {
+func (m *mockUserRepo) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
     if m.getErrByEmail != nil {
         if err, ok := m.getErrByEmail[email]; ok {
             return nil, err
@@ -36,19 +41,44 @@ func (m *mockUserRepo) GetByEmail(email string) (*domain.User, error) {
     }
     u, ok := m.users[email]
     if !ok {
-        return nil, errors.New("not found")
+        return nil, domain.NewNotFoundError("not found")
     }
     return u, nil
 }
 
+func (m *mockUserRepo) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+    for _, u := range m.users {
+        if u.ID == id {
+            return u, nil
+        }
+    }
+    return nil, domain.NewNotFoundError("not found")
+}
+
+type fakeGuard struct {
+    blocked  map[string]time.Duration
+    failures map[string]int
+}
+
+func newFakeGuard() *fakeGuard {
+    return &fakeGuard{blocked: map[string]time.Duration{}, failures: map[string]int{}}
+}
+
+func (g *fakeGuard) Check(ctx context.Context, key string) time.Duration { return g.blocked[key] }
+func (g *fakeGuard) Fail(ctx context.Context, key string) time.Duration {
+    g.failures[key]++
+    return g.blocked[key]
+}
+func (g *fakeGuard) Reset(ctx context.Context, key string) { delete(g.failures, key) }
+
 func TestUserService_Register_DefaultRoleAndHash(t *testing.T) {
     repo := &mockUserRepo{}
-    svc := NewUserService(repo)
+    svc := NewUserService(repo, testTokens, newFakeGuard(), newFakeGuard())
     req := &port.RegisterRequest{Name: "A", Email: "a@example.com", Password: "pass"}
-    if err := svc.Register(req); err != nil {
+    if err := svc.Register(context.Background(), req); err != nil {
         t.Fatalf("Register error: %v", err)
     }
-    u, _ := repo.GetByEmail("a@example.com")
+    u, _ := repo.GetByEmail(context.Background(), "a@example.com")
     if u.Role != "user" {
         t.Fatalf("expected default role 'user', got %q", u.Role)
     }
@@ -58,25 +88,92 @@ func TestUserService_Register_DefaultRoleAndHash(t *testing.T) {
 }
 
 func TestUserService_Login_SuccessAndFailures(t *testing.T) {
-    os.Setenv("JWT_SECRET", "secret")
     repo := &mockUserRepo{users: map[string]*domain.User{}}
-    svc := NewUserService(repo)
+    svc := NewUserService(repo, testTokens, newFakeGuard(), newFakeGuard())
 
     // register user
-    _ = svc.Register(&port.RegisterRequest{Name: "U", Email: "u@mail", Password: "123"})
+    _ = svc.Register(context.Background(), &port.RegisterRequest{Name: "U", Email: "u@mail", Password: "123"})
 
     // success
-    resp, err := svc.Login(&port.LoginRequest{Email: "u@mail", Password: "123"})
+    resp, err := svc.Login(context.Background(), &port.LoginRequest{Email: "u@mail", Password: "123"})
     if err != nil || resp == nil || resp.Token == "" {
         t.Fatalf("expected token, got resp=%v err=%v", resp, err)
     }
 
     // wrong password
-    if _, err := svc.Login(&port.LoginRequest{Email: "u@mail", Password: "wrong"}); err == nil {
+    if _, err := svc.Login(context.Background(), &port.LoginRequest{Email: "u@mail", Password: "wrong"}); err == nil {
         t.Fatalf("expected error on wrong password")
     }
     // unknown email
-    if _, err := svc.Login(&port.LoginRequest{Email: "x@mail", Password: "123"}); err == nil {
+    if _, err := svc.Login(context.Background(), &port.LoginRequest{Email: "x@mail", Password: "123"}); err == nil {
         t.Fatalf("expected error on unknown email")
     }
 }
+
+func TestUserService_Login_Throttling(t *testing.T) {
+    repo := &mockUserRepo{users: map[string]*domain.User{}}
+    ipGuard, accountGuard := newFakeGuard(), newFakeGuard()
+    svc := NewUserService(repo, testTokens, ipGuard, accountGuard)
+    _ = svc.Register(context.Background(), &port.RegisterRequest{Name: "U", Email: "u@mail", Password: "123"})
+
+    // unknown email counts against both the IP and the account key
+    if _, err := svc.Login(context.Background(), &port.LoginRequest{Email: "X@mail", Password: "123", ClientIP: "1.2.3.4"}); !errors.Is(err, domain.ErrUnauthorized) {
+        t.Fatalf("expected unauthorized, got %v", err)
+    }
+    if ipGuard.failures["ip:1.2.3.4"] != 1 || accountGuard.failures["account:x@mail"] != 1 {
+        t.Fatalf("expected failures recorded, got ip=%v account=%v", ipGuard.failures, accountGuard.failures)
+    }
+
+    // wrong password counts too; success clears the account key only
+    _, _ = svc.Login(context.Background(), &port.LoginRequest{Email: "u@mail", Password: "bad", ClientIP: "1.2.3.4"})
+    if accountGuard.failures["account:u@mail"] != 1 || ipGuard.failures["ip:1.2.3.4"] != 2 {
+        t.Fatalf("expected failures recorded, got ip=%v account=%v", ipGuard.failures, accountGuard.failures)
+    }
+    if _, err := svc.Login(context.Background(), &port.LoginRequest{Email: "u@mail", Password: "123", ClientIP: "1.2.3.4"}); err != nil {
+        t.Fatalf("expected success, got %v", err)
+    }
+    if _, ok := accountGuard.failures["account:u@mail"]; ok {
+        t.Fatalf("expected account failures reset after success")
+    }
+    if ipGuard.failures["ip:1.2.3.4"] != 2 {
+        t.Fatalf("expected IP failures kept after success, got %v", ipGuard.failures)
+    }
+
+    // a locked account is rejected before the password is checked
+    accountGuard.blocked["account:u@mail"] = 30 * time.Second
+    _, err := svc.Login(context.Background(), &port.LoginRequest{Email: "u@mail", Password: "123", ClientIP: "5.6.7.8"})
+    var rateErr *domain.RateLimitError
+    if !errors.As(err, &rateErr) || rateErr.RetryAfter != 30*time.Second {
+        t.Fatalf("expected rate limit error with retry after, got %v", err)
+    }
+
+    // a blocked IP is rejected for any account
+    ipGuard.blocked["ip:9.9.9.9"] = time.Minute
+    if _, err := svc.Login(context.Background(), &port.LoginRequest{Email: "x@mail", Password: "123", ClientIP: "9.9.9.9"}); !errors.Is(err, domain.ErrRateLimited) {
+        t.Fatalf("expected rate limited, got %v", err)
+    }
+}
+
+func TestUserService_Login_GoodLoginsDoNotClearTheIP(t *testing.T) {
+    repo := &mockUserRepo{users: map[string]*domain.User{}}
+    for _, email := range []string{"mine@mail", "victim@mail"} {
+        hash, _ := bcrypt.GenerateFromPassword([]byte("right"), bcrypt.MinCost)
+        repo.users[email] = &domain.User{Email: email, Password: string(hash), Role: "user"}
+    }
+    ipGuard := util.NewLoginGuard(util.LoginGuardPolicy{FreeAttempts: 10, LockoutThreshold: 3, LockoutDuration: time.Hour, ResetAfter: time.Hour})
+    svc := NewUserService(repo, testTokens, ipGuard, newFakeGuard())
+    ctx := context.Background()
+
+    // An attacker logs into their own account between guesses at another.
+    for i := 0; i < 3; i++ {
+        if _, err := svc.Login(ctx, &port.LoginRequest{Email: "mine@mail", Password: "right", ClientIP: "6.6.6.6"}); err != nil {
+            t.Fatalf("attempt %d: expected their own login to succeed, got %v", i, err)
+        }
+        if _, err := svc.Login(ctx, &port.LoginRequest{Email: "victim@mail", Password: "guess", ClientIP: "6.6.6.6"}); !errors.Is(err, domain.ErrUnauthorized) {
+            t.Fatalf("attempt %d: expected unauthorized, got %v", i, err)
+        }
+    }
+    if _, err := svc.Login(ctx, &port.LoginRequest{Email: "victim@mail", Password: "guess", ClientIP: "6.6.6.6"}); !errors.Is(err, domain.ErrRateLimited) {
+        t.Fatalf("expected the IP to be locked out, got %v", err)
+    }
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+type WaitlistServiceImpl struct {
+	repo      port.WaitlistRepository
+	bookings  port.BookingRepository
+	fieldRepo port.FieldRepository
+	clock     port.Clock
+	// hold is how long an offered slot is kept for the customer.
+	hold time.Duration
+}
+
+func NewWaitlistService(repo port.WaitlistRepository, bookings port.BookingRepository, fieldRepo port.FieldRepository, clock port.Clock, hold time.Duration) port.WaitlistService {
+	return &WaitlistServiceImpl{repo: repo, bookings: bookings, fieldRepo: fieldRepo, clock: clock, hold: hold}
+}
+
+// JoinWaitlist accepts the same slots CreateBooking does, but only while
+// they are taken.
+func (s *WaitlistServiceImpl) JoinWaitlist(ctx context.Context, userID uint, req *port.WaitlistRequest) (*domain.WaitlistEntry, error) {
+	start, end, err := checkSlot(ctx, s.fieldRepo, s.clock.Now(), req.FieldID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.bookings.CheckAvailability(ctx, req.FieldID, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if !taken {
+		return nil, domain.NewValidationError("the field is free at this time; book it instead")
+	}
+
+	entry := &domain.WaitlistEntry{
+		FieldID:   req.FieldID,
+		UserID:    userID,
+		StartTime: start,
+		EndTime:   end,
+		Status:    domain.WaitlistStatusWaiting,
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.NewConflictError("you are already on the waitlist for this slot")
+		}
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("waitlist joined", "entry_id", entry.ID, "field_id", entry.FieldID, "user_id", userID)
+	return entry, nil
+}
+
+func (s *WaitlistServiceImpl) GetMyWaitlist(ctx context.Context, userID uint) ([]domain.WaitlistEntry, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// LeaveWaitlist withdraws the entry. Declining an offer passes the slot on
+// to the next customer through the waitlist.released event.
+func (s *WaitlistServiceImpl) LeaveWaitlist(ctx context.Context, userID, id uint) error {
+	entry, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if entry.UserID != userID {
+		// Other customers' entries are not acknowledged to exist.
+		return domain.NewNotFoundError("waitlist entry not found")
+	}
+	return s.repo.Cancel(ctx, id)
+}
+
+func (s *WaitlistServiceImpl) SlotFreed(ctx context.Context, fieldID uint, start, end time.Time) error {
+	now := s.clock.Now()
+	offered, err := s.repo.OfferFreed(ctx, fieldID, start, end, now, now.Add(s.hold))
+	if err != nil {
+		return err
+	}
+	for i := range offered {
+		logging.FromContext(ctx).Info("waitlist slot offered",
+			"entry_id", offered[i].ID, "field_id", fieldID, "user_id", offered[i].UserID)
+	}
+	return nil
+}
+
+// WaitlistEventTypes are the outbox events that free a slot.
+var WaitlistEventTypes = []string{domain.EventBookingCancelled, domain.EventBookingExpired, domain.EventWaitlistReleased,
+	domain.EventHoldReleased}
+
+// WaitlistEventHandler offers the slots freed by WaitlistEventTypes to the
+// waitlist.
+func WaitlistEventHandler(svc port.WaitlistService) port.EventHandler {
+	return port.EventHandlerFunc(func(ctx context.Context, event *domain.OutboxEvent) error {
+		switch event.EventType {
+		case domain.EventWaitlistReleased:
+			p, err := event.WaitlistPayload()
+			if err != nil {
+				return err
+			}
+			return svc.SlotFreed(ctx, p.FieldID, p.StartTime, p.EndTime)
+		case domain.EventHoldReleased:
+			p, err := event.HoldPayload()
+			if err != nil {
+				return err
+			}
+			return svc.SlotFreed(ctx, p.FieldID, p.StartTime, p.EndTime)
+		}
+		p, err := event.BookingPayload()
+		if err != nil {
+			return err
+		}
+		return svc.SlotFreed(ctx, p.FieldID, p.StartTime, p.EndTime)
+	})
+}
+
+// WaitlistOfferExpirer lapses offers that were not taken up in time. Their
+// slots pass to the next customer in line through waitlist.released events.
+type WaitlistOfferExpirer struct {
+	repo  port.WaitlistRepository
+	clock port.Clock
+}
+
+func NewWaitlistOfferExpirer(repo port.WaitlistRepository, clock port.Clock) *WaitlistOfferExpirer {
+	return &WaitlistOfferExpirer{repo: repo, clock: clock}
+}
+
+// ExpireOnce lapses every overdue offer and returns how many.
+func (e *WaitlistOfferExpirer) ExpireOnce(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		lapsed, err := e.repo.ExpireOffers(ctx, e.clock.Now(), expireBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for i := range lapsed {
+			logging.FromContext(ctx).Info("waitlist offer lapsed", "entry_id", lapsed[i].ID, "user_id", lapsed[i].UserID)
+		}
+		total += len(lapsed)
+		if len(lapsed) < expireBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Run lapses overdue offers every interval until ctx is cancelled.
+func (e *WaitlistOfferExpirer) Run(ctx context.Context, interval time.Duration, beat func()) {
+	for {
+		beat()
+		if _, err := e.ExpireOnce(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("expiring waitlist offers failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
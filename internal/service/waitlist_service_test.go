@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/internal/outbox"
+)
+
+// memoryWaitlistRepo keeps entries in join order and records the entries
+// whose offers were released. Offered entries hold their slots.
+type memoryWaitlistRepo struct {
+	entries  []*domain.WaitlistEntry
+	released []uint
+}
+
+func (m *memoryWaitlistRepo) Create(ctx context.Context, entry *domain.WaitlistEntry) error {
+	for _, e := range m.entries {
+		if e.UserID == entry.UserID && e.FieldID == entry.FieldID && e.StartTime.Equal(entry.StartTime) &&
+			e.EndTime.Equal(entry.EndTime) && (e.Status == domain.WaitlistStatusWaiting || e.Status == domain.WaitlistStatusOffered) {
+			return domain.NewConflictError("duplicate")
+		}
+	}
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *memoryWaitlistRepo) GetByID(ctx context.Context, id uint) (*domain.WaitlistEntry, error) {
+	if id == 0 || int(id) > len(m.entries) {
+		return nil, domain.NewNotFoundError("waitlist entry not found")
+	}
+	return m.entries[id-1], nil
+}
+
+func (m *memoryWaitlistRepo) ListByUser(ctx context.Context, userID uint) ([]domain.WaitlistEntry, error) {
+	var res []domain.WaitlistEntry
+	for _, e := range m.entries {
+		if e.UserID == userID {
+			res = append(res, *e)
+		}
+	}
+	return res, nil
+}
+
+func (m *memoryWaitlistRepo) Cancel(ctx context.Context, id uint) error {
+	e, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Status == domain.WaitlistStatusOffered {
+		m.released = append(m.released, e.ID)
+	}
+	if e.Status == domain.WaitlistStatusWaiting || e.Status == domain.WaitlistStatusOffered {
+		e.Status, e.HoldExpiresAt = domain.WaitlistStatusCancelled, nil
+	}
+	return nil
+}
+
+func (m *memoryWaitlistRepo) taken(e *domain.WaitlistEntry) bool {
+	for _, o := range m.entries {
+		if o.Status == domain.WaitlistStatusOffered && o.FieldID == e.FieldID &&
+			o.StartTime.Before(e.EndTime) && o.EndTime.After(e.StartTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryWaitlistRepo) OfferFreed(ctx context.Context, fieldID uint, start, end, now, holdUntil time.Time) ([]domain.WaitlistEntry, error) {
+	var offered []domain.WaitlistEntry
+	for _, e := range m.entries {
+		if e.Status != domain.WaitlistStatusWaiting || e.FieldID != fieldID || !e.StartTime.After(now) ||
+			!e.StartTime.Before(end) || !e.EndTime.After(start) || m.taken(e) {
+			continue
+		}
+		until := holdUntil
+		e.Status, e.HoldExpiresAt = domain.WaitlistStatusOffered, &until
+		offered = append(offered, *e)
+	}
+	return offered, nil
+}
+
+func (m *memoryWaitlistRepo) ExpireOffers(ctx context.Context, now time.Time, limit int) ([]domain.WaitlistEntry, error) {
+	var lapsed []domain.WaitlistEntry
+	for _, e := range m.entries {
+		if len(lapsed) == limit {
+			break
+		}
+		if e.Status == domain.WaitlistStatusOffered && e.HoldExpiresAt.Before(now) {
+			e.Status, e.HoldID, e.HoldExpiresAt = domain.WaitlistStatusLapsed, nil, nil
+			m.released = append(m.released, e.ID)
+			lapsed = append(lapsed, *e)
+		}
+	}
+	return lapsed, nil
+}
+
+// memoryOutbox is an outbox whose events are all due at once and never
+// leased by anyone else.
+type memoryOutbox struct {
+	events []domain.OutboxEvent
+}
+
+func (m *memoryOutbox) appendHold(hold *domain.SlotHold) {
+	if m == nil {
+		return
+	}
+	event, err := domain.NewHoldEvent(domain.EventHoldReleased, hold, testNow)
+	if err != nil {
+		panic(err)
+	}
+	event.ID = uint(len(m.events) + 1)
+	m.events = append(m.events, event)
+}
+
+func (m *memoryOutbox) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	var due []domain.OutboxEvent
+	for _, e := range m.events {
+		if e.Status == domain.OutboxStatusPending && !e.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, e)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryOutbox) MarkSubscriberDelivered(ctx context.Context, event *domain.OutboxEvent, subscriber string, at time.Time) error {
+	return nil
+}
+
+func (m *memoryOutbox) MarkDelivered(ctx context.Context, event *domain.OutboxEvent, at time.Time) error {
+	m.events[event.ID-1].Status = domain.OutboxStatusDelivered
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(ctx context.Context, event *domain.OutboxEvent) error {
+	m.events[event.ID-1] = *event
+	return nil
+}
+
+func newTestWaitlist(repo *memoryWaitlistRepo, taken bool) port.WaitlistService {
+	bookings := &mockBookingRepo{avail: map[uint]bool{1: taken}}
+	return NewWaitlistService(repo, bookings, newFieldRepoWith(1), fixedClock(testNow), 15*time.Minute)
+}
+
+func TestWaitlistService_Join(t *testing.T) {
+	ctx := context.Background()
+	start := testNow.Add(3 * time.Hour)
+	end := start.Add(time.Hour)
+
+	svc := newTestWaitlist(&memoryWaitlistRepo{}, true)
+	for _, tc := range []struct {
+		name string
+		req  port.WaitlistRequest
+		want error
+	}{
+		{"missing field", port.WaitlistRequest{StartTime: start, EndTime: end}, domain.ErrValidation},
+		{"end before start", port.WaitlistRequest{FieldID: 1, StartTime: end, EndTime: start}, domain.ErrValidation},
+		{"already begun", port.WaitlistRequest{FieldID: 1, StartTime: testNow.Add(-time.Hour), EndTime: end}, domain.ErrValidation},
+		{"unknown field", port.WaitlistRequest{FieldID: 9, StartTime: start, EndTime: end}, domain.ErrNotFound},
+		{"joined", port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: end}, nil},
+		{"joined twice", port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: end}, domain.ErrConflict},
+	} {
+		_, err := svc.JoinWaitlist(ctx, 10, &tc.req)
+		if (tc.want == nil && err != nil) || (tc.want != nil && !errors.Is(err, tc.want)) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	free := newTestWaitlist(&memoryWaitlistRepo{}, false)
+	if _, err := free.JoinWaitlist(ctx, 10, &port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: end}); !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("expected a free slot to be refused, got %v", err)
+	}
+}
+
+func TestWaitlistService_OffersInJoinOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryWaitlistRepo{}
+	svc := newTestWaitlist(repo, true)
+	start := testNow.Add(3 * time.Hour)
+	end := start.Add(time.Hour)
+
+	first, _ := svc.JoinWaitlist(ctx, 10, &port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: end})
+	second, _ := svc.JoinWaitlist(ctx, 11, &port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: end})
+	later, _ := svc.JoinWaitlist(ctx, 12, &port.WaitlistRequest{FieldID: 1, StartTime: end, EndTime: end.Add(time.Hour)})
+
+	if err := svc.SlotFreed(ctx, 1, start, end); err != nil {
+		t.Fatalf("slot freed: %v", err)
+	}
+	if first.Status != domain.WaitlistStatusOffered || !first.HoldExpiresAt.Equal(testNow.Add(15*time.Minute)) {
+		t.Fatalf("expected the first in line to be offered until now+hold, got %+v", first)
+	}
+	if second.Status != domain.WaitlistStatusWaiting || later.Status != domain.WaitlistStatusWaiting {
+		t.Fatalf("expected the others to keep waiting, got %s and %s", second.Status, later.Status)
+	}
+
+	// Someone else's entry cannot be withdrawn.
+	if err := svc.LeaveWaitlist(ctx, 11, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	// Declining releases the slot to the next in line.
+	if err := svc.LeaveWaitlist(ctx, 10, first.ID); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	if first.Status != domain.WaitlistStatusCancelled || len(repo.released) != 1 {
+		t.Fatalf("expected the offer released, got %+v (released %v)", first, repo.released)
+	}
+	event, _ := domain.NewWaitlistEvent(domain.EventWaitlistReleased, first, testNow)
+	if err := WaitlistEventHandler(svc).HandleEvent(ctx, &event); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if second.Status != domain.WaitlistStatusOffered {
+		t.Fatalf("expected the second in line to be offered, got %s", second.Status)
+	}
+}
+
+func TestWaitlistEventHandler_BookingEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryWaitlistRepo{}
+	svc := newTestWaitlist(repo, true)
+	start := testNow.Add(3 * time.Hour)
+	entry, _ := svc.JoinWaitlist(ctx, 10, &port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: start.Add(time.Hour)})
+
+	// A longer booking covering the slot is cancelled.
+	booking := &domain.Booking{FieldID: 1, UserID: 20, StartTime: start.Add(-time.Hour), EndTime: start.Add(2 * time.Hour),
+		Status: domain.BookingStatusCancelled}
+	event, err := domain.NewBookingEvent(domain.EventBookingCancelled, booking, "", testNow)
+	if err != nil {
+		t.Fatalf("event: %v", err)
+	}
+	if err := WaitlistEventHandler(svc).HandleEvent(ctx, &event); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if entry.Status != domain.WaitlistStatusOffered {
+		t.Fatalf("expected an offer, got %s", entry.Status)
+	}
+}
+
+func TestWaitlistEventHandler_HoldReleased(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryWaitlistRepo{}
+	svc := newTestWaitlist(repo, true)
+	start := testNow.Add(3 * time.Hour)
+	entry, _ := svc.JoinWaitlist(ctx, 10, &port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: start.Add(time.Hour)})
+
+	// Another customer's checkout hold on the slot runs out.
+	hold := &domain.SlotHold{ID: 4, FieldID: 1, UserID: 20, StartTime: start, EndTime: start.Add(time.Hour),
+		ExpiresAt: testNow.Add(-time.Minute)}
+	event, err := domain.NewHoldEvent(domain.EventHoldReleased, hold, testNow)
+	if err != nil {
+		t.Fatalf("event: %v", err)
+	}
+	if err := WaitlistEventHandler(svc).HandleEvent(ctx, &event); err != nil {
+		t.Fatalf("handle: %v", err)
+	}
+	if entry.Status != domain.WaitlistStatusOffered {
+		t.Fatalf("expected an offer, got %s", entry.Status)
+	}
+}
+
+// TestWaitlist_OfferedWhenAHoldRunsOut follows a slot from the hold that
+// blocks it, through the outbox, to the offer made to the customer waiting
+// for it.
+func TestWaitlist_OfferedWhenAHoldRunsOut(t *testing.T) {
+	ctx := context.Background()
+	start := testNow.Add(3 * time.Hour)
+	events := &memoryOutbox{}
+	holds := &memoryHoldRepo{outbox: events, holds: map[uint]*domain.SlotHold{
+		1: {ID: 1, FieldID: 1, UserID: 20, StartTime: start, EndTime: start.Add(time.Hour), ExpiresAt: testNow.Add(-time.Minute)},
+	}}
+	entries := &memoryWaitlistRepo{}
+	waitlist := newTestWaitlist(entries, true)
+	entry, err := waitlist.JoinWaitlist(ctx, 10, &port.WaitlistRequest{FieldID: 1, StartTime: start, EndTime: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	dispatcher := outbox.NewDispatcher(events, outbox.Options{})
+	for _, eventType := range WaitlistEventTypes {
+		dispatcher.Subscribe(eventType, "waitlist", WaitlistEventHandler(waitlist))
+	}
+
+	if n, err := NewSlotHoldExpirer(holds, fixedClock(testNow)).ExpireOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("expected the hold to run out, got %d (%v)", n, err)
+	}
+	if n, err := dispatcher.DispatchOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one event dispatched, got %d (%v)", n, err)
+	}
+	if events.events[0].EventType != domain.EventHoldReleased || events.events[0].Status != domain.OutboxStatusDelivered {
+		t.Fatalf("expected hold.released delivered, got %+v", events.events[0])
+	}
+	if entry.Status != domain.WaitlistStatusOffered || !entry.HoldExpiresAt.Equal(testNow.Add(15*time.Minute)) {
+		t.Fatalf("expected the waiting customer to be offered the slot, got %+v", entry)
+	}
+}
+
+func TestWaitlistOfferExpirer_ExpireOnce(t *testing.T) {
+	repo := &memoryWaitlistRepo{}
+	overdue, due := testNow.Add(-time.Minute), testNow.Add(time.Minute)
+	repo.entries = []*domain.WaitlistEntry{
+		{ID: 1, Status: domain.WaitlistStatusOffered, HoldExpiresAt: &overdue},
+		{ID: 2, Status: domain.WaitlistStatusOffered, HoldExpiresAt: &due},
+		{ID: 3, Status: domain.WaitlistStatusWaiting},
+	}
+	e := NewWaitlistOfferExpirer(repo, fixedClock(testNow))
+
+	n, err := e.ExpireOnce(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("expected one lapsed offer, got %d (%v)", n, err)
+	}
+	if repo.entries[0].Status != domain.WaitlistStatusLapsed || repo.entries[1].Status != domain.WaitlistStatusOffered {
+		t.Fatalf("unexpected statuses %s, %s", repo.entries[0].Status, repo.entries[1].Status)
+	}
+	if repo.entries[0].HoldExpiresAt != nil || repo.entries[1].HoldExpiresAt == nil {
+		t.Fatalf("expected only the lapsed offer's hold cleared, got %+v", repo.entries)
+	}
+	if len(repo.released) != 1 || repo.released[0] != 1 {
+		t.Fatalf("expected entry 1 released, got %v", repo.released)
+	}
+}
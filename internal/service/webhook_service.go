@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+const (
+	minWebhookSecretLength = 16
+	defaultDeliveryLimit   = 50
+	maxDeliveryLimit       = 200
+)
+
+type WebhookServiceImpl struct {
+	repo port.WebhookRepository
+	now  func() time.Time
+}
+
+func NewWebhookService(repo port.WebhookRepository) port.WebhookService {
+	return &WebhookServiceImpl{repo: repo, now: time.Now}
+}
+
+func validateWebhookRequest(req *port.WebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return domain.NewValidationError("url must be an absolute http or https URL")
+	}
+	if u.User != nil {
+		return domain.NewValidationError("url must not contain credentials; use the signing secret instead")
+	}
+	if len(req.EventTypes) == 0 {
+		return domain.NewValidationError("at least one event type is required")
+	}
+	for _, t := range req.EventTypes {
+		if !isWebhookEventType(t) {
+			return domain.NewValidationError("unknown event type " + t)
+		}
+	}
+	if req.Secret != "" && len(req.Secret) < minWebhookSecretLength {
+		return domain.NewValidationError("secret must be at least 16 characters")
+	}
+	return nil
+}
+
+func isWebhookEventType(t string) bool {
+	for _, known := range domain.WebhookEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueEventTypes drops duplicates while keeping the caller's order.
+func uniqueEventTypes(types []string) []string {
+	seen := make(map[string]bool, len(types))
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, req *port.WebhookRequest) (*port.WebhookSecretResponse, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	sub := &domain.WebhookSubscription{
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  uniqueEventTypes(req.EventTypes),
+		Description: req.Description,
+		Active:      active,
+	}
+	if err := s.repo.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("webhook created", "webhook_id", sub.ID, "url", sub.URL)
+	return &port.WebhookSecretResponse{WebhookSubscription: *sub, Secret: secret}, nil
+}
+
+func (s *WebhookServiceImpl) GetAllWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *WebhookServiceImpl) GetWebhookByID(ctx context.Context, id uint) (*domain.WebhookSubscription, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *WebhookServiceImpl) UpdateWebhook(ctx context.Context, id uint, req *port.WebhookRequest) (*port.WebhookSecretResponse, error) {
+	if err := validateWebhookRequest(req); err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.URL = req.URL
+	sub.EventTypes = uniqueEventTypes(req.EventTypes)
+	sub.Description = req.Description
+	columns := []string{"url", "event_types", "description"}
+	res := &port.WebhookSecretResponse{}
+	if req.Secret != "" {
+		sub.Secret = req.Secret
+		res.Secret = req.Secret
+		columns = append(columns, "secret")
+	}
+	if req.Active != nil {
+		if *req.Active && !sub.Active {
+			// Re-enabling gives the endpoint a clean slate; deliveries
+			// queued while it was disabled go out again.
+			sub.ConsecutiveFailures = 0
+			sub.DisabledAt = nil
+			sub.DisabledReason = ""
+			columns = append(columns, "consecutive_failures", "disabled_at", "disabled_reason")
+		}
+		sub.Active = *req.Active
+		columns = append(columns, "active")
+	}
+
+	// The sender updates the failure columns as it delivers, so only the
+	// edited columns are saved.
+	if err := s.repo.Update(ctx, sub, columns...); err != nil {
+		return nil, err
+	}
+	res.WebhookSubscription = *sub
+	return res, nil
+}
+
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, id uint, limit int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	if limit > maxDeliveryLimit {
+		limit = maxDeliveryLimit
+	}
+	return s.repo.ListDeliveries(ctx, id, limit)
+}
+
+func (s *WebhookServiceImpl) GetDelivery(ctx context.Context, id, deliveryID uint) (*domain.WebhookDelivery, error) {
+	return s.repo.GetDelivery(ctx, id, deliveryID)
+}
+
+// Redeliver sends a delivery again, whatever its state, with a fresh set of
+// attempts. Earlier attempts stay in the log.
+func (s *WebhookServiceImpl) Redeliver(ctx context.Context, id, deliveryID uint) (*domain.WebhookDelivery, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return nil, domain.NewConflictError("webhook is disabled; re-enable it before redelivering")
+	}
+	delivery, err := s.repo.Requeue(ctx, id, deliveryID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("webhook redelivery requested", "webhook_id", id, "delivery_id", deliveryID)
+	return delivery, nil
+}
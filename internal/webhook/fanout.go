@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// Fanout queues an outbox event for every subscription that wants it. Each
+// subscription then gets its own retries, so one slow partner never holds
+// back the others or the in-process subscribers.
+func Fanout(repo port.WebhookDeliveryRepository) port.EventHandler {
+	return port.EventHandlerFunc(func(ctx context.Context, event *domain.OutboxEvent) error {
+		body, err := json.Marshal(domain.WebhookEnvelope{
+			ID:        event.ID,
+			Type:      event.EventType,
+			CreatedAt: event.CreatedAt,
+			Data:      event.Payload,
+		})
+		if err != nil {
+			return err
+		}
+		n, err := repo.Enqueue(ctx, event, body)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			logging.FromContext(ctx).Debug("webhook deliveries queued", "event_id", event.ID, "deliveries", n)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,226 @@
+// Package webhook POSTs booking events to partner endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/internal/core/port"
+	"github.com/HIUNCY/sagara-booking-api/pkg/buildinfo"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+)
+
+// maxResponseBody bounds how much of a receiver's reply is kept in the log.
+const maxResponseBody = 1024
+
+// Options tune the sender. Zero values fall back to DefaultOptions.
+type Options struct {
+	// PollInterval is how long to sleep when no delivery was due.
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease is how long a claimed batch is reserved for this instance. It
+	// must comfortably exceed BatchSize × Timeout.
+	Lease   time.Duration
+	Timeout time.Duration
+	// MaxAttempts failed attempts mark a delivery failed.
+	MaxAttempts int
+	// Retries back off exponentially from BaseBackoff, capped at MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// DisableAfter consecutive failed attempts, across all deliveries,
+	// disable the subscription.
+	DisableAfter int
+}
+
+var DefaultOptions = Options{
+	PollInterval: time.Second,
+	BatchSize:    20,
+	Lease:        5 * time.Minute,
+	Timeout:      10 * time.Second,
+	MaxAttempts:  8,
+	BaseBackoff:  30 * time.Second,
+	MaxBackoff:   6 * time.Hour,
+	DisableAfter: 20,
+}
+
+// Sender claims due deliveries and POSTs them, signed, to their
+// subscription's URL. A 2xx response is a success; anything else, including
+// redirects and timeouts, is retried.
+type Sender struct {
+	repo   port.WebhookDeliveryRepository
+	client *http.Client
+	opts   Options
+	now    func() time.Time
+}
+
+func NewSender(repo port.WebhookDeliveryRepository, opts Options) *Sender {
+	o := DefaultOptions
+	if opts.PollInterval > 0 {
+		o.PollInterval = opts.PollInterval
+	}
+	if opts.BatchSize > 0 {
+		o.BatchSize = opts.BatchSize
+	}
+	if opts.Lease > 0 {
+		o.Lease = opts.Lease
+	}
+	if opts.Timeout > 0 {
+		o.Timeout = opts.Timeout
+	}
+	if opts.MaxAttempts > 0 {
+		o.MaxAttempts = opts.MaxAttempts
+	}
+	if opts.BaseBackoff > 0 {
+		o.BaseBackoff = opts.BaseBackoff
+	}
+	if opts.MaxBackoff > 0 {
+		o.MaxBackoff = opts.MaxBackoff
+	}
+	if opts.DisableAfter > 0 {
+		o.DisableAfter = opts.DisableAfter
+	}
+	return &Sender{
+		repo: repo,
+		client: &http.Client{
+			Timeout: o.Timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		opts: o,
+		now:  time.Now,
+	}
+}
+
+// Run sends until ctx is cancelled, calling beat once per iteration so a
+// stalled loop shows up in readiness checks.
+func (s *Sender) Run(ctx context.Context, beat func()) {
+	for {
+		beat()
+		n, err := s.SendOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("webhook: send failed", "error", err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.opts.PollInterval):
+		}
+	}
+}
+
+// SendOnce claims and sends one batch, returning how many deliveries it
+// claimed.
+func (s *Sender) SendOnce(ctx context.Context) (int, error) {
+	now := s.now()
+	deliveries, err := s.repo.ClaimDue(ctx, now, now.Add(s.opts.Lease), s.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			// Unsent deliveries stay leased and are retried after the lease.
+			return len(deliveries), ctx.Err()
+		}
+		s.process(ctx, &deliveries[i])
+	}
+	return len(deliveries), nil
+}
+
+func (s *Sender) process(ctx context.Context, delivery *domain.WebhookDelivery) {
+	logger := logging.FromContext(ctx).With("delivery_id", delivery.ID, "subscription_id", delivery.SubscriptionID)
+	attempt := s.post(ctx, delivery)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = attempt.ResponseStatus
+	delivery.LastError = attempt.Error
+	switch {
+	case attempt.Succeeded():
+		at := s.now()
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &at
+	case delivery.Attempts >= s.opts.MaxAttempts:
+		delivery.Status = domain.WebhookDeliveryFailed
+		logger.Warn("webhook: delivery failed", "attempts", delivery.Attempts, "error", attempt.Error)
+	default:
+		delivery.NextAttemptAt = s.now().Add(s.backoff(delivery.Attempts))
+	}
+
+	disabled, err := s.repo.RecordAttempt(ctx, delivery, attempt, s.opts.DisableAfter)
+	if err != nil {
+		logger.Error("webhook: recording attempt", "error", err)
+		return
+	}
+	if disabled {
+		logger.Warn("webhook: subscription disabled after repeated failures", "url", delivery.Subscription.URL)
+	}
+}
+
+// post sends one attempt and describes its outcome.
+func (s *Sender) post(ctx context.Context, delivery *domain.WebhookDelivery) *domain.WebhookAttempt {
+	attempt := &domain.WebhookAttempt{CreatedAt: s.now()}
+	start := time.Now()
+	defer func() { attempt.DurationMS = time.Since(start).Milliseconds() }()
+
+	sub := delivery.Subscription
+	if sub == nil {
+		attempt.Error = "subscription not loaded"
+		return attempt
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sagara-booking-webhooks/"+buildinfo.Version)
+	req.Header.Set(HeaderID, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var urlErr interface{ Timeout() bool }
+		if errors.As(err, &urlErr) && urlErr.Timeout() {
+			attempt.Error = fmt.Sprintf("timed out after %s", s.opts.Timeout)
+		} else {
+			attempt.Error = err.Error()
+		}
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt.ResponseStatus = resp.StatusCode
+	// Postgres text cannot hold invalid UTF-8 or NUL bytes.
+	attempt.ResponseBody = strings.ReplaceAll(string(bytes.ToValidUTF8(body, nil)), "\x00", "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = "unexpected status " + strconv.Itoa(resp.StatusCode)
+	}
+	return attempt
+}
+
+// backoff doubles from BaseBackoff for each attempt, capped at MaxBackoff.
+func (s *Sender) backoff(attempts int) time.Duration {
+	delay := s.opts.BaseBackoff
+	for i := 1; i < attempts && delay < s.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.opts.MaxBackoff {
+		delay = s.opts.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,354 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+)
+
+// memoryQueue is an in-memory WebhookDeliveryRepository with the same lease
+// and auto-disable rules as the Postgres one.
+type memoryQueue struct {
+	subs       map[uint]*domain.WebhookSubscription
+	deliveries []*domain.WebhookDelivery
+	attempts   []domain.WebhookAttempt
+}
+
+func newMemoryQueue(subs ...*domain.WebhookSubscription) *memoryQueue {
+	q := &memoryQueue{subs: map[uint]*domain.WebhookSubscription{}}
+	for _, s := range subs {
+		q.subs[s.ID] = s
+	}
+	return q
+}
+
+func (q *memoryQueue) Enqueue(ctx context.Context, event *domain.OutboxEvent, body []byte) (int, error) {
+	n := 0
+	for id := uint(1); id <= uint(len(q.subs)); id++ {
+		sub := q.subs[id]
+		if sub == nil || !sub.Active || !sub.Wants(event.EventType) || q.find(id, event.ID) != nil {
+			continue
+		}
+		q.deliveries = append(q.deliveries, &domain.WebhookDelivery{
+			ID:             uint(len(q.deliveries) + 1),
+			SubscriptionID: id,
+			EventID:        event.ID,
+			EventType:      event.EventType,
+			Payload:        body,
+			Status:         domain.WebhookDeliveryPending,
+		})
+		n++
+	}
+	return n, nil
+}
+
+func (q *memoryQueue) find(subID, eventID uint) *domain.WebhookDelivery {
+	for _, d := range q.deliveries {
+		if d.SubscriptionID == subID && d.EventID == eventID {
+			return d
+		}
+	}
+	return nil
+}
+
+func (q *memoryQueue) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var claimed []domain.WebhookDelivery
+	for _, d := range q.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if d.Status != domain.WebhookDeliveryPending || !q.subs[d.SubscriptionID].Active || d.NextAttemptAt.After(now) {
+			continue
+		}
+		if d.LockedUntil != nil && d.LockedUntil.After(now) {
+			continue
+		}
+		lease := leaseUntil
+		d.LockedUntil = &lease
+		c := *d
+		sub := *q.subs[d.SubscriptionID]
+		c.Subscription = &sub
+		claimed = append(claimed, c)
+	}
+	return claimed, nil
+}
+
+func (q *memoryQueue) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt *domain.WebhookAttempt, disableAfter int) (bool, error) {
+	stored := q.deliveries[delivery.ID-1]
+	if stored.LockedUntil == nil || delivery.LockedUntil == nil || !stored.LockedUntil.Equal(*delivery.LockedUntil) {
+		return false, domain.NewConflictError("webhook delivery lease was lost")
+	}
+	*stored = *delivery
+	stored.LockedUntil = nil
+	stored.Subscription = nil
+	attempt.DeliveryID = delivery.ID
+	q.attempts = append(q.attempts, *attempt)
+
+	sub := q.subs[delivery.SubscriptionID]
+	if attempt.Succeeded() {
+		sub.ConsecutiveFailures = 0
+		return false, nil
+	}
+	sub.ConsecutiveFailures++
+	if sub.Active && sub.ConsecutiveFailures >= disableAfter {
+		now := time.Now()
+		sub.Active, sub.DisabledAt = false, &now
+		return true, nil
+	}
+	return false, nil
+}
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestSender(q *memoryQueue, opts Options) (*Sender, *clock) {
+	c := &clock{t: time.Now().Truncate(time.Second)}
+	s := NewSender(q, opts)
+	s.now = c.now
+	return s, c
+}
+
+// receiver records what it was sent and answers with the next status.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(http.StatusText(status)))
+}
+
+func newSubscription(id uint, url string) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:         id,
+		URL:        url,
+		Secret:     "whsec_test_secret_value",
+		EventTypes: []string{domain.EventBookingPaid},
+		Active:     true,
+	}
+}
+
+func enqueue(t *testing.T, q *memoryQueue, eventID uint, eventType string) {
+	t.Helper()
+	event := &domain.OutboxEvent{ID: eventID, EventType: eventType, Payload: []byte(`{"booking_id":7}`), CreatedAt: time.Now()}
+	if err := Fanout(q).HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("fanout: %v", err)
+	}
+}
+
+func TestSender_DeliversSignedRequest(t *testing.T) {
+	rcv := &receiver{}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	q := newMemoryQueue(newSubscription(1, srv.URL))
+	enqueue(t, q, 42, domain.EventBookingPaid)
+	enqueue(t, q, 43, domain.EventBookingCreated) // not subscribed
+	if len(q.deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(q.deliveries))
+	}
+
+	s, c := newTestSender(q, Options{})
+	if n, err := s.SendOnce(context.Background()); n != 1 || err != nil {
+		t.Fatalf("expected 1 sent, got %d err=%v", n, err)
+	}
+
+	if len(rcv.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(rcv.requests))
+	}
+	req, body := rcv.requests[0], rcv.bodies[0]
+	ts := strconv.FormatInt(c.t.Unix(), 10)
+	if req.Header.Get(HeaderTimestamp) != ts || req.Header.Get(HeaderEvent) != domain.EventBookingPaid || req.Header.Get(HeaderID) != "1" {
+		t.Fatalf("unexpected headers: %v", req.Header)
+	}
+	if got, want := req.Header.Get(HeaderSignature), Sign("whsec_test_secret_value", c.t.Unix(), body); got != want {
+		t.Fatalf("signature mismatch: got %s want %s", got, want)
+	}
+
+	var envelope domain.WebhookEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("body is not an envelope: %v", err)
+	}
+	if envelope.ID != 42 || envelope.Type != domain.EventBookingPaid || string(envelope.Data) != `{"booking_id":7}` {
+		t.Fatalf("unexpected envelope: %+v", envelope)
+	}
+
+	d := q.deliveries[0]
+	if d.Status != domain.WebhookDeliverySucceeded || d.Attempts != 1 || d.ResponseStatus != 200 || d.DeliveredAt == nil {
+		t.Fatalf("unexpected delivery state: %+v", d)
+	}
+	if len(q.attempts) != 1 || q.attempts[0].ResponseBody != "OK" {
+		t.Fatalf("unexpected attempt log: %+v", q.attempts)
+	}
+
+	// The outbox may hand the same event over again.
+	enqueue(t, q, 42, domain.EventBookingPaid)
+	if len(q.deliveries) != 1 {
+		t.Fatalf("duplicate event queued twice")
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Receivers recompute HMAC-SHA256("<timestamp>.<body>") with their secret, e.g.
+	// printf '1700000000.{"id":1}' | openssl dgst -sha256 -hmac secret
+	got := Sign("secret", 1700000000, []byte(`{"id":1}`))
+	if want := "v1=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11"; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if Sign("secret", 1700000001, []byte(`{"id":1}`)) == got || Sign("other", 1700000000, []byte(`{"id":1}`)) == got {
+		t.Fatalf("signature must cover the timestamp and the secret")
+	}
+}
+
+func TestSender_RetriesWithBackoffThenFails(t *testing.T) {
+	rcv := &receiver{statuses: []int{500, 503, 500}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+
+	q := newMemoryQueue(newSubscription(1, srv.URL))
+	enqueue(t, q, 1, domain.EventBookingPaid)
+	s, c := newTestSender(q, Options{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour})
+	d := q.deliveries[0]
+
+	_, _ = s.SendOnce(context.Background())
+	if d.Status != domain.WebhookDeliveryPending || d.Attempts != 1 || d.ResponseStatus != 500 || !d.NextAttemptAt.Equal(c.t.Add(time.Minute)) {
+		t.Fatalf("expected retry in 1m, got %+v", d)
+	}
+	if n, _ := s.SendOnce(context.Background()); n != 0 {
+		t.Fatalf("delivery retried before its backoff elapsed")
+	}
+
+	c.advance(time.Minute)
+	_, _ = s.SendOnce(context.Background())
+	if d.Attempts != 2 || !d.NextAttemptAt.Equal(c.t.Add(2*time.Minute)) {
+		t.Fatalf("expected retry in 2m, got %+v", d)
+	}
+
+	c.advance(2 * time.Minute)
+	_, _ = s.SendOnce(context.Background())
+	if d.Status != domain.WebhookDeliveryFailed || d.Attempts != 3 || d.LastError != "unexpected status 500" {
+		t.Fatalf("expected failed delivery, got %+v", d)
+	}
+	if len(q.attempts) != 3 || len(rcv.requests) != 3 {
+		t.Fatalf("expected 3 logged attempts, got %d", len(q.attempts))
+	}
+	// Retries resend the exact same body under the same delivery ID.
+	if string(rcv.bodies[0]) != string(rcv.bodies[2]) || rcv.requests[0].Header.Get(HeaderID) != rcv.requests[2].Header.Get(HeaderID) {
+		t.Fatalf("retries must be identical")
+	}
+}
+
+func TestSender_Backoff(t *testing.T) {
+	s, _ := newTestSender(newMemoryQueue(), Options{BaseBackoff: 30 * time.Second, MaxBackoff: 3 * time.Minute})
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	for i, w := range want {
+		if got := s.backoff(i + 1); got != w {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+}
+
+func TestSender_DisablesSubscriptionAfterRepeatedFailures(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	healthy := &receiver{}
+	ok := httptest.NewServer(healthy)
+	defer ok.Close()
+
+	broken, fine := newSubscription(1, failing.URL), newSubscription(2, ok.URL)
+	q := newMemoryQueue(broken, fine)
+	for id := uint(1); id <= 3; id++ {
+		enqueue(t, q, id, domain.EventBookingPaid)
+	}
+
+	s, c := newTestSender(q, Options{DisableAfter: 3, BaseBackoff: time.Second})
+	_, _ = s.SendOnce(context.Background())
+	if broken.Active || broken.DisabledAt == nil || broken.ConsecutiveFailures != 3 {
+		t.Fatalf("expected subscription disabled after 3 failures, got %+v", broken)
+	}
+	if !fine.Active || len(healthy.requests) != 3 {
+		t.Fatalf("a failing partner must not affect the others")
+	}
+
+	// Nothing is sent to a disabled subscription, but its deliveries wait.
+	c.advance(time.Hour)
+	if n, _ := s.SendOnce(context.Background()); n != 0 {
+		t.Fatalf("claimed %d deliveries of a disabled subscription", n)
+	}
+	for _, d := range q.deliveries {
+		if d.SubscriptionID == 1 && d.Status != domain.WebhookDeliveryPending {
+			t.Fatalf("delivery %d should stay pending, got %s", d.ID, d.Status)
+		}
+	}
+}
+
+func TestSender_RedirectsAndTimeoutsFail(t *testing.T) {
+	redirect := httptest.NewServer(http.RedirectHandler("https://example.com/elsewhere", http.StatusFound))
+	defer redirect.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	a, b := newSubscription(1, redirect.URL), newSubscription(2, slow.URL)
+	q := newMemoryQueue(a, b)
+	enqueue(t, q, 1, domain.EventBookingPaid)
+
+	s, _ := newTestSender(q, Options{Timeout: 50 * time.Millisecond})
+	_, _ = s.SendOnce(context.Background())
+
+	if d := q.find(1, 1); d.Status != domain.WebhookDeliveryPending || d.ResponseStatus != http.StatusFound {
+		t.Fatalf("redirect should be retried, got %+v", d)
+	}
+	if d := q.find(2, 1); d.Status != domain.WebhookDeliveryPending || !strings.Contains(d.LastError, "timed out") {
+		t.Fatalf("timeout should be retried, got %+v", d)
+	}
+}
+
+func TestSender_LostLeaseIsNotOverwritten(t *testing.T) {
+	q := newMemoryQueue()
+	var s *Sender
+	var c *clock
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Another instance reclaims the delivery after the lease ran out.
+		c.advance(10 * time.Minute)
+		_, _ = q.ClaimDue(context.Background(), c.t, c.t.Add(time.Minute), 1)
+	}))
+	defer srv.Close()
+	q.subs[1] = newSubscription(1, srv.URL)
+	enqueue(t, q, 1, domain.EventBookingPaid)
+
+	s, c = newTestSender(q, Options{Lease: time.Minute})
+	_, _ = s.SendOnce(context.Background())
+	if d := q.deliveries[0]; d.Status != domain.WebhookDeliveryPending || d.Attempts != 0 || len(q.attempts) != 0 {
+		t.Fatalf("stale lease holder must not record the outcome, got %+v", d)
+	}
+}
@@ -0,0 +1,418 @@
+// Package config loads the application settings once at startup from the
+// environment, an optional config file and .env, and validates them before
+// anything else is constructed.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const (
+	minJWTSecretLength = 32
+	// minJWTSecretBits is the least entropy, estimated from how often each
+	// character occurs, of a usable secret. 32 random hex digits have about
+	// 120 bits; a few characters typed over and over have far fewer.
+	minJWTSecretBits = 96
+)
+
+// weakSecrets are placeholder values, from this repo's docs and examples,
+// that must never sign real tokens.
+var weakSecrets = map[string]bool{
+	"rahasia_negara_sagara":            true,
+	"your_jwt_secret":                  true,
+	"your_jwt_secret_key_min_32_chars": true,
+}
+
+type Config struct {
+	Port       string
+	TrustProxy bool
+	// LogLevel is debug, info, warn or error.
+	LogLevel string
+	// ShutdownTimeout bounds how long in-flight requests may drain after
+	// SIGTERM. Heroku kills the dyno 30 seconds after sending it.
+	ShutdownTimeout time.Duration
+	// RequestTimeout is the deadline given to each request's context.
+	RequestTimeout time.Duration
+	// BookingPaymentWindow is how long a pending booking holds its slot
+	// before it expires; zero keeps pending bookings forever.
+	BookingPaymentWindow time.Duration
+	// BookingReminderOffsets are how long before a paid booking starts a
+	// reminder is sent; empty disables reminders.
+	BookingReminderOffsets []time.Duration
+	// WaitlistHold is how long a freed slot is held for the waitlisted
+	// customer it is offered to.
+	WaitlistHold time.Duration
+	// SlotHoldMax is the longest a customer can hold a slot during
+	// checkout, and how long it is held when they do not say.
+	SlotHoldMax time.Duration
+	// DefaultTimeZone is the IANA zone given to fields created without one.
+	DefaultTimeZone string
+	Database        DatabaseConfig
+	JWT             JWTConfig
+	RateLimit       RateLimitConfig
+	Tracing         TracingConfig
+	Notification    NotificationConfig
+	// MetricsToken, when set, must be sent as a bearer token to scrape
+	// /metrics.
+	MetricsToken string
+}
+
+type DatabaseConfig struct {
+	Host     string
+	User     string
+	Password string
+	Name     string
+	Port     string
+	SSLMode  string
+	// SlowQueryThreshold is how long a query may take before it is logged
+	// as slow; zero disables the warning.
+	SlowQueryThreshold time.Duration
+}
+
+// DSN builds the libpq connection string for the gorm Postgres driver. The
+// session always runs in UTC; local times are a presentation concern of
+// each field's time zone.
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
+		d.Host, d.User, d.Password, d.Name, d.Port, d.SSLMode)
+}
+
+type JWTConfig struct {
+	Secret string
+	TTL    time.Duration
+}
+
+type TracingConfig struct {
+	// Exporter is "none" or "otlp".
+	Exporter string
+	// SampleRatio is the fraction of new traces recorded; traces started
+	// upstream follow the caller's sampling decision.
+	SampleRatio float64
+}
+
+type NotificationConfig struct {
+	// Sink is "log", "file" or "smtp".
+	Sink string
+	// Language is the default for customers without a preference: "id" or
+	// "en".
+	Language string
+	// From is the sender address, e.g. "Sagara Booking <no-reply@example.com>".
+	From string
+	// FileDir is where the file sink writes .eml files.
+	FileDir string
+	SMTP    SMTPConfig
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+type RateLimitConfig struct {
+	// Store is "memory" or "postgres".
+	Store string
+}
+
+// Load reads the configuration. Values already in the environment win over
+// CONFIG_FILE (dotenv format), which wins over .env in the working
+// directory. Malformed values are reported; use Validate for the rest.
+func Load() (*Config, error) {
+	if file := os.Getenv("CONFIG_FILE"); file != "" {
+		if err := godotenv.Load(file); err != nil {
+			return nil, fmt.Errorf("config: reading %s: %w", file, err)
+		}
+	}
+	_ = godotenv.Load()
+
+	p := &parser{}
+	cfg := &Config{
+		Port:            p.str("PORT", "8080"),
+		TrustProxy:      p.boolean("TRUST_PROXY", false),
+		LogLevel:        p.str("LOG_LEVEL", "info"),
+		ShutdownTimeout: p.duration("SHUTDOWN_TIMEOUT", 25*time.Second),
+		RequestTimeout:  p.duration("REQUEST_TIMEOUT", 15*time.Second),
+
+		BookingPaymentWindow:   p.duration("BOOKING_PAYMENT_WINDOW", 0),
+		BookingReminderOffsets: p.durations("BOOKING_REMINDER_OFFSETS", []time.Duration{24 * time.Hour, 2 * time.Hour}),
+		WaitlistHold:           p.duration("WAITLIST_HOLD", 15*time.Minute),
+		SlotHoldMax:            p.duration("SLOT_HOLD_MAX", 10*time.Minute),
+		// DB_TIMEZONE is the old name, from when it also set the session zone.
+		DefaultTimeZone: p.str("DEFAULT_TIMEZONE", p.str("DB_TIMEZONE", "Asia/Jakarta")),
+		Database: DatabaseConfig{
+			Host:     p.str("DB_HOST", ""),
+			User:     p.str("DB_USER", ""),
+			Password: p.str("DB_PASSWORD", ""),
+			Name:     p.str("DB_NAME", ""),
+			Port:     p.str("DB_PORT", "5432"),
+			SSLMode:  p.str("DB_SSLMODE", "disable"),
+
+			SlowQueryThreshold: p.duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		},
+		JWT: JWTConfig{
+			Secret: p.str("JWT_SECRET", ""),
+			TTL:    p.duration("JWT_TTL", 72*time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Store: p.str("RATE_LIMIT_STORE", "memory"),
+		},
+		Tracing: TracingConfig{
+			Exporter:    p.str("TRACING_EXPORTER", "none"),
+			SampleRatio: p.float("TRACING_SAMPLE_RATIO", 1),
+		},
+		Notification: NotificationConfig{
+			Sink:     p.str("NOTIFICATION_SINK", "log"),
+			Language: p.str("NOTIFICATION_LANGUAGE", "id"),
+			From:     p.str("NOTIFICATION_FROM", "Sagara Booking <no-reply@localhost>"),
+			FileDir:  p.str("NOTIFICATION_FILE_DIR", "mail"),
+			SMTP: SMTPConfig{
+				Host:     p.str("SMTP_HOST", ""),
+				Port:     p.str("SMTP_PORT", "587"),
+				Username: p.str("SMTP_USERNAME", ""),
+				Password: p.str("SMTP_PASSWORD", ""),
+			},
+		},
+		MetricsToken: p.str("METRICS_TOKEN", ""),
+	}
+	if len(p.errs) > 0 {
+		return nil, errors.Join(p.errs...)
+	}
+	return cfg, nil
+}
+
+// Validate reports every problem at once so a misconfigured deploy can be
+// fixed in one go.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(isPort(c.Port), "PORT must be a TCP port number, got %q", c.Port)
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(c.RequestTimeout > 0, "REQUEST_TIMEOUT must be positive")
+	check(c.BookingPaymentWindow >= 0, "BOOKING_PAYMENT_WINDOW must not be negative")
+	seen := map[time.Duration]bool{}
+	for _, offset := range c.BookingReminderOffsets {
+		check(offset > 0 && !seen[offset], "BOOKING_REMINDER_OFFSETS must be distinct positive durations, got %s", offset)
+		seen[offset] = true
+	}
+	check(c.WaitlistHold > 0, "WAITLIST_HOLD must be positive")
+	check(c.SlotHoldMax >= time.Minute, "SLOT_HOLD_MAX must be at least 1m")
+	_, tzErr := time.LoadLocation(c.DefaultTimeZone)
+	check(c.DefaultTimeZone != "" && tzErr == nil, "DEFAULT_TIMEZONE %q is not a valid IANA time zone", c.DefaultTimeZone)
+	check(oneOf(strings.ToLower(c.LogLevel), "debug", "info", "warn", "error"),
+		"LOG_LEVEL must be debug, info, warn or error, got %q", c.LogLevel)
+
+	check(c.Database.Host != "", "DB_HOST is required")
+	check(c.Database.User != "", "DB_USER is required")
+	check(c.Database.Name != "", "DB_NAME is required")
+	check(isPort(c.Database.Port), "DB_PORT must be a TCP port number, got %q", c.Database.Port)
+	check(oneOf(c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		"DB_SSLMODE %q is not a valid sslmode", c.Database.SSLMode)
+	check(c.Database.SlowQueryThreshold >= 0, "DB_SLOW_QUERY_THRESHOLD must not be negative")
+
+	check(c.JWT.Secret != "", "JWT_SECRET is required")
+	if c.JWT.Secret != "" {
+		check(len(c.JWT.Secret) >= minJWTSecretLength, "JWT_SECRET must be at least %d characters", minJWTSecretLength)
+		check(!weakSecrets[strings.ToLower(c.JWT.Secret)], "JWT_SECRET is a placeholder; generate a random one")
+		check(secretBits(c.JWT.Secret) >= minJWTSecretBits, "JWT_SECRET is too predictable; generate a random one")
+	}
+	check(c.JWT.TTL > 0, "JWT_TTL must be positive")
+
+	check(oneOf(c.RateLimit.Store, "memory", "postgres"), "RATE_LIMIT_STORE must be 'memory' or 'postgres', got %q", c.RateLimit.Store)
+
+	check(oneOf(c.Tracing.Exporter, "none", "otlp"), "TRACING_EXPORTER must be 'none' or 'otlp', got %q", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+
+	n := c.Notification
+	check(oneOf(n.Sink, "log", "file", "smtp"), "NOTIFICATION_SINK must be 'log', 'file' or 'smtp', got %q", n.Sink)
+	check(oneOf(n.Language, "id", "en"), "NOTIFICATION_LANGUAGE must be 'id' or 'en', got %q", n.Language)
+	_, fromErr := mail.ParseAddress(n.From)
+	check(fromErr == nil, "NOTIFICATION_FROM %q is not a valid email address", n.From)
+	if n.Sink == "file" {
+		check(n.FileDir != "", "NOTIFICATION_FILE_DIR is required for the file sink")
+	}
+	if n.Sink == "smtp" {
+		check(n.SMTP.Host != "", "SMTP_HOST is required for the smtp sink")
+		check(isPort(n.SMTP.Port), "SMTP_PORT must be a TCP port number, got %q", n.SMTP.Port)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Print writes the configuration in dotenv form with secrets redacted.
+func (c *Config) Print(w io.Writer) {
+	settings := [][2]string{
+		{"PORT", c.Port},
+		{"TRUST_PROXY", strconv.FormatBool(c.TrustProxy)},
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout.String()},
+		{"REQUEST_TIMEOUT", c.RequestTimeout.String()},
+		{"BOOKING_PAYMENT_WINDOW", c.BookingPaymentWindow.String()},
+		{"BOOKING_REMINDER_OFFSETS", formatDurations(c.BookingReminderOffsets)},
+		{"WAITLIST_HOLD", c.WaitlistHold.String()},
+		{"SLOT_HOLD_MAX", c.SlotHoldMax.String()},
+		{"DEFAULT_TIMEZONE", c.DefaultTimeZone},
+		{"LOG_LEVEL", c.LogLevel},
+		{"DB_HOST", c.Database.Host},
+		{"DB_USER", c.Database.User},
+		{"DB_PASSWORD", redact(c.Database.Password)},
+		{"DB_NAME", c.Database.Name},
+		{"DB_PORT", c.Database.Port},
+		{"DB_SSLMODE", c.Database.SSLMode},
+		{"DB_SLOW_QUERY_THRESHOLD", c.Database.SlowQueryThreshold.String()},
+		{"JWT_SECRET", redact(c.JWT.Secret)},
+		{"JWT_TTL", c.JWT.TTL.String()},
+		{"RATE_LIMIT_STORE", c.RateLimit.Store},
+		{"TRACING_EXPORTER", c.Tracing.Exporter},
+		{"TRACING_SAMPLE_RATIO", strconv.FormatFloat(c.Tracing.SampleRatio, 'g', -1, 64)},
+		{"NOTIFICATION_SINK", c.Notification.Sink},
+		{"NOTIFICATION_LANGUAGE", c.Notification.Language},
+		{"NOTIFICATION_FROM", c.Notification.From},
+		{"NOTIFICATION_FILE_DIR", c.Notification.FileDir},
+		{"SMTP_HOST", c.Notification.SMTP.Host},
+		{"SMTP_PORT", c.Notification.SMTP.Port},
+		{"SMTP_USERNAME", c.Notification.SMTP.Username},
+		{"SMTP_PASSWORD", redact(c.Notification.SMTP.Password)},
+		{"METRICS_TOKEN", redact(c.MetricsToken)},
+	}
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s=%s\n", s[0], s[1])
+	}
+}
+
+// formatDurations is the inverse of parser.durations.
+func formatDurations(ds []time.Duration) string {
+	if len(ds) == 0 {
+		return "0"
+	}
+	parts := make([]string, len(ds))
+	for i, d := range ds {
+		parts[i] = d.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "<redacted>"
+}
+
+func isPort(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && n < 65536
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// secretBits estimates the entropy of secret in bits from the frequency of
+// its characters.
+func secretBits(secret string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range secret {
+		counts[r]++
+		n++
+	}
+	bits := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		bits -= float64(c) * math.Log2(p)
+	}
+	return bits
+}
+
+// parser collects malformed values instead of stopping at the first one.
+type parser struct {
+	errs []error
+}
+
+func (p *parser) str(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+func (p *parser) boolean(key string, fallback bool) bool {
+	v := p.str(key, "")
+	if v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be true or false, got %q", key, v))
+		return fallback
+	}
+	return b
+}
+
+func (p *parser) duration(key string, fallback time.Duration) time.Duration {
+	v := p.str(key, "")
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be a duration such as 72h, got %q", key, v))
+		return fallback
+	}
+	return d
+}
+
+// durations reads a comma-separated list such as "24h,2h"; "0" is the
+// empty list.
+func (p *parser) durations(key string, fallback []time.Duration) []time.Duration {
+	v := p.str(key, "")
+	if v == "" {
+		return fallback
+	}
+	if v == "0" {
+		return nil
+	}
+	var ds []time.Duration
+	for _, part := range strings.Split(v, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			p.errs = append(p.errs, fmt.Errorf("%s must be a comma-separated list of durations such as 24h,2h, got %q", key, v))
+			return fallback
+		}
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+func (p *parser) float(key string, fallback float64) float64 {
+	v := p.str(key, "")
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be a number, got %q", key, v))
+		return fallback
+	}
+	return f
+}
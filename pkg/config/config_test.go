@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setValidEnv(t *testing.T) {
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USER", "postgres")
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("DB_NAME", "sagara_booking")
+	t.Setenv("JWT_SECRET", "k3v9Q2x8ZpL4mN7rT1wY6bH5cJ0dF3gS")
+}
+
+func TestLoad_DefaultsAndValidation(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	if cfg.Port != "8080" || cfg.Database.Port != "5432" || cfg.JWT.TTL != 72*time.Hour || cfg.RateLimit.Store != "memory" {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestValidate_RejectsMissingAndWeakSecrets(t *testing.T) {
+	setValidEnv(t)
+
+	for _, secret := range []string{"", "short", "YOUR_JWT_SECRET_KEY_MIN_32_CHARS", "abababababababababababababababab"} {
+		t.Setenv("JWT_SECRET", secret)
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load error: %v", err)
+		}
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+			t.Fatalf("secret %q: expected JWT_SECRET error, got %v", secret, err)
+		}
+	}
+
+	// Random secrets pass whatever they happen to contain.
+	for _, secret := range []string{"789ec5b1f32d2e604ebe9218b103acf9", "x9Secret-Q2vL7pZ4mN8rT1wY6bH5cJ0d"} {
+		t.Setenv("JWT_SECRET", secret)
+		cfg, _ := Load()
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("secret %q: expected it to be accepted, got %v", secret, err)
+		}
+	}
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("DB_HOST", "")
+	t.Setenv("DB_PORT", "abc")
+	t.Setenv("RATE_LIMIT_STORE", "redis")
+	t.Setenv("DEFAULT_TIMEZONE", "Mars/Olympus")
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("REQUEST_TIMEOUT", "0s")
+	t.Setenv("BOOKING_PAYMENT_WINDOW", "-1m")
+	t.Setenv("BOOKING_REMINDER_OFFSETS", "2h,2h")
+	t.Setenv("WAITLIST_HOLD", "0s")
+	t.Setenv("SLOT_HOLD_MAX", "30s")
+	t.Setenv("TRACING_EXPORTER", "jaeger")
+	t.Setenv("TRACING_SAMPLE_RATIO", "1.5")
+	t.Setenv("NOTIFICATION_LANGUAGE", "fr")
+	t.Setenv("NOTIFICATION_SINK", "smtp")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	err = cfg.Validate()
+	for _, key := range []string{"DB_HOST", "DB_PORT", "RATE_LIMIT_STORE", "DEFAULT_TIMEZONE", "LOG_LEVEL", "REQUEST_TIMEOUT", "BOOKING_PAYMENT_WINDOW", "BOOKING_REMINDER_OFFSETS", "WAITLIST_HOLD", "SLOT_HOLD_MAX", "TRACING_EXPORTER", "TRACING_SAMPLE_RATIO", "NOTIFICATION_LANGUAGE", "SMTP_HOST"} {
+		if err == nil || !strings.Contains(err.Error(), key) {
+			t.Fatalf("expected %s in %v", key, err)
+		}
+	}
+}
+
+func TestLoad_ReminderOffsets(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, _ := Load()
+	if len(cfg.BookingReminderOffsets) != 2 || cfg.BookingReminderOffsets[0] != 24*time.Hour {
+		t.Fatalf("unexpected default offsets %v", cfg.BookingReminderOffsets)
+	}
+
+	t.Setenv("BOOKING_REMINDER_OFFSETS", "48h, 30m")
+	cfg, _ = Load()
+	if len(cfg.BookingReminderOffsets) != 2 || cfg.BookingReminderOffsets[1] != 30*time.Minute {
+		t.Fatalf("unexpected offsets %v", cfg.BookingReminderOffsets)
+	}
+
+	t.Setenv("BOOKING_REMINDER_OFFSETS", "0")
+	cfg, _ = Load()
+	if len(cfg.BookingReminderOffsets) != 0 || cfg.Validate() != nil {
+		t.Fatalf("expected reminders disabled, got %v", cfg.BookingReminderOffsets)
+	}
+
+	t.Setenv("BOOKING_REMINDER_OFFSETS", "tomorrow")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "BOOKING_REMINDER_OFFSETS") {
+		t.Fatalf("expected malformed offsets reported, got %v", err)
+	}
+}
+
+func TestLoad_DefaultTimeZone(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, _ := Load()
+	if cfg.DefaultTimeZone != "Asia/Jakarta" || !strings.Contains(cfg.Database.DSN(), "TimeZone=UTC") {
+		t.Fatalf("unexpected zone %q / DSN %q", cfg.DefaultTimeZone, cfg.Database.DSN())
+	}
+
+	// deployments that still set the old name keep their zone
+	t.Setenv("DB_TIMEZONE", "Asia/Makassar")
+	cfg, _ = Load()
+	if cfg.DefaultTimeZone != "Asia/Makassar" || !strings.Contains(cfg.Database.DSN(), "TimeZone=UTC") {
+		t.Fatalf("expected DB_TIMEZONE fallback, got %q", cfg.DefaultTimeZone)
+	}
+	t.Setenv("DEFAULT_TIMEZONE", "Europe/Amsterdam")
+	cfg, _ = Load()
+	if cfg.DefaultTimeZone != "Europe/Amsterdam" {
+		t.Fatalf("DEFAULT_TIMEZONE must win, got %q", cfg.DefaultTimeZone)
+	}
+}
+
+func TestLoad_MalformedValues(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("TRUST_PROXY", "sometimes")
+	t.Setenv("JWT_TTL", "3 days")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "TRUST_PROXY") || !strings.Contains(err.Error(), "JWT_TTL") {
+		t.Fatalf("expected both malformed values reported, got %v", err)
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("METRICS_TOKEN", "scrape-me")
+	t.Setenv("SMTP_PASSWORD", "mail-pass")
+	cfg, _ := Load()
+
+	var buf bytes.Buffer
+	cfg.Print(&buf)
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, cfg.JWT.Secret) || strings.Contains(out, "scrape-me") || strings.Contains(out, "mail-pass") {
+		t.Fatalf("secrets leaked:\n%s", out)
+	}
+	if !strings.Contains(out, "DB_PASSWORD=<redacted>") || !strings.Contains(out, "DB_HOST=localhost") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
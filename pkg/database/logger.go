@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Logger adapts gorm's logger to slog. It takes the request-scoped logger
+// from the query's context, so statements run with db.WithContext(ctx) are
+// tagged with the request ID. Statements slower than SlowThreshold are
+// logged at warn, failures at error and everything else only at debug.
+type Logger struct {
+	SlowThreshold time.Duration
+	level         gormlogger.LogLevel
+}
+
+// NewLogger returns a Logger that warns about queries slower than slow.
+func NewLogger(slow time.Duration) *Logger {
+	return &Logger{SlowThreshold: slow, level: gormlogger.Info}
+}
+
+func (l *Logger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		logging.FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		logging.FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		logging.FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	logger := logging.FromContext(ctx)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{"sql", sql, "rows", rows, "duration_ms", float64(elapsed.Microseconds()) / 1000}
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		logger.ErrorContext(ctx, "query failed", append(attrs(), "error", err)...)
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold && l.level >= gormlogger.Warn:
+		logger.WarnContext(ctx, "slow query", append(attrs(), "threshold_ms", l.SlowThreshold.Milliseconds())...)
+	case l.level >= gormlogger.Info:
+		logger.DebugContext(ctx, "query", attrs()...)
+	}
+}
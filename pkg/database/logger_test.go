@@ -0,0 +1,46 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"gorm.io/gorm"
+)
+
+func TestLogger_SlowAndFailedQueries(t *testing.T) {
+	var buf bytes.Buffer
+	base := logging.New(&buf, slog.LevelInfo)
+	ctx := logging.WithRequestID(logging.WithLogger(context.Background(), base), "req-7")
+
+	l := NewLogger(100 * time.Millisecond)
+	sql := func() (string, int64) { return `SELECT * FROM "bookings"`, 3 }
+
+	l.Trace(ctx, time.Now().Add(-10*time.Millisecond), sql, nil)
+	l.Trace(ctx, time.Now(), sql, gorm.ErrRecordNotFound)
+	if buf.Len() != 0 {
+		t.Fatalf("fast queries and missing rows should only log at debug, got:\n%s", buf.String())
+	}
+
+	l.Trace(ctx, time.Now().Add(-250*time.Millisecond), sql, nil)
+	l.Trace(ctx, time.Now(), sql, errors.New("connection reset"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got:\n%s", buf.String())
+	}
+	want := []struct{ level, msg string }{{"WARN", "slow query"}, {"ERROR", "query failed"}}
+	for i, line := range lines {
+		var entry map[string]any
+		_ = json.Unmarshal([]byte(line), &entry)
+		if entry["level"] != want[i].level || entry["msg"] != want[i].msg || entry["request_id"] != "req-7" || entry["sql"] == nil {
+			t.Fatalf("line %d: unexpected entry %s", i, line)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations_EmbeddedFilesAreWellFormed(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatalf("LoadMigrations error: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatalf("expected embedded migrations")
+	}
+
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Fatalf("expected contiguous versions, got %d at position %d", m.Version, i)
+		}
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			t.Fatalf("migration %d_%s has an empty up or down script", m.Version, m.Name)
+		}
+	}
+}
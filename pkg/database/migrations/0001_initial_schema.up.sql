@@ -0,0 +1,39 @@
+-- Baseline matching the schema previously created by gorm AutoMigrate.
+-- IF NOT EXISTS lets databases created that way adopt migrations as-is.
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    name       TEXT,
+    email      TEXT,
+    password   TEXT,
+    role       TEXT DEFAULT 'user',
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS fields (
+    id             BIGSERIAL PRIMARY KEY,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ,
+    name           TEXT,
+    price_per_hour BIGINT,
+    location       TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_fields_deleted_at ON fields (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bookings (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    field_id   BIGINT,
+    user_id    BIGINT,
+    start_time TIMESTAMPTZ,
+    end_time   TIMESTAMPTZ,
+    status     TEXT DEFAULT 'pending'
+);
+CREATE INDEX IF NOT EXISTS idx_bookings_deleted_at ON bookings (deleted_at);
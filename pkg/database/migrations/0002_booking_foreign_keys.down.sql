@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS fk_bookings_user;
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS fk_bookings_field;
+DROP INDEX IF EXISTS idx_bookings_user_id;
+DROP INDEX IF EXISTS idx_bookings_field_id;
+ALTER TABLE bookings ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE bookings ALTER COLUMN field_id DROP NOT NULL;
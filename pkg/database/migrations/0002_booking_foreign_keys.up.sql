@@ -0,0 +1,14 @@
+ALTER TABLE bookings ALTER COLUMN field_id SET NOT NULL;
+ALTER TABLE bookings ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_field_id ON bookings (field_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings (user_id);
+
+-- AutoMigrate may already have created these constraints without the
+-- referential actions, so replace them.
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS fk_bookings_field;
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS fk_bookings_user;
+ALTER TABLE bookings
+    ADD CONSTRAINT fk_bookings_field FOREIGN KEY (field_id) REFERENCES fields (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_bookings_user FOREIGN KEY (user_id) REFERENCES users (id)
+        ON UPDATE CASCADE ON DELETE RESTRICT;
@@ -0,0 +1,2 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_time_range;
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_no_overlap;
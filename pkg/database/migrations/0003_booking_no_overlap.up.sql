@@ -0,0 +1,12 @@
+-- Enforce the overlap rule in the database so two concurrent requests can
+-- never both book the same slot. Only active bookings take part.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+        field_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+    ) WHERE (status IN ('pending', 'paid') AND deleted_at IS NULL);
+
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_time_range CHECK (end_time > start_time);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_records (
+    key             VARCHAR(320) PRIMARY KEY,
+    request_hash    VARCHAR(64) NOT NULL,
+    status          VARCHAR(16) NOT NULL,
+    response_status BIGINT NOT NULL DEFAULT 0,
+    content_type    VARCHAR(128),
+    response_body   BYTEA,
+    locked_at       TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_records_expires_at ON idempotency_records (expires_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookings_pending_created_at;
+DROP TABLE IF EXISTS outbox_events;
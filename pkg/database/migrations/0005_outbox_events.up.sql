@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id              BIGSERIAL PRIMARY KEY,
+    event_type      VARCHAR(64) NOT NULL,
+    aggregate_id    BIGINT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until    TIMESTAMPTZ,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ
+);
+-- The dispatcher only ever scans pending events in due order.
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events (next_attempt_at, id) WHERE status = 'pending';
+
+-- Unpaid bookings are expired oldest first.
+CREATE INDEX IF NOT EXISTS idx_bookings_pending_created_at ON bookings (created_at) WHERE status = 'pending';
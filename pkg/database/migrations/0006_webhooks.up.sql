@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id                   BIGSERIAL PRIMARY KEY,
+    url                  TEXT NOT NULL,
+    secret               TEXT NOT NULL,
+    event_types          JSONB NOT NULL,
+    description          TEXT,
+    active               BOOLEAN NOT NULL DEFAULT true,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_at          TIMESTAMPTZ,
+    disabled_reason      TEXT,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        BIGINT NOT NULL,
+    event_type      VARCHAR(64) NOT NULL,
+    payload         JSONB NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until    TIMESTAMPTZ,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at    TIMESTAMPTZ,
+    -- The outbox delivers at least once; an event is queued once per subscription.
+    CONSTRAINT webhook_deliveries_event_unique UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_log ON webhook_deliveries (subscription_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    id              BIGSERIAL PRIMARY KEY,
+    delivery_id     BIGINT NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    response_body   TEXT,
+    error           TEXT,
+    duration_ms     BIGINT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_attempts_delivery_id ON webhook_attempts (delivery_id);
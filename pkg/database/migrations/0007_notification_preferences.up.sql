@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id                   BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    language                  VARCHAR(8) NOT NULL,
+    opt_out_non_transactional BOOLEAN NOT NULL DEFAULT false,
+    updated_at                TIMESTAMPTZ NOT NULL DEFAULT now()
+);
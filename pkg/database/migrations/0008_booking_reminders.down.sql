@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookings_paid_start_time;
+DROP TABLE IF EXISTS booking_reminders;
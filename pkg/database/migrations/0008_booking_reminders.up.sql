@@ -0,0 +1,11 @@
+-- One row per reminder scheduled; the primary key is what keeps several
+-- instances from scheduling the same reminder twice.
+CREATE TABLE IF NOT EXISTS booking_reminders (
+    booking_id     BIGINT NOT NULL REFERENCES bookings (id) ON DELETE CASCADE,
+    offset_seconds BIGINT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (booking_id, offset_seconds)
+);
+
+-- Reminders are looked up among paid bookings by start time.
+CREATE INDEX IF NOT EXISTS idx_bookings_paid_start_time ON bookings (start_time) WHERE status = 'paid';
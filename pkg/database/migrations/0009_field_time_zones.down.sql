@@ -0,0 +1,3 @@
+ALTER TABLE fields DROP COLUMN IF EXISTS close_time;
+ALTER TABLE fields DROP COLUMN IF EXISTS open_time;
+ALTER TABLE fields DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,6 @@
+-- Times were always written through sessions in DB_TIMEZONE (Asia/Jakarta
+-- by default) into TIMESTAMPTZ columns, so the stored instants are already
+-- correct; only the zone each field is shown and scheduled in is new.
+ALTER TABLE fields ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta';
+ALTER TABLE fields ADD COLUMN IF NOT EXISTS open_time VARCHAR(5) NOT NULL DEFAULT '00:00';
+ALTER TABLE fields ADD COLUMN IF NOT EXISTS close_time VARCHAR(5) NOT NULL DEFAULT '24:00';
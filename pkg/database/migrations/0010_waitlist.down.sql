@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS waitlist_entries;
+DROP TABLE IF EXISTS slot_holds;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS slot_holds (
+    id         BIGSERIAL PRIMARY KEY,
+    field_id   BIGINT NOT NULL REFERENCES fields (id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time   TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT slot_holds_time_range CHECK (end_time > start_time)
+);
+CREATE INDEX IF NOT EXISTS idx_slot_holds_field_time ON slot_holds (field_id, start_time, end_time);
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id              BIGSERIAL PRIMARY KEY,
+    field_id        BIGINT NOT NULL REFERENCES fields (id) ON DELETE CASCADE,
+    user_id         BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    start_time      TIMESTAMPTZ NOT NULL,
+    end_time        TIMESTAMPTZ NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'waiting',
+    hold_id         BIGINT REFERENCES slot_holds (id) ON DELETE SET NULL,
+    hold_expires_at TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT waitlist_entries_time_range CHECK (end_time > start_time)
+);
+-- A customer can wait for the same slot only once at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS uni_waitlist_entries_active
+    ON waitlist_entries (user_id, field_id, start_time, end_time)
+    WHERE status IN ('waiting', 'offered');
+-- Freed slots are offered in join order.
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_waiting
+    ON waitlist_entries (field_id, created_at) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_offered
+    ON waitlist_entries (hold_expires_at) WHERE status = 'offered';
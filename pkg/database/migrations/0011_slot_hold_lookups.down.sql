@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_slot_holds_expires;
+DROP INDEX IF EXISTS idx_slot_holds_user_expires;
@@ -0,0 +1,3 @@
+-- Checkout holds are counted per customer and swept once expired.
+CREATE INDEX IF NOT EXISTS idx_slot_holds_user_expires ON slot_holds (user_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_slot_holds_expires ON slot_holds (expires_at);
@@ -0,0 +1,2 @@
+-- Reports scan bookings by the day they start on.
+CREATE INDEX IF NOT EXISTS idx_bookings_start_time ON bookings (start_time) WHERE deleted_at IS NULL;
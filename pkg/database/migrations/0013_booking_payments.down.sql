@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS no_show_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS expired_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS paid_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS amount_paid;
+ALTER TABLE bookings DROP COLUMN IF EXISTS payment_reference;
@@ -0,0 +1,24 @@
+-- What finance reconciles a booking by: the payment reference and amount
+-- taken when it was paid, and when it reached each status.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS payment_reference VARCHAR(100);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS amount_paid BIGINT;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS paid_at TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS expired_at TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS no_show_at TIMESTAMPTZ;
+
+-- Earlier status changes are dated by the events they published.
+UPDATE bookings b SET paid_at = e.at
+FROM (SELECT aggregate_id, MIN(created_at) AS at FROM outbox_events WHERE event_type = 'booking.paid' GROUP BY aggregate_id) e
+WHERE e.aggregate_id = b.id AND b.paid_at IS NULL;
+UPDATE bookings b SET cancelled_at = e.at
+FROM (SELECT aggregate_id, MIN(created_at) AS at FROM outbox_events WHERE event_type = 'booking.cancelled' GROUP BY aggregate_id) e
+WHERE e.aggregate_id = b.id AND b.status = 'cancelled' AND b.cancelled_at IS NULL;
+UPDATE bookings b SET expired_at = e.at
+FROM (SELECT aggregate_id, MIN(created_at) AS at FROM outbox_events WHERE event_type = 'booking.expired' GROUP BY aggregate_id) e
+WHERE e.aggregate_id = b.id AND b.status = 'expired' AND b.expired_at IS NULL;
+
+-- Bookings paid so far were charged the field's current price.
+UPDATE bookings b SET amount_paid = f.price_per_hour * (EXTRACT(EPOCH FROM b.end_time - b.start_time)::bigint / 60) / 60
+FROM fields f
+WHERE f.id = b.field_id AND b.amount_paid IS NULL AND (b.paid_at IS NOT NULL OR b.status IN ('paid', 'no_show'));
@@ -0,0 +1,13 @@
+-- Each customer and each field has at most one secret calendar feed URL.
+-- Only a SHA-256 hash of the URL's token is stored.
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT REFERENCES users (id) ON DELETE CASCADE,
+    field_id   BIGINT REFERENCES fields (id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT calendar_feeds_one_owner CHECK ((user_id IS NULL) <> (field_id IS NULL))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uni_calendar_feeds_token_hash ON calendar_feeds (token_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS uni_calendar_feeds_user ON calendar_feeds (user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uni_calendar_feeds_field ON calendar_feeds (field_id) WHERE field_id IS NOT NULL;
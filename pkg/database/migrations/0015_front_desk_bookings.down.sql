@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS override_reason;
+ALTER TABLE bookings DROP COLUMN IF EXISTS overrides;
+ALTER TABLE bookings DROP COLUMN IF EXISTS created_by;
+ALTER TABLE bookings DROP COLUMN IF EXISTS payment_method;
+DROP INDEX IF EXISTS uni_users_guest_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
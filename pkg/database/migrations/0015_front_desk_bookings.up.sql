@@ -0,0 +1,11 @@
+-- Walk-in and phone customers are guests: users without an email or
+-- password, told apart by phone number.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(32);
+CREATE UNIQUE INDEX IF NOT EXISTS uni_users_guest_phone ON users (phone) WHERE role = 'guest' AND deleted_at IS NULL;
+
+-- Bookings the front desk takes record who took them, how they were paid
+-- and which rules were waived, for what reason.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS payment_method VARCHAR(16);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS created_by BIGINT REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS overrides VARCHAR(100);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS override_reason VARCHAR(500);
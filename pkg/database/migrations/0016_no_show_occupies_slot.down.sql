@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_no_overlap;
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+        field_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+    ) WHERE (status IN ('pending', 'paid') AND deleted_at IS NULL);
@@ -0,0 +1,9 @@
+-- A no-show's slot stays paid for, so it keeps the slot like a paid
+-- booking. Fails, naming the bookings, if a slot was booked again after
+-- its booking was marked a no-show; cancel one of them and retry.
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_no_overlap;
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+        field_id WITH =,
+        tstzrange(start_time, end_time) WITH &&
+    ) WHERE (status IN ('pending', 'paid', 'no_show') AND deleted_at IS NULL);
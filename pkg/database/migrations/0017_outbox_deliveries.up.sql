@@ -0,0 +1,8 @@
+-- The subscribers that have handled each outbox event, so a retry only runs
+-- the ones that failed.
+CREATE TABLE IF NOT EXISTS outbox_deliveries (
+    event_id     BIGINT NOT NULL REFERENCES outbox_events (id) ON DELETE CASCADE,
+    subscriber   VARCHAR(64) NOT NULL,
+    delivered_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (event_id, subscriber)
+);
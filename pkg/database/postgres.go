@@ -1,42 +1,31 @@
 package database
 
 import (
-	"fmt"
-	"log"
-	"os"
+	"log/slog"
 
-	"github.com/HIUNCY/sagara-booking-api/internal/core/domain"
+	"github.com/HIUNCY/sagara-booking-api/pkg/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectDB() (*gorm.DB, error) {
-	sslMode := os.Getenv("DB_SSLMODE")
-	if sslMode == "" {
-		sslMode = "disable"
-	}
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=Asia/Jakarta",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-		sslMode,
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+func ConnectDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		Logger: NewLogger(cfg.SlowQueryThreshold),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	log.Println("✅ Database Connected to Neon Tech!")
+	slog.Info("database connected", "host", cfg.Host, "name", cfg.Name)
 
-	err = db.AutoMigrate(&domain.User{}, &domain.Field{}, &domain.Booking{})
+	return db, nil
+}
+
+// Close releases every pooled connection.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	log.Println("✅ Database Migrated!")
-
-	return db, nil
+	return sqlDB.Close()
 }
@@ -0,0 +1,143 @@
+// Package health runs readiness checks for load balancers and uptime
+// monitors.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc reports whether one dependency is usable.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is one check's outcome. Only the status is public; the error
+// and duration may name hosts or driver internals and are for logs.
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"-"`
+	DurationMS int64  `json:"-"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker runs registered checks concurrently, each bounded by timeout.
+type Checker struct {
+	timeout time.Duration
+	mu      sync.RWMutex
+	checks  []namedCheck
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, fn: fn})
+}
+
+// Run executes every check. The report is ok only if all checks pass.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check namedCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := runCheck(checkCtx, check.fn)
+			result := CheckResult{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			report.Checks[check.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+	return report
+}
+
+// runCheck returns when fn finishes or ctx expires, whichever comes first,
+// so a check that ignores its context cannot hang the probe.
+func runCheck(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out: %w", ctx.Err())
+	}
+}
+
+// DatabasePing checks that a pooled connection to Postgres answers.
+func DatabasePing(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Heartbeat is beaten by a background worker on every loop iteration; it is
+// healthy while the last beat is younger than maxAge.
+type Heartbeat struct {
+	maxAge time.Duration
+	mu     sync.Mutex
+	last   time.Time
+}
+
+func NewHeartbeat(maxAge time.Duration) *Heartbeat {
+	return &Heartbeat{maxAge: maxAge, last: time.Now()}
+}
+
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	h.last = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *Heartbeat) Check(ctx context.Context) error {
+	h.mu.Lock()
+	age := time.Since(h.last)
+	h.mu.Unlock()
+	if age > h.maxAge {
+		return fmt.Errorf("last heartbeat %s ago", age.Round(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Package idempotency stores the outcome of requests carrying an
+// Idempotency-Key header so that client retries can be answered with the
+// original response instead of being executed twice.
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// ErrNotOwner is returned when completing or releasing a record that was
+// taken over by another request in the meantime.
+var ErrNotOwner = errors.New("idempotency record is no longer owned by this request")
+
+// Record is one remembered request and, once completed, its response.
+type Record struct {
+	Key            string    `gorm:"primaryKey;size:320"`
+	RequestHash    string    `gorm:"size:64;not null"`
+	Status         string    `gorm:"size:16;not null"`
+	ResponseStatus int       `gorm:"not null;default:0"`
+	ContentType    string    `gorm:"size:128"`
+	ResponseBody   []byte    `gorm:""`
+	LockedAt       time.Time `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_records"
+}
+
+// Store persists records. Begin either claims key for the caller, returning
+// a new in-progress record with started set, or returns the record that
+// already exists. Expired records, and in-progress records whose lock is
+// older than lockTimeout (the owner most likely crashed), are replaced.
+type Store interface {
+	Begin(key, requestHash string, now time.Time, ttl, lockTimeout time.Duration) (rec *Record, started bool, err error)
+	Complete(key string, lockedAt time.Time, status int, contentType string, body []byte) error
+	// Release forgets an in-progress record so the request can be retried.
+	Release(key string, lockedAt time.Time) error
+}
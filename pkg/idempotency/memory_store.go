@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps records in process memory. It is only suitable for a
+// single instance and for tests.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]*Record{}}
+}
+
+func (s *MemoryStore) Begin(key, requestHash string, now time.Time, ttl, lockTimeout time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, rec := range s.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.records, k)
+		}
+	}
+
+	if rec, ok := s.records[key]; ok && !replaceable(rec, now, lockTimeout) {
+		copied := *rec
+		return &copied, false, nil
+	}
+
+	rec := &Record{
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      StatusInProgress,
+		LockedAt:    now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	s.records[key] = rec
+	copied := *rec
+	return &copied, true, nil
+}
+
+func (s *MemoryStore) Complete(key string, lockedAt time.Time, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok || rec.Status != StatusInProgress || !rec.LockedAt.Equal(lockedAt) {
+		return ErrNotOwner
+	}
+	rec.Status = StatusCompleted
+	rec.ResponseStatus = status
+	rec.ContentType = contentType
+	rec.ResponseBody = append([]byte(nil), body...)
+	return nil
+}
+
+func (s *MemoryStore) Release(key string, lockedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[key]
+	if !ok || rec.Status != StatusInProgress || !rec.LockedAt.Equal(lockedAt) {
+		return ErrNotOwner
+	}
+	delete(s.records, key)
+	return nil
+}
+
+func replaceable(rec *Record, now time.Time, lockTimeout time.Duration) bool {
+	if !rec.ExpiresAt.After(now) {
+		return true
+	}
+	return rec.Status == StatusInProgress && now.Sub(rec.LockedAt) > lockTimeout
+}
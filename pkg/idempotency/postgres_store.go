@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore keeps records in the idempotency_records table so retries
+// landing on a different dyno are still recognised. The primary key makes
+// claiming a key atomic across instances.
+type PostgresStore struct {
+	db    *gorm.DB
+	calls atomic.Uint64
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Begin(key, requestHash string, now time.Time, ttl, lockTimeout time.Duration) (*Record, bool, error) {
+	// Postgres keeps microseconds; truncate so LockedAt round-trips exactly.
+	now = now.Truncate(time.Microsecond)
+	if s.calls.Add(1)%1000 == 0 {
+		go s.sweep(now)
+	}
+
+	var rec Record
+	started := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Clear the way if the existing record expired or its owner vanished.
+		err := tx.Where("key = ?", key).
+			Where("expires_at <= ? OR (status = ? AND locked_at < ?)", now, StatusInProgress, now.Add(-lockTimeout)).
+			Delete(&Record{}).Error
+		if err != nil {
+			return err
+		}
+
+		fresh := Record{
+			Key:         key,
+			RequestHash: requestHash,
+			Status:      StatusInProgress,
+			LockedAt:    now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&fresh)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			rec, started = fresh, true
+			return nil
+		}
+		return tx.First(&rec, "key = ?", key).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &rec, started, nil
+}
+
+func (s *PostgresStore) Complete(key string, lockedAt time.Time, status int, contentType string, body []byte) error {
+	result := s.db.Model(&Record{}).
+		Where("key = ? AND status = ? AND locked_at = ?", key, StatusInProgress, lockedAt).
+		Updates(map[string]interface{}{
+			"status":          StatusCompleted,
+			"response_status": status,
+			"content_type":    contentType,
+			"response_body":   body,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+func (s *PostgresStore) Release(key string, lockedAt time.Time) error {
+	result := s.db.Where("key = ? AND status = ? AND locked_at = ?", key, StatusInProgress, lockedAt).Delete(&Record{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// sweep removes records past their retention window.
+func (s *PostgresStore) sweep(now time.Time) {
+	if err := s.db.Where("expires_at <= ?", now).Delete(&Record{}).Error; err != nil {
+		slog.Warn("idempotency: failed to sweep expired records", "error", err)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/routes"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels requests that did not hit a registered route so
+// scanners probing random paths cannot blow up label cardinality.
+const unmatchedRoute = "unmatched"
+
+// Middleware records the count and latency of every request, labelled by
+// route template (e.g. /api/bookings/:id) rather than the raw path. Errors
+// from later handlers are rendered here with the app's error handler so the
+// final status code is recorded; mount it before the other middleware.
+func (m *Metrics) Middleware() fiber.Handler {
+	var (
+		once     sync.Once
+		handlers map[string]bool
+	)
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		// Routes are all registered by the time the first request arrives.
+		once.Do(func() { handlers = routes.Handlers(c.App()) })
+
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				return handlerErr
+			}
+		}
+
+		// c.Method() aliases a pooled buffer; labels outlive the request.
+		method := utils.CopyString(c.Method())
+		path := c.Route().Path
+		if !handlers[method+" "+path] {
+			// Fell through every handler; Route() is the last Use() middleware.
+			path = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Response().StatusCode())
+
+		m.httpRequests.WithLabelValues(method, path, status).Inc()
+		m.httpDuration.WithLabelValues(method, path, status).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// Handler serves the registry in the Prometheus exposition format. When
+// token is non-empty, scrapers must send it as a bearer token.
+func (m *Metrics) Handler(token string) fiber.Handler {
+	serve := adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	return func(c *fiber.Ctx) error {
+		if token != "" {
+			given := []byte(c.Get(fiber.HeaderAuthorization))
+			if subtle.ConstantTimeCompare(given, []byte("Bearer "+token)) != 1 {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+			}
+		}
+		return serve(c)
+	}
+}
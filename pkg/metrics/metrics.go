@@ -0,0 +1,108 @@
+// Package metrics defines the Prometheus collectors for HTTP traffic,
+// database queries and booking business events. Collectors are registered
+// on an injected registry so tests can use a fresh one and assert values.
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const namespace = "sagara"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	dbDuration   *prometheus.HistogramVec
+
+	bookingsCreated  prometheus.Counter
+	bookingConflicts prometheus.Counter
+	payments         *prometheus.CounterVec
+	bookingsExpired  prometheus.Counter
+}
+
+// New creates the collectors and registers them, along with the Go runtime
+// and process collectors, on reg.
+func New(reg *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: reg,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by gorm operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		bookingsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_created_total",
+			Help:      "Bookings successfully created.",
+		}),
+		bookingConflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "booking_conflicts_total",
+			Help:      "Booking attempts rejected because the slot was taken.",
+		}),
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payments_total",
+			Help:      "Payment attempts by result (succeeded, failed).",
+		}, []string{"result"}),
+		bookingsExpired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_expired_total",
+			Help:      "Pending bookings that expired without payment.",
+		}),
+	}
+
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.dbDuration,
+		m.bookingsCreated, m.bookingConflicts, m.payments, m.bookingsExpired,
+	)
+	// Pre-create both results so dashboards see zeros instead of gaps.
+	m.payments.WithLabelValues("succeeded")
+	m.payments.WithLabelValues("failed")
+	return m
+}
+
+// Gatherer exposes the registry for the /metrics endpoint.
+func (m *Metrics) Gatherer() prometheus.Gatherer {
+	return m.registry
+}
+
+// RegisterDB times every gorm query and exports connection pool statistics.
+func (m *Metrics) RegisterDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := db.Use(&gormPlugin{duration: m.dbDuration}); err != nil {
+		return err
+	}
+	return m.registry.Register(collectors.NewDBStatsCollector(sqlDB, "postgres"))
+}
+
+func (m *Metrics) BookingCreated(ctx context.Context)  { m.bookingsCreated.Inc() }
+func (m *Metrics) BookingConflict(ctx context.Context) { m.bookingConflicts.Inc() }
+func (m *Metrics) PaymentSucceeded(ctx context.Context) {
+	m.payments.WithLabelValues("succeeded").Inc()
+}
+func (m *Metrics) PaymentFailed(ctx context.Context)  { m.payments.WithLabelValues("failed").Inc() }
+func (m *Metrics) BookingExpired(ctx context.Context) { m.bookingsExpired.Inc() }
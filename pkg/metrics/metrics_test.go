@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newTestApp(m *Metrics) *fiber.App {
+	app := fiber.New()
+	app.Use(m.Middleware())
+	api := app.Group("/api")
+	api.Get("/bookings/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+	api.Post("/payments", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusConflict, "taken")
+	})
+	api.Get("/boom", func(c *fiber.Ctx) error { return errors.New("boom") })
+	return app
+}
+
+func TestMiddleware_LabelsByRouteTemplateAndStatus(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	app := newTestApp(m)
+
+	for _, path := range []string{"/api/bookings/1", "/api/bookings/2"} {
+		_, _ = app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	_, _ = app.Test(httptest.NewRequest(http.MethodPost, "/api/payments", nil))
+	_, _ = app.Test(httptest.NewRequest(http.MethodGet, "/api/boom", nil))
+	_, _ = app.Test(httptest.NewRequest(http.MethodGet, "/wp-login.php", nil))
+
+	cases := []struct {
+		method, route, status string
+		want                  float64
+	}{
+		{"GET", "/api/bookings/:id", "200", 2},
+		{"POST", "/api/payments", "409", 1},
+		{"GET", "/api/boom", "500", 1},
+		{"GET", unmatchedRoute, "404", 1},
+	}
+	for _, tc := range cases {
+		got := testutil.ToFloat64(m.httpRequests.WithLabelValues(tc.method, tc.route, tc.status))
+		if got != tc.want {
+			t.Errorf("%s %s %s: expected %v, got %v", tc.method, tc.route, tc.status, tc.want, got)
+		}
+	}
+	if n := testutil.CollectAndCount(m.httpRequests); n != len(cases) {
+		t.Errorf("expected %d series, got %d", len(cases), n)
+	}
+}
+
+func TestHandler_RequiresTokenWhenConfigured(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	m.BookingCreated(context.Background())
+
+	app := fiber.New()
+	app.Get("/metrics", m.Handler("scrape-token"))
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-token")
+	resp, _ = app.Test(req)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "sagara_bookings_created_total 1") {
+		t.Fatalf("expected booking counter in output, got:\n%s", body)
+	}
+}
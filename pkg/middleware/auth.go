@@ -1,44 +1,34 @@
 package middleware
 
 import (
-	"fmt"
-	"os"
 	"strings"
 
+	"github.com/HIUNCY/sagara-booking-api/pkg/util"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func Protected(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	if authHeader == "" {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: No token provided"})
-	}
-
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Invalid token format"})
-	}
+func Protected(tokens *util.TokenManager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if authHeader == "" {
+			return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: No token provided"})
+		}
 
-	tokenString := parts[1]
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method")
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Invalid token format"})
 		}
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
 
-	if err != nil || !token.Valid {
-		return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Invalid or expired token"})
-	}
+		claims, err := tokens.ParseToken(parts[1])
+		if err != nil {
+			return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Invalid or expired token"})
+		}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if ok && token.Valid {
 		c.Locals("user_id", claims["user_id"])
 		c.Locals("role", claims["role"])
-	}
 
-	return c.Next()
+		return c.Next()
+	}
 }
 
 func AdminOnly(c *fiber.Ctx) error {
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RealIP resolves the client address once per request. Behind a router that
+// appends to X-Forwarded-For (such as Heroku's), only the right-most entry
+// was written by infrastructure we trust; anything to its left is supplied by
+// the client. Enable trustProxy only when such a router sits in front.
+func RealIP(trustProxy bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ip := c.IP()
+		if trustProxy {
+			if forwarded := c.Get(fiber.HeaderXForwardedFor); forwarded != "" {
+				parts := strings.Split(forwarded, ",")
+				if last := strings.TrimSpace(parts[len(parts)-1]); last != "" {
+					ip = last
+				}
+			}
+		}
+		c.Locals("client_ip", ip)
+		return c.Next()
+	}
+}
+
+// ClientIP returns the address resolved by RealIP, falling back to the
+// socket peer when the middleware is not installed.
+func ClientIP(c *fiber.Ctx) string {
+	if ip, ok := c.Locals("client_ip").(string); ok && ip != "" {
+		return ip
+	}
+	return c.IP()
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/idempotency"
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	idempotencyHeader      = "Idempotency-Key"
+	idempotencyMaxKeyLen   = 255
+	idempotencyTTL         = 24 * time.Hour
+	idempotencyLockTimeout = time.Minute
+)
+
+// Idempotency replays the stored response when a request is retried with the
+// same Idempotency-Key. Keys are scoped to the caller and the route, and a
+// retry must carry the same body as the original. Requests still running get
+// 409, and 5xx responses are not stored so the client may try again. It must
+// be mounted after Protected.
+func Idempotency(store idempotency.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(idempotencyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > idempotencyMaxKeyLen {
+			return c.Status(400).JSON(fiber.Map{"error": "Idempotency-Key must be at most 255 characters"})
+		}
+
+		scopedKey := callerKey(c) + ":" + c.Method() + ":" + c.Path() + ":" + key
+		sum := sha256.Sum256(c.Body())
+		requestHash := hex.EncodeToString(sum[:])
+
+		rec, started, err := store.Begin(scopedKey, requestHash, time.Now(), idempotencyTTL, idempotencyLockTimeout)
+		if err != nil {
+			logging.FromContext(c.UserContext()).Warn("idempotency store failed", "error", err)
+			return c.Status(503).JSON(fiber.Map{"error": "Unable to process Idempotency-Key, please retry"})
+		}
+
+		if !started {
+			switch {
+			case rec.RequestHash != requestHash:
+				return c.Status(422).JSON(fiber.Map{"error": "Idempotency-Key was already used with a different request body"})
+			case rec.Status != idempotency.StatusCompleted:
+				c.Set(fiber.HeaderRetryAfter, "1")
+				return c.Status(409).JSON(fiber.Map{"error": "A request with this Idempotency-Key is still being processed"})
+			}
+			c.Set("Idempotent-Replayed", "true")
+			if rec.ContentType != "" {
+				c.Set(fiber.HeaderContentType, rec.ContentType)
+			}
+			return c.Status(rec.ResponseStatus).Send(rec.ResponseBody)
+		}
+
+		// Render errors here rather than in the app's error handler so the
+		// final response can be captured and stored.
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				_ = store.Release(scopedKey, rec.LockedAt)
+				return handlerErr
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if status >= 500 {
+			if err := store.Release(scopedKey, rec.LockedAt); err != nil {
+				logging.FromContext(c.UserContext()).Warn("idempotency release failed", "key", key, "error", err)
+			}
+			return nil
+		}
+		contentType := string(c.Response().Header.ContentType())
+		if err := store.Complete(scopedKey, rec.LockedAt, status, contentType, c.Response().Body()); err != nil {
+			logging.FromContext(c.UserContext()).Warn("idempotency complete failed", "key", key, "error", err)
+		}
+		return nil
+	}
+}
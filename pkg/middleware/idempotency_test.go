@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/idempotency"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIdempotency_ReplayAndMismatch(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Post("/bookings", func(c *fiber.Ctx) error {
+		c.Locals("user_id", float64(1))
+		return c.Next()
+	}, Idempotency(idempotency.NewMemoryStore()), func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(http.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	send := func(key, body string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp, _ := app.Test(req)
+		b, _ := io.ReadAll(resp.Body)
+		return resp, string(b)
+	}
+
+	resp, first := send("k1", `{"field_id":1}`)
+	if resp.StatusCode != http.StatusCreated || calls != 1 {
+		t.Fatalf("expected first call to run, got %d calls=%d", resp.StatusCode, calls)
+	}
+
+	// retry is replayed without running the handler
+	resp, replay := send("k1", `{"field_id":1}`)
+	if resp.StatusCode != http.StatusCreated || calls != 1 || replay != first {
+		t.Fatalf("expected replay, got %d calls=%d body=%s", resp.StatusCode, calls, replay)
+	}
+	if resp.Header.Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected replay header")
+	}
+
+	// same key with a different body is rejected
+	resp, _ = send("k1", `{"field_id":2}`)
+	if resp.StatusCode != http.StatusUnprocessableEntity || calls != 1 {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+
+	// requests without a key are never deduplicated
+	send("", `{"field_id":1}`)
+	send("", `{"field_id":1}`)
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestIdempotency_InProgressAndServerErrors(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	fail := true
+	app := fiber.New()
+	app.Post("/payments", Idempotency(store), func(c *fiber.Ctx) error {
+		if fail {
+			return errors.New("database unavailable")
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/payments", bytes.NewReader([]byte(`{"booking_id":1}`)))
+		req.Header.Set("Idempotency-Key", "pay-1")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+
+	// a 5xx is not stored, so the retry runs again
+	if status := send(); status != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", status)
+	}
+	fail = false
+	if status := send(); status != http.StatusOK {
+		t.Fatalf("expected retry to run and succeed, got %d", status)
+	}
+
+	// a concurrent duplicate of a request still in flight gets 409
+	req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+	req.Header.Set("Idempotency-Key", "pay-2")
+	app2 := fiber.New()
+	app2.Post("/payments", Idempotency(store), func(c *fiber.Ctx) error {
+		dup := httptest.NewRequest(http.MethodPost, "/payments", nil)
+		dup.Header.Set("Idempotency-Key", "pay-2")
+		resp, _ := app.Test(dup)
+		return c.SendStatus(resp.StatusCode)
+	})
+	resp, _ := app2.Test(req)
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected nested duplicate to get 409, got %d", resp.StatusCode)
+	}
+}
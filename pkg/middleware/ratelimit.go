@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/HIUNCY/sagara-booking-api/pkg/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimitPolicy configures RateLimit for one group of routes.
+type RateLimitPolicy struct {
+	// Name namespaces the buckets so policies never share counters.
+	Name  string
+	Limit ratelimit.Limit
+	Store ratelimit.Store
+}
+
+// RateLimit enforces a token bucket per caller. Authenticated callers are
+// keyed by the user_id set by Protected, everyone else by client IP, so it
+// must be mounted after Protected on authenticated routes. Store failures
+// are logged and the request is let through.
+func RateLimit(policy RateLimitPolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := policy.Name + ":" + callerKey(c)
+
+		res, err := policy.Store.Take(key, policy.Limit, time.Now())
+		if err != nil {
+			logging.FromContext(c.UserContext()).Warn("rate limit store failed", "policy", policy.Name, "error", err)
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			return c.Status(429).JSON(fiber.Map{"error": "Too many requests, please slow down"})
+		}
+		return c.Next()
+	}
+}
+
+// callerKey identifies the caller by user ID when authenticated, otherwise
+// by client IP.
+func callerKey(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("user_id").(float64); ok {
+		return fmt.Sprintf("user:%d", uint(userID))
+	}
+	return "ip:" + ClientIP(c)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/ratelimit"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRateLimit_HeadersAndKeying(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := RateLimit(RateLimitPolicy{Name: "test", Limit: ratelimit.Every(1, time.Minute, 2), Store: store})
+
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		if uid := c.Get("X-Test-User"); uid == "7" {
+			c.Locals("user_id", float64(7))
+		}
+		return c.Next()
+	}, limit, func(c *fiber.Ctx) error { return c.SendStatus(http.StatusCreated) })
+
+	send := func(user string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if user != "" {
+			req.Header.Set("X-Test-User", user)
+		}
+		resp, _ := app.Test(req)
+		return resp
+	}
+
+	resp := send("7")
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("RateLimit-Limit") != "2" || resp.Header.Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("unexpected headers: %v", resp.Header)
+	}
+
+	send("7")
+	resp = send("7")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") != "60" {
+		t.Fatalf("expected Retry-After 60, got %q", resp.Header.Get("Retry-After"))
+	}
+
+	// anonymous callers are keyed by IP and have their own bucket
+	if resp := send(""); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected anonymous request to pass, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID reuses a well-formed X-Request-ID from the caller or generates
+// one, echoes it in the response and stores a logger tagged with it in the
+// request's user context. Mount it first so every later log line carries it.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(fiber.HeaderXRequestID)
+		if !validRequestID(id) {
+			id = newRequestID()
+		} else {
+			// Header values alias a pooled buffer; the context outlives it.
+			id = utils.CopyString(id)
+		}
+
+		c.Set(fiber.HeaderXRequestID, id)
+		c.Locals("request_id", id)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), id))
+		return c.Next()
+	}
+}
+
+// AccessLog writes one structured line per request. Errors from later
+// handlers are rendered with the app's error handler first so the logged
+// status is the one the client receives.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				return handlerErr
+			}
+		}
+
+		logging.FromContext(c.UserContext()).Info("request",
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", c.Response().StatusCode(),
+			"duration_ms", time.Since(start).Milliseconds(),
+			"ip", ClientIP(c),
+		)
+		return nil
+	}
+}
+
+// validRequestID accepts short IDs of letters, digits and -_.: so a client
+// cannot inject arbitrary text into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequestID_AcceptsOrGenerates(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(logging.RequestID(c.UserContext()))
+	})
+
+	send := func(header string) (string, string) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set("X-Request-ID", header)
+		}
+		resp, _ := app.Test(req)
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(resp.Body)
+		return resp.Header.Get("X-Request-ID"), body.String()
+	}
+
+	if echoed, seen := send("abc-123"); echoed != "abc-123" || seen != "abc-123" {
+		t.Fatalf("expected caller's ID to be kept, got header %q context %q", echoed, seen)
+	}
+	for _, bad := range []string{"", "has spaces", "line\nbreak", strings.Repeat("a", 129)} {
+		echoed, seen := send(bad)
+		if echoed == "" || echoed == bad || echoed != seen {
+			t.Fatalf("%q: expected a generated ID, got header %q context %q", bad, echoed, seen)
+		}
+	}
+}
+
+func TestAccessLog_TagsLinesWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	app := fiber.New()
+	app.Use(RequestID(), AccessLog())
+	app.Get("/bookings/:id", func(c *fiber.Ctx) error {
+		logging.FromContext(c.UserContext()).Info("inside handler")
+		return fiber.ErrNotFound
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/bookings/7", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	resp, _ := app.Test(req)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("not JSON: %s", line)
+		}
+		if entry["request_id"] != "req-42" {
+			t.Fatalf("expected request_id on every line, got %s", line)
+		}
+	}
+	var access map[string]any
+	_ = json.Unmarshal([]byte(lines[1]), &access)
+	if access["msg"] != "request" || access["status"] != float64(404) || access["path"] != "/bookings/7" {
+		t.Fatalf("unexpected access line: %s", lines[1])
+	}
+}
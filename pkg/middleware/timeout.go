@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Timeout gives each request a deadline through its user context. Handlers
+// pass c.UserContext() down to the repositories, so a slow query is
+// cancelled by the database driver once the deadline passes and the client
+// gets a 503 instead of waiting indefinitely.
+func Timeout(d time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+			logging.FromContext(ctx).Warn("request timed out",
+				"method", c.Method(), "path", c.Path(), "timeout", d.String(), "error", err)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Request timed out, please try again"})
+		}
+		return err
+	}
+}
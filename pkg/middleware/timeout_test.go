@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestTimeout_Returns503OnDeadline(t *testing.T) {
+	app := fiber.New()
+	app.Use(Timeout(20 * time.Millisecond))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		// Stands in for a query that honours the context, as pgx does.
+		select {
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		case <-time.After(time.Second):
+			return c.SendString("too late")
+		}
+	})
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		if _, ok := c.UserContext().Deadline(); !ok {
+			return errors.New("no deadline on user context")
+		}
+		return c.SendString("ok")
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return fiber.NewError(http.StatusTeapot, "teapot")
+	})
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"/slow", http.StatusServiceUnavailable},
+		{"/fast", http.StatusOK},
+		{"/broken", http.StatusTeapot},
+	}
+	for _, tc := range cases {
+		start := time.Now()
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.path, err)
+		}
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%s: expected %d, got %d", tc.path, tc.status, resp.StatusCode)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Fatalf("%s: took %s, deadline not enforced", tc.path, elapsed)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryStore keeps buckets in process memory. Limits are per instance, so it
+// suits local development and single-dyno deployments.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	calls   int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}}
+}
+
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	tokens, res := take(b.tokens, b.last, now, limit)
+	b.tokens, b.last = tokens, now
+	return res, nil
+}
+
+// sweep drops buckets idle for a day; they would have refilled long ago.
+// Callers must hold s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	s.calls++
+	if s.calls%1000 != 0 {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.last) > 24*time.Hour {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Every(1, time.Second, 3)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// burst is available immediately
+	for i := 0; i < 3; i++ {
+		res, _ := store.Take("k", limit, now)
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("take %d: unexpected result %+v", i, res)
+		}
+	}
+
+	// then the bucket is empty
+	res, _ := store.Take("k", limit, now)
+	if res.Allowed {
+		t.Fatalf("expected denial once the burst is spent")
+	}
+	if res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Fatalf("unexpected retry/reset: %+v", res)
+	}
+
+	// other keys are independent
+	if res, _ := store.Take("other", limit, now); !res.Allowed {
+		t.Fatalf("expected other key to be allowed")
+	}
+
+	// a token refills after one second
+	res, _ = store.Take("k", limit, now.Add(time.Second))
+	if !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("expected refilled token, got %+v", res)
+	}
+
+	// refill never exceeds the burst
+	res, _ = store.Take("k", limit, now.Add(time.Hour))
+	if !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("expected full bucket minus one, got %+v", res)
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bucket is the persisted state of one token bucket.
+type Bucket struct {
+	Key       string    `gorm:"primaryKey"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index;autoUpdateTime:false"`
+}
+
+func (Bucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every dyno
+// enforces the same limit. Each Take locks the bucket row for the duration
+// of a short transaction.
+type PostgresStore struct {
+	db    *gorm.DB
+	calls atomic.Uint64
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	var res Result
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		fresh := Bucket{Key: key, Tokens: float64(limit.Burst), UpdatedAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&fresh).Error; err != nil {
+			return err
+		}
+
+		var b Bucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&b, "key = ?", key).Error; err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, res = take(b.Tokens, b.UpdatedAt, now, limit)
+		return tx.Model(&Bucket{}).Where("key = ?", key).
+			Updates(map[string]interface{}{"tokens": tokens, "updated_at": now}).Error
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	if s.calls.Add(1)%1000 == 0 {
+		go s.sweep(now)
+	}
+	return res, nil
+}
+
+// sweep removes buckets idle for a day; they would have refilled long ago.
+func (s *PostgresStore) sweep(now time.Time) {
+	if err := s.db.Where("updated_at < ?", now.Add(-24*time.Hour)).Delete(&Bucket{}).Error; err != nil {
+		slog.Warn("rate limit: failed to sweep idle buckets", "error", err)
+	}
+}
@@ -0,0 +1,60 @@
+// Package ratelimit implements token bucket rate limiting over pluggable
+// storage so limits can be shared across several application instances.
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Limit describes a token bucket: it refills at Rate tokens per second and
+// holds at most Burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Every builds a Limit allowing n requests per period with the given burst.
+func Every(n int, period time.Duration, burst int) Limit {
+	return Limit{Rate: float64(n) / period.Seconds(), Burst: burst}
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available; zero when allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store atomically takes one token from the bucket identified by key.
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
+
+// take refills a bucket that held tokens at last and tries to consume one at
+// now. It returns the new token count together with the result.
+func take(tokens float64, last, now time.Time, limit Limit) (float64, Result) {
+	burst := float64(limit.Burst)
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(burst, tokens+elapsed*limit.Rate)
+	}
+
+	res := Result{Limit: limit.Burst}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = secondsToDuration((burst - tokens) / limit.Rate)
+	return tokens, res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
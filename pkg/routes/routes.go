@@ -0,0 +1,16 @@
+// Package routes tells the route a Fiber request was served by from the
+// Use() middleware it fell through to.
+package routes
+
+import "github.com/gofiber/fiber/v2"
+
+// Handlers indexes app's non-middleware routes by "METHOD path". Routes are
+// all registered by the time the first request arrives, so middleware can
+// build it then.
+func Handlers(app *fiber.App) map[string]bool {
+	routes := make(map[string]bool)
+	for _, r := range app.GetRoutes(true) {
+		routes[r.Method+" "+r.Path] = true
+	}
+	return routes
+}
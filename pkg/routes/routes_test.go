@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandlers_SkipsMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error { return c.Next() })
+	app.Get("/api/bookings/:id", func(c *fiber.Ctx) error { return nil })
+
+	routes := Handlers(app)
+	if !routes["GET /api/bookings/:id"] {
+		t.Fatalf("expected the booking route, got %v", routes)
+	}
+	if routes["USE /"] || routes["GET /"] {
+		t.Fatalf("expected no middleware routes, got %v", routes)
+	}
+}
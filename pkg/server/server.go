@@ -0,0 +1,84 @@
+// Package server runs the HTTP server and background workers and shuts them
+// down in order when the process is asked to stop.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Run serves app on ln until ctx is cancelled, then stops accepting new
+// connections and gives in-flight requests up to timeout to finish. It
+// returns nil after a clean drain, or an error if the server failed or the
+// deadline passed with requests still running.
+func Run(ctx context.Context, app *fiber.App, ln net.Listener, timeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- app.Listener(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped unexpectedly: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down: draining in-flight requests", "timeout", timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown: %w", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
+
+// Workers runs background loops that share a context cancelled on shutdown.
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in its own goroutine. fn must return once ctx is cancelled.
+func (w *Workers) Go(name string, fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+		slog.Info("worker stopped", "worker", name)
+	}()
+}
+
+// Stop cancels all workers and waits up to timeout for them to return.
+func (w *Workers) Stop(timeout time.Duration) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("background workers did not stop in time")
+	}
+}
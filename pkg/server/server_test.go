@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRun_DrainsInFlightRequest(t *testing.T) {
+	started := make(chan struct{})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Post("/bookings", func(c *fiber.Ctx) error {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		return c.Status(http.StatusCreated).SendString("booked")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- Run(ctx, app, ln, 5*time.Second) }()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Post(addr+"/bookings", "application/json", nil)
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		resCh <- result{status: resp.StatusCode, body: string(b)}
+	}()
+
+	// signal shutdown while the request is still being handled
+	<-started
+	cancel()
+
+	res := <-resCh
+	if res.err != nil || res.status != http.StatusCreated || res.body != "booked" {
+		t.Fatalf("in-flight request did not complete: %+v", res)
+	}
+	if err := <-runErr; err != nil {
+		t.Fatalf("expected clean shutdown, got %v", err)
+	}
+
+	// new connections are refused after shutdown
+	if _, err := http.Post(addr+"/bookings", "application/json", nil); err == nil {
+		t.Fatalf("expected server to stop accepting connections")
+	}
+}
+
+func TestRun_DeadlineExceeded(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.SendStatus(http.StatusOK)
+	})
+	defer close(release)
+
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- Run(ctx, app, ln, 100*time.Millisecond) }()
+
+	go http.Get("http://" + ln.Addr().String() + "/slow")
+	<-started
+	cancel()
+
+	if err := <-runErr; err == nil {
+		t.Fatalf("expected an error when requests outlive the deadline")
+	}
+}
+
+func TestWorkers_Stop(t *testing.T) {
+	w := NewWorkers()
+	stopped := make(chan struct{})
+	w.Go("ticker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	if err := w.Stop(time.Second); err != nil {
+		t.Fatalf("stop error: %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Fatalf("worker was not stopped")
+	}
+
+	stuck := NewWorkers()
+	block := make(chan struct{})
+	defer close(block)
+	stuck.Go("stuck", func(ctx context.Context) { <-block })
+	if err := stuck.Stop(50 * time.Millisecond); err == nil {
+		t.Fatalf("expected timeout for a worker ignoring cancellation")
+	}
+}
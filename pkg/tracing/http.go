@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/logging"
+	"github.com/HIUNCY/sagara-booking-api/pkg/routes"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request, continuing the trace
+// from an incoming traceparent header, and stores it in the request's user
+// context so service and gorm spans become its children. The span is named
+// after the route template once routing has happened. Errors from later
+// handlers are rendered with the app's error handler so the span records
+// the final status; mount it after RequestID and before everything else.
+func Middleware() fiber.Handler {
+	var (
+		once     sync.Once
+		handlers map[string]bool
+	)
+	return func(c *fiber.Ctx) error {
+		once.Do(func() { handlers = routes.Handlers(c.App()) })
+
+		method := utils.CopyString(c.Method())
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+		ctx, span := Tracer().Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(utils.CopyString(c.Path())),
+			),
+		)
+		defer span.End()
+
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("trace_id", sc.TraceID().String()))
+		}
+		c.SetUserContext(ctx)
+
+		if err := c.Next(); err != nil {
+			span.RecordError(err)
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				span.SetStatus(codes.Error, handlerErr.Error())
+				return handlerErr
+			}
+		}
+
+		route := c.Route().Path
+		// Requests that fell through to a Use() handler keep the bare
+		// method name.
+		if handlers[method+" "+route] {
+			span.SetName(method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		status := c.Response().StatusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+		return nil
+	}
+}
+
+// headerCarrier exposes Fiber request headers to OTel propagators.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(k, _ []byte) {
+		keys = append(keys, string(k))
+	})
+	return keys
+}
@@ -0,0 +1,72 @@
+// Package tracing wires OpenTelemetry: the global tracer provider and W3C
+// propagator, a Fiber middleware that starts a server span per request and
+// a gorm plugin that adds a child span per SQL statement.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HIUNCY/sagara-booking-api/pkg/buildinfo"
+	"github.com/HIUNCY/sagara-booking-api/pkg/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// ScopeName identifies spans created by this application's own code.
+const ScopeName = "github.com/HIUNCY/sagara-booking-api"
+
+const defaultServiceName = "sagara-booking-api"
+
+// Tracer returns the application tracer from the global provider. It is
+// looked up on every call so tests can swap the provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. With the "none" exporter spans are not recorded, but incoming
+// traceparent headers are still honoured. The OTLP exporter reads its
+// endpoint and headers from the standard OTEL_EXPORTER_OTLP_* variables.
+// The returned function flushes pending spans and must be called on exit.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	if cfg.Exporter == "none" {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: creating OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(defaultServiceName),
+			semconv.ServiceVersion(buildinfo.Get().Version),
+		),
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the above.
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: building resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
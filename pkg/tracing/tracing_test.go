@@ -0,0 +1,147 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// useRecorder installs an in-memory exporter for the duration of the test.
+func useRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return exporter
+}
+
+// dryRunDB builds SQL without a database server so gorm spans can be
+// asserted in unit tests.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := db.Use(GormPlugin{}); err != nil {
+		t.Fatalf("use: %v", err)
+	}
+	return db
+}
+
+type booking struct {
+	ID uint
+}
+
+func TestMiddleware_SpansHandlerServiceAndSQL(t *testing.T) {
+	exporter := useRecorder(t)
+	db := dryRunDB(t)
+
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/api/bookings/:id", func(c *fiber.Ctx) error {
+		ctx, span := Tracer().Start(c.UserContext(), "BookingService.GetBookingByID")
+		defer span.End()
+		var b booking
+		db.WithContext(ctx).First(&b, c.Params("id"))
+		return c.SendString("ok")
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/bookings/7", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	server, ok := byName["GET /api/bookings/:id"]
+	if !ok {
+		t.Fatalf("no server span, got %v", spanNames(spans))
+	}
+	service := byName["BookingService.GetBookingByID"]
+	query, ok := byName["gorm.query bookings"]
+	if !ok {
+		t.Fatalf("no gorm span, got %v", spanNames(spans))
+	}
+
+	if server.SpanContext.TraceID().String() != traceID {
+		t.Fatalf("incoming traceparent not continued: %s", server.SpanContext.TraceID())
+	}
+	if server.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("server span should be a child of the remote parent, got %s", server.Parent.SpanID())
+	}
+	if service.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Fatal("service span is not a child of the server span")
+	}
+	if query.Parent.SpanID() != service.SpanContext.SpanID() {
+		t.Fatal("gorm span is not a child of the service span")
+	}
+}
+
+func TestMiddleware_MarksServerErrors(t *testing.T) {
+	exporter := useRecorder(t)
+
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/boom", func(c *fiber.Ctx) error { return fiber.ErrServiceUnavailable })
+
+	resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/boom", nil))
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", resp.StatusCode)
+	}
+	_, _ = app.Test(httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %v", spanNames(spans))
+	}
+	if spans[0].Name != "GET /boom" || spans[0].Status.Code != codes.Error {
+		t.Fatalf("expected failed GET /boom span, got %s %v", spans[0].Name, spans[0].Status)
+	}
+	if spans[1].Name != "GET" {
+		t.Fatalf("unmatched request should not be named after a route, got %q", spans[1].Name)
+	}
+}
+
+func TestGormPlugin_SkipsQueriesWithoutSpan(t *testing.T) {
+	exporter := useRecorder(t)
+	db := dryRunDB(t)
+
+	var b booking
+	db.First(&b, 1)
+	if n := len(exporter.GetSpans()); n != 0 {
+		t.Fatalf("expected no spans for a query without a parent, got %d", n)
+	}
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	names := make([]string, len(spans))
+	for i, s := range spans {
+		names[i] = s.Name
+	}
+	return names
+}
@@ -1,19 +1,59 @@
 package util
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateToken(userID uint, role string) (string, error) {
+// TokenManager signs and verifies the API's HS256 access tokens with a
+// secret supplied by the configuration.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenManager(secret string, ttl time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), ttl: ttl}
+}
+
+func (m *TokenManager) GenerateToken(ctx context.Context, userID uint, role string) (string, error) {
+	if len(m.secret) == 0 {
+		return "", errors.New("jwt secret is not configured")
+	}
+
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
-		"exp":     time.Now().Add(time.Hour * 72).Unix(),
+		"exp":     time.Now().Add(m.ttl).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return token.SignedString(m.secret)
+}
+
+// ParseToken validates the signature and expiry and returns the claims.
+func (m *TokenManager) ParseToken(tokenString string) (jwt.MapClaims, error) {
+	if len(m.secret) == 0 {
+		return nil, errors.New("jwt secret is not configured")
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return m.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims, nil
 }
@@ -1,15 +1,14 @@
 package util
 
 import (
-    "os"
+    "context"
     "testing"
-
-    "github.com/golang-jwt/jwt/v5"
+    "time"
 )
 
 func TestGenerateToken(t *testing.T) {
-    os.Setenv("JWT_SECRET", "testsecret")
-    tokenStr, err := GenerateToken(42, "admin")
+    tokens := NewTokenManager("testsecret-testsecret-testsecret-42", time.Hour)
+    tokenStr, err := tokens.GenerateToken(context.Background(), 42, "admin")
     if err != nil {
         t.Fatalf("GenerateToken error: %v", err)
     }
@@ -18,13 +17,23 @@ func TestGenerateToken(t *testing.T) {
     }
 
     // Parse and validate claims
-    parsed, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            t.Fatalf("unexpected signing method")
-        }
-        return []byte(os.Getenv("JWT_SECRET")), nil
-    })
-    if err != nil || !parsed.Valid {
+    claims, err := tokens.ParseToken(tokenStr)
+    if err != nil {
         t.Fatalf("token not valid: %v", err)
     }
+    if claims["user_id"] != float64(42) || claims["role"] != "admin" {
+        t.Fatalf("unexpected claims: %v", claims)
+    }
+
+    // a token signed with another secret is rejected
+    other := NewTokenManager("another-secret-another-secret-4242", time.Hour)
+    if _, err := other.ParseToken(tokenStr); err == nil {
+        t.Fatalf("expected token signed with a different secret to be rejected")
+    }
+}
+
+func TestGenerateToken_EmptySecretRefused(t *testing.T) {
+    if _, err := NewTokenManager("", time.Hour).GenerateToken(context.Background(), 1, "user"); err == nil {
+        t.Fatalf("expected an error when the secret is empty")
+    }
 }
@@ -0,0 +1,141 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LoginGuardPolicy describes how aggressively repeated login failures for a
+// single key (an IP address or an account) are throttled.
+type LoginGuardPolicy struct {
+	// FreeAttempts is the number of failures allowed before any delay kicks in.
+	FreeAttempts int
+	// BaseDelay is the first backoff; each further failure doubles it.
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential backoff.
+	MaxDelay time.Duration
+	// LockoutThreshold is the number of failures that locks the key out
+	// for LockoutDuration regardless of the backoff.
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// ResetAfter forgets a key's failures after this long without a new one.
+	ResetAfter time.Duration
+}
+
+var (
+	// DefaultAccountPolicy throttles a single email address.
+	DefaultAccountPolicy = LoginGuardPolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		ResetAfter:       time.Hour,
+	}
+	// DefaultIPPolicy throttles a single client address, which may be shared
+	// by many users behind the same NAT, so it is more lenient.
+	DefaultIPPolicy = LoginGuardPolicy{
+		FreeAttempts:     10,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 50,
+		LockoutDuration:  15 * time.Minute,
+		ResetAfter:       time.Hour,
+	}
+)
+
+type loginAttempts struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// LoginGuard tracks failed logins per key in memory and tells callers how
+// long a key must wait before it may try again.
+type LoginGuard struct {
+	policy  LoginGuardPolicy
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*loginAttempts
+	calls   int
+}
+
+func NewLoginGuard(policy LoginGuardPolicy) *LoginGuard {
+	return &LoginGuard{
+		policy:  policy,
+		now:     time.Now,
+		entries: map[string]*loginAttempts{},
+	}
+}
+
+// Check returns how long key is still blocked for, or zero if it may attempt
+// a login now.
+func (g *LoginGuard) Check(ctx context.Context, key string) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.sweep(now)
+
+	entry, ok := g.entries[key]
+	if !ok || !entry.blockedUntil.After(now) {
+		return 0
+	}
+	return entry.blockedUntil.Sub(now)
+}
+
+// Fail records a failed attempt for key and returns the resulting block.
+func (g *LoginGuard) Fail(ctx context.Context, key string) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	entry, ok := g.entries[key]
+	if !ok || now.Sub(entry.lastFailure) > g.policy.ResetAfter {
+		entry = &loginAttempts{}
+		g.entries[key] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+
+	var delay time.Duration
+	switch {
+	case g.policy.LockoutThreshold > 0 && entry.failures >= g.policy.LockoutThreshold:
+		delay = g.policy.LockoutDuration
+	case entry.failures > g.policy.FreeAttempts:
+		delay = g.policy.BaseDelay
+		for i := g.policy.FreeAttempts + 1; i < entry.failures && delay < g.policy.MaxDelay; i++ {
+			delay *= 2
+		}
+		if delay > g.policy.MaxDelay {
+			delay = g.policy.MaxDelay
+		}
+	}
+
+	if until := now.Add(delay); until.After(entry.blockedUntil) {
+		entry.blockedUntil = until
+	}
+	return entry.blockedUntil.Sub(now)
+}
+
+// Reset forgets all failures for key, e.g. after a successful login.
+func (g *LoginGuard) Reset(ctx context.Context, key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.entries, key)
+}
+
+// sweep drops stale entries every so often so the map cannot grow without
+// bound under a spray of distinct keys. Callers must hold g.mu.
+func (g *LoginGuard) sweep(now time.Time) {
+	g.calls++
+	if g.calls%1000 != 0 {
+		return
+	}
+	for key, entry := range g.entries {
+		if now.Sub(entry.lastFailure) > g.policy.ResetAfter && !entry.blockedUntil.After(now) {
+			delete(g.entries, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLoginGuard_BackoffAndLockout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	g := NewLoginGuard(LoginGuardPolicy{
+		FreeAttempts:     2,
+		BaseDelay:        time.Second,
+		MaxDelay:         4 * time.Second,
+		LockoutThreshold: 6,
+		LockoutDuration:  time.Minute,
+		ResetAfter:       time.Hour,
+	})
+	g.now = func() time.Time { return now }
+
+	// free attempts are not delayed
+	for i := 0; i < 2; i++ {
+		if d := g.Fail(ctx, "k"); d != 0 {
+			t.Fatalf("attempt %d: expected no delay, got %v", i+1, d)
+		}
+	}
+
+	// then the delay doubles up to the cap
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		if d := g.Fail(ctx, "k"); d != want {
+			t.Fatalf("backoff step %d: expected %v, got %v", i, want, d)
+		}
+		if d := g.Check(ctx, "k"); d != want {
+			t.Fatalf("check step %d: expected %v, got %v", i, want, d)
+		}
+		now = now.Add(want)
+	}
+
+	// reaching the threshold locks the key out
+	if d := g.Fail(ctx, "k"); d != time.Minute {
+		t.Fatalf("expected lockout, got %v", d)
+	}
+	now = now.Add(30 * time.Second)
+	if d := g.Check(ctx, "k"); d != 30*time.Second {
+		t.Fatalf("expected 30s remaining, got %v", d)
+	}
+	if d := g.Check(ctx, "other"); d != 0 {
+		t.Fatalf("other keys must not be affected, got %v", d)
+	}
+
+	// reset clears the key
+	g.Reset(ctx, "k")
+	if d := g.Check(ctx, "k"); d != 0 {
+		t.Fatalf("expected no block after reset, got %v", d)
+	}
+}
+
+func TestLoginGuard_ForgetsOldFailures(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	g := NewLoginGuard(LoginGuardPolicy{FreeAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Minute, ResetAfter: time.Minute})
+	g.now = func() time.Time { return now }
+
+	g.Fail(ctx, "k")
+	now = now.Add(2 * time.Minute)
+	if d := g.Fail(ctx, "k"); d != 0 {
+		t.Fatalf("expected failures to be forgotten, got %v", d)
+	}
+}
@@ -1,9 +1,20 @@
 package util
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const passwordCost = 14
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
 
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), passwordCost)
 	return string(bytes), err
 }
 
@@ -11,3 +22,21 @@ func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// PrepareDummyPassword computes the throwaway hash CheckDummyPassword
+// compares against, so that the first unknown email to log in is not slowed
+// down by hashing it. Call it before serving logins.
+func PrepareDummyPassword() {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("sagara-dummy-password"), passwordCost)
+	})
+}
+
+// CheckDummyPassword spends the same bcrypt work as CheckPasswordHash against
+// a throwaway hash. Login calls it for unknown emails so response timing does
+// not reveal which accounts exist. It always returns false.
+func CheckDummyPassword(password string) bool {
+	PrepareDummyPassword()
+	_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+	return false
+}